@@ -0,0 +1,74 @@
+// Command anomaly-replay runs the anomaly detector over recorded health
+// samples and prints the anomaly events it finds, for tuning the detector
+// against fixtures without a database or Redis.
+//
+// Samples are read as JSON lines in the shape cached under
+// health:timeseries:<id>, from the given file or stdin:
+//
+//	anomaly-replay -threshold 4 samples.jsonl
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Prof-Rosario-UCLA/team15/internal"
+)
+
+func main() {
+	cfg := internal.DefaultAnomalyConfig
+	flag.Float64Var(&cfg.Threshold, "threshold", cfg.Threshold, "score at or above which a sample is anomalous")
+	flag.Int64Var(&cfg.Warmup, "warmup", cfg.Warmup, "samples before scoring starts")
+	flag.Float64Var(&cfg.Alpha, "alpha", cfg.Alpha, "weight of each sample in the short-term baseline")
+	flag.Float64Var(&cfg.SeasonAlpha, "season-alpha", cfg.SeasonAlpha, "weight of the latest week in the seasonal baseline")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	byService := map[string][]internal.HealthMetricModel{}
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var m internal.HealthMetricModel
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			fmt.Fprintf(os.Stderr, "line %d: %v\n", n, err)
+			os.Exit(1)
+		}
+		byService[m.ServiceID] = append(byService[m.ServiceID], m)
+	}
+	if err := sc.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ids := make([]string, 0, len(byService))
+	for id := range byService {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := json.NewEncoder(os.Stdout)
+	for _, id := range ids {
+		samples := byService[id]
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].Timestamp < samples[j].Timestamp })
+		for _, ev := range internal.ReplayAnomalies(cfg, samples) {
+			out.Encode(ev)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Command audit-verify checks the hash chain of the audit log. It connects
+// with the same DB_* variables as the server and exits non-zero at the
+// first event whose hash or link to the event before it does not match.
+//
+// Pass -head with a hash printed by an earlier run to also check that no
+// events up to that one were removed from the end of the log.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Prof-Rosario-UCLA/team15/internal"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	head := flag.String("head", "", "hash of an earlier latest event that must still be in the chain")
+	flag.Parse()
+
+	dsn := internal.PostgresDSN(getEnv("DB_HOST", "localhost"), getEnv("DB_USER", "team15"),
+		getEnv("DB_PASSWORD", "team15"), getEnv("DB_NAME", "team15"), getEnv("DB_PORT", "5432"))
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "connect to Postgres:", err)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	n, latest, err := internal.VerifyAuditChain(ctx, db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "audit chain broken after %d valid events: %v\n", n, err)
+		os.Exit(1)
+	}
+	if *head != "" {
+		var count int64
+		if err := db.Model(&internal.AuditEventModel{}).Where("hash = ?", *head).Count(&count).Error; err != nil {
+			fmt.Fprintln(os.Stderr, "look up head:", err)
+			os.Exit(2)
+		}
+		if count == 0 {
+			fmt.Fprintf(os.Stderr, "event with hash %s is no longer in the chain\n", *head)
+			os.Exit(1)
+		}
+	}
+	fmt.Printf("%d events verified, latest hash %s\n", n, latest)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -2,14 +2,23 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/Prof-Rosario-UCLA/team15/internal"
 )
 
-// jwtKey should be a long, random string in a real application.
-var jwtKey = []byte("my-super-secret-key")
+// loginGuard locks out usernames and IPs that keep failing; set in main.
+var loginGuard *internal.LoginGuard
+
+// accounts holds the users table and issues tokens; set in main.
+var accounts *internal.Accounts
+
+// authenticator checks passwords: the local users, after LDAP when it is
+// configured; set in main.
+var authenticator internal.Authenticator
 
 type Credentials struct {
 	Username string `json:"username"`
@@ -24,43 +33,29 @@ func loginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// In a real application, you would check credentials against a database.
-	// Here, we are using hardcoded credentials as per the project plan.
-	if creds.Username != "admin" || creds.Password != "secret" {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	internal.SetAuditResource(r.Context(), "user", creds.Username)
+
+	ip := internal.HTTPClientIP(r)
+	if wait := loginGuard.Locked(r.Context(), ip, creds.Username); wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+		http.Error(w, "Too many failed login attempts", http.StatusTooManyRequests)
 		return
 	}
 
-	// Create JWT with 1h expiry
-	expirationTime := time.Now().Add(1 * time.Hour)
-	claims := &jwt.RegisteredClaims{
-		Subject:   creds.Username,
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
+	user, err := authenticator.Authenticate(r.Context(), creds.Username, creds.Password)
+	if errors.Is(err, internal.ErrInvalidCredentials) {
+		internal.Logger(r.Context()).Warn("login failed", "username", creds.Username)
+		loginGuard.Failed(r.Context(), ip, creds.Username)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
 	if err != nil {
-		http.Error(w, "Token generation failed", http.StatusInternalServerError)
+		internal.Logger(r.Context()).Error("login check failed", "error", err)
+		http.Error(w, "Authentication unavailable", http.StatusServiceUnavailable)
 		return
 	}
 
-	cookie := &http.Cookie{
-		Name:     "access_token",
-		Value:    tokenString,
-		Expires:  expirationTime,
-		HttpOnly: true, // Important for security
-		Path:     "/",  // Cookie is valid for all paths
-		SameSite: http.SameSiteLaxMode,
-		Secure:   false, // Should be true in production (HTTPS)
-	}
-	http.SetCookie(w, cookie)
-
-	// Also return the token in the response body for gRPC-Web usage
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
-		"token":   tokenString,
-		"message": "Login successful",
-	})
+	// Users with MFA get a challenge to complete at /login/mfa; everyone
+	// else gets the access token straight away.
+	accounts.BeginLogin(w, r, user)
 }
@@ -2,15 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/http" // Added for HTTP server
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	alertingpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/alerting/v1"
+	auditpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/audit/v1"
 	catalogpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1"
 	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	incidentpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/incident/v1"
+	maintenancepb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/maintenance/v1"
+	notificationpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/notification/v1"
+	slopb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/slo/v1"
 	"github.com/Prof-Rosario-UCLA/team15/internal"
 	"github.com/go-chi/chi/v5" // Added for Chi router
 	"github.com/redis/go-redis/v9"
@@ -35,6 +45,13 @@ type HealthServer struct {
 var secretKey = []byte("my-super-secret-key") // Added for JWT
 
 func main() {
+	// 0) Structured JSON logging; LOG_LEVEL can later be changed via /admin/loglevel
+	logger := internal.NewLogger(os.Stdout)
+	slog.SetDefault(logger)
+	if err := internal.LogLevel.UnmarshalText([]byte(getEnv("LOG_LEVEL", "info"))); err != nil {
+		logger.Warn("invalid LOG_LEVEL, defaulting to info", "error", err)
+	}
+
 	// 1) Connect to Postgres via GORM
 	dbHost := getEnv("DB_HOST", "localhost")
 	dbUser := getEnv("DB_USER", "team15")
@@ -42,20 +59,18 @@ func main() {
 	dbName := getEnv("DB_NAME", "team15")
 	dbPort := getEnv("DB_PORT", "5432")
 
-	var dsn string
-	if strings.Contains(dbHost, ":") {
-		// Cloud SQL connection name format: project:region:instance
-		dsn = fmt.Sprintf("host=/cloudsql/%s user=%s password=%s dbname=%s sslmode=disable",
-			dbHost, dbUser, dbPassword, dbName)
-	} else {
-		// Local/Docker connection
-		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-			dbHost, dbUser, dbPassword, dbName, dbPort)
-	}
+	dsn := internal.PostgresDSN(dbHost, dbUser, dbPassword, dbName, dbPort)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// Postgres may still be starting (or briefly unreachable), so keep
+	// retrying with backoff instead of exiting
+	ctx := context.Background()
+	var db *gorm.DB
+	err := internal.Retry(ctx, "connect to Postgres", time.Second, 30*time.Second, func() (err error) {
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		return err
+	})
 	if err != nil {
-		log.Fatalf("failed to connect to Postgres: %v", err)
+		fatal("failed to connect to Postgres", err)
 	}
 
 	// 2) Connect to Redis
@@ -64,22 +79,94 @@ func main() {
 	redisAddr := fmt.Sprintf("%s:%s", redisHost, redisPort)
 
 	redisClient := redis.NewClient(&redis.Options{
-		Addr:     redisAddr,
-		Password: "", // no password
-		DB:       0,  // default DB
+		Addr:         redisAddr,
+		Password:     "", // no password
+		DB:           0,  // default DB
+		DialTimeout:  time.Second,
+		ReadTimeout:  500 * time.Millisecond,
+		WriteTimeout: 500 * time.Millisecond,
+	})
+	// Redis is only a cache: while it is down every command fails fast and
+	// callers fall back to Postgres
+	redisClient.AddHook(internal.RedisBreakerHook{
+		Breaker: internal.NewCircuitBreaker("redis", 5, 10*time.Second),
 	})
 
-	// Test Redis connection
-	ctx := context.Background()
-	_, err = redisClient.Ping(ctx).Result()
-	if err != nil {
-		log.Fatalf("failed to connect to Redis: %v", err)
+	// Test Redis connection; the server starts either way
+	if _, err := redisClient.Ping(ctx).Result(); err != nil {
+		logger.Warn("Redis unavailable, starting without cache", "addr", redisAddr, "error", err)
+	} else {
+		logger.Info("connected to Redis", "addr", redisAddr)
 	}
-	log.Printf("Connected to Redis at %s", redisAddr)
 
 	// 3) AutoMigrate our models (creates tables if they don’t exist)
-	if err := internal.Migrate(db); err != nil {
-		log.Fatalf("auto‐migrate failed: %v", err)
+	err = internal.Retry(ctx, "auto-migrate", time.Second, 30*time.Second, func() error {
+		return internal.Migrate(db)
+	})
+	if err != nil {
+		fatal("auto-migrate failed", err)
+	}
+
+	// Catalog cache shared by the gRPC service and the seeding below
+	catalogCache := internal.NewCatalogCache(db, redisClient)
+	go catalogCache.Subscribe(ctx)
+
+	readiness := internal.NewReadiness(db, redisClient, catalogCache)
+	go readiness.Monitor(ctx, 5*time.Second)
+
+	// Alert transitions are written to the notification outbox and delivered in the background
+	smtpSender := internal.SMTPSender{
+		Addr:     getEnv("SMTP_ADDR", ""),
+		From:     getEnv("SMTP_FROM", "alerts@team15.local"),
+		Username: getEnv("SMTP_USERNAME", ""),
+		Password: getEnv("SMTP_PASSWORD", ""),
+	}
+	notifier := internal.NewNotifier(db, smtpSender)
+	go notifier.Run(ctx, 5*time.Second)
+
+	maintenance := internal.NewMaintenanceSchedule(db)
+
+	// Raw samples are rolled up into 1m/1h/1d tables and purged once expired
+	retention := internal.DefaultRetentionPolicy
+	for env, d := range map[string]*time.Duration{
+		"RETENTION_RAW": &retention.Raw,
+		"RETENTION_1M":  &retention.Minute,
+		"RETENTION_1H":  &retention.Hour,
+		"RETENTION_1D":  &retention.Day,
+	} {
+		if v := getEnv(env, ""); v != "" {
+			parsed, err := internal.ParseRetention(v)
+			if err != nil {
+				fatal("invalid "+env, err)
+			}
+			*d = parsed
+		}
+	}
+	if err := retention.Validate(); err != nil {
+		fatal("invalid retention policy", err)
+	}
+	go internal.NewRetainer(db, retention).Run(ctx, time.Minute)
+	history := internal.NewHealthHistory(db, retention)
+
+	// Alerts are not evaluated for services in a maintenance window
+	alertEvaluator := internal.NewAlertEvaluator(db, 30*time.Second)
+	alertEvaluator.OnTransition(notifier.Enqueue)
+	alertEvaluator.SuppressWhen(func(ctx context.Context, svc internal.ServiceModel, t time.Time) bool {
+		return maintenance.Active(ctx, svc, t) != nil
+	})
+	go alertEvaluator.Run(ctx)
+
+	// Cross-instance fan-out for streaming RPCs
+	hub := internal.NewHub(redisClient)
+	go hub.Run(ctx)
+
+	incidentServer := internal.NewIncidentServer(db, hub)
+	if after := getEnv("INCIDENT_AUTO_OPEN_AFTER", ""); after != "" {
+		d, err := time.ParseDuration(after)
+		if err != nil {
+			fatal("invalid INCIDENT_AUTO_OPEN_AFTER", err)
+		}
+		go internal.NewIncidentAutoOpener(db, incidentServer, d).Run(ctx, 30*time.Second)
 	}
 
 	// 4) Seed initial services if none exist
@@ -92,50 +179,248 @@ func main() {
 			{ID: "catalog", Name: "Catalog", Owner: "TeamC", Version: "v1.0.0", ProtoURL: "http://example.com/protos/catalog.proto"},
 		}
 		if err := db.Create(&initial).Error; err != nil {
-			log.Fatalf("failed to seed services: %v", err)
+			fatal("failed to seed services", err)
+		}
+		logger.Info("seeded initial services into the database", "count", len(initial))
+		if err := catalogCache.Invalidate(ctx); err != nil {
+			logger.Warn("catalog cache invalidation failed", "error", err)
+		}
+	}
+
+	auditLog := internal.NewAuditLog(db)
+
+	// Rate limits, per client IP and principal, kept in Redis
+	if hops := getEnv("TRUSTED_PROXY_HOPS", ""); hops != "" {
+		if internal.TrustedProxyHops, err = strconv.Atoi(hops); err != nil || internal.TrustedProxyHops < 0 {
+			fatal("invalid TRUSTED_PROXY_HOPS", fmt.Errorf("%q is not a non-negative integer", hops))
+		}
+	}
+	rateRules := internal.DefaultRateRules
+	if overrides := getEnv("RATE_LIMITS", ""); overrides != "" {
+		if rateRules, err = internal.ParseRateRules(rateRules, overrides); err != nil {
+			fatal("invalid RATE_LIMITS", err)
 		}
-		fmt.Println("Seeded initial services into the database.")
 	}
+	limiter := internal.NewRateLimiter(redisClient, rateRules)
+
+	// Cookie attributes; browsers only send Secure cookies over HTTPS
+	internal.CookieSecure = getEnv("COOKIE_SECURE", "false") == "true"
+	if internal.CookieSameSite, err = internal.ParseSameSite(getEnv("COOKIE_SAMESITE", "lax")); err != nil {
+		fatal("invalid COOKIE_SAMESITE", err)
+	}
+	if internal.CookieSameSite == http.SameSiteNoneMode && !internal.CookieSecure {
+		fatal("invalid COOKIE_SAMESITE", fmt.Errorf("SameSite=None requires COOKIE_SECURE=true"))
+	}
+	loginGuard = internal.NewLoginGuard(redisClient)
+
+	// Every login is a session that can be listed and revoked
+	sessions := internal.NewSessions(db, redisClient, hub)
+	go sessions.Run(ctx)
+
+	// Password resets and email verification links are mailed; without SMTP
+	// their endpoints are off
+	var mailer internal.Mailer
+	if smtpSender.Addr != "" {
+		mailer = smtpSender
+	} else {
+		logger.Warn("SMTP_ADDR not set, password reset and email verification are disabled")
+	}
+	minLength, err := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "12"))
+	if err != nil || minLength < 1 {
+		fatal("invalid PASSWORD_MIN_LENGTH", fmt.Errorf("%q is not a positive integer", getEnv("PASSWORD_MIN_LENGTH", "")))
+	}
+	passwordPolicy, err := internal.LoadPasswordPolicy(minLength, getEnv("PASSWORD_BREACHED_FILE", ""))
+	if err != nil {
+		fatal("loading PASSWORD_BREACHED_FILE failed", err)
+	}
+	logger.Info("password policy loaded", "min_length", minLength, "breached_passwords", passwordPolicy.BreachedCount())
+
+	// Local users; the admin account is created on first start
+	accounts = internal.NewAccounts(db, secretKey, loginGuard, sessions, mailer, passwordPolicy, getEnv("APP_URL", "http://localhost:8080"))
+	if err := accounts.SeedAdmin(ctx, getEnv("ADMIN_PASSWORD", "secret")); err != nil {
+		fatal("seeding the admin user failed", err)
+	}
+	authenticator = accounts
+	if ldapURL := getEnv("LDAP_URL", ""); ldapURL != "" {
+		groupRoles, err := internal.ParseGroupRoles(getEnv("LDAP_GROUP_ROLES", ""))
+		if err != nil {
+			fatal("invalid LDAP_GROUP_ROLES", err)
+		}
+		tlsConfig := &tls.Config{}
+		if caFile := getEnv("LDAP_CA_FILE", ""); caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				fatal("reading LDAP_CA_FILE failed", err)
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				fatal("invalid LDAP_CA_FILE", fmt.Errorf("no certificates in %s", caFile))
+			}
+		}
+		ldapAuth := internal.NewLDAPAuthenticator(internal.LDAPConfig{
+			URL:            ldapURL,
+			StartTLS:       getEnv("LDAP_START_TLS", "false") == "true",
+			TLSConfig:      tlsConfig,
+			BindDN:         getEnv("LDAP_BIND_DN", ""),
+			BindPassword:   getEnv("LDAP_BIND_PASSWORD", ""),
+			BaseDN:         getEnv("LDAP_BASE_DN", ""),
+			UserFilter:     getEnv("LDAP_USER_FILTER", ""),
+			GroupBaseDN:    getEnv("LDAP_GROUP_BASE_DN", ""),
+			GroupFilter:    getEnv("LDAP_GROUP_FILTER", ""),
+			GroupAttribute: getEnv("LDAP_GROUP_ATTRIBUTE", ""),
+			GroupRoles:     groupRoles,
+		}, accounts)
+		// The local store stays as a fallback, e.g. for the admin account
+		// while the directory is unreachable.
+		authenticator = internal.Authenticators{ldapAuth, accounts}
+		logger.Info("LDAP login enabled", "url", ldapURL)
+	}
+
+	// Non-human clients get scoped tokens with the client-credentials grant
+	serviceAccounts := internal.NewServiceAccounts(db, secretKey, sessions)
 
 	// 5) Start HTTP login server on 8081
 	router := chi.NewRouter()
+	router.Use(internal.RequestLogger(logger))
+	router.Use(limiter.Middleware)
+	router.Use(internal.AuditMiddleware(auditLog))
 	router.Post("/login", loginHandler) // loginHandler is from backend/cmd/server/auth.go
+	router.Get("/healthz", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+	router.Get("/readyz", readiness.Handler)
+	router.Get("/metrics", internal.MetricsHandler)
+	accounts.LimitPerPrincipal(limiter)
+	accounts.Routes(router)
+	serviceAccounts.Routes(router)
+	if issuer := getEnv("OIDC_ISSUER", ""); issuer != "" {
+		groupRoles, err := internal.ParseGroupRoles(getEnv("OIDC_GROUP_ROLES", ""))
+		if err != nil {
+			fatal("invalid OIDC_GROUP_ROLES", err)
+		}
+		oidcCfg := internal.OIDCConfig{
+			Issuer:       issuer,
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", ""),
+			Scopes:       strings.Fields(getEnv("OIDC_SCOPES", "")),
+			GroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", ""),
+			GroupRoles:   groupRoles,
+			PostLoginURL: getEnv("OIDC_POST_LOGIN_URL", ""),
+		}
+		if oidcCfg.ClientID == "" || oidcCfg.RedirectURL == "" {
+			fatal("invalid OIDC configuration", fmt.Errorf("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required with OIDC_ISSUER"))
+		}
+		internal.NewOIDCProvider(oidcCfg, accounts, auditLog).Routes(router)
+		logger.Info("OIDC login enabled", "issuer", issuer)
+	}
+	internal.NewStatusPage(db, redisClient, catalogCache, maintenance, history).Routes(router)
+	router.Route("/admin", func(r chi.Router) {
+		r.Use(internal.RequireJWT(secretKey, sessions))
+		r.Use(limiter.PrincipalMiddleware)
+		r.Use(internal.RequireRole("admin"))
+		r.Use(sessions.RequireStrict)
+		r.Get("/loglevel", internal.LogLevelHandler)
+		r.Put("/loglevel", internal.LogLevelHandler)
+		r.Get("/mfa-policy", accounts.MFAPolicyHandler)
+		r.Put("/mfa-policy", accounts.MFAPolicyHandler)
+		sessions.AdminRoutes(r)
+		serviceAccounts.AdminRoutes(r)
+		accounts.AdminRoutes(r)
+	})
+	router.Route("/auth/sessions", func(r chi.Router) {
+		r.Use(internal.RequireJWT(secretKey, sessions))
+		r.Use(limiter.PrincipalMiddleware)
+		sessions.Routes(r)
+	})
+	router.With(internal.RequireJWT(secretKey, sessions), limiter.PrincipalMiddleware).Get("/me", internal.MeHandler)
+	// HTTP/JSON for the catalog and health RPCs; the gateway calls the gRPC
+	// server below in memory so every interceptor still applies
+	gatewayLis, gatewayConn, err := internal.NewInProcessConn()
+	if err != nil {
+		fatal("failed to create the gateway's gRPC client", err)
+	}
+	gateway, err := internal.NewGateway(gatewayConn)
+	if err != nil {
+		fatal("failed to register the gateway handlers", err)
+	}
+	gateway.Routes(router)
 	httpLis, err := net.Listen("tcp4", "0.0.0.0:8081")
 	if err != nil {
-		log.Fatalf("failed to listen for HTTP on 0.0.0.0:8081: %v", err)
+		fatal("failed to listen for HTTP on 0.0.0.0:8081", err)
 	}
-	log.Println("HTTP server listening on", httpLis.Addr().String())
+	logger.Info("HTTP server listening", "addr", httpLis.Addr().String())
 	go func() {
 		if err := http.Serve(httpLis, router); err != nil {
-			log.Fatalf("failed to serve HTTP: %v", err)
+			fatal("failed to serve HTTP", err)
 		}
 	}()
 
 	// 6) Start the gRPC server on 50051 (with JWT interceptors)
 	lis, err := net.Listen("tcp4", ":50051")
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		fatal("failed to listen", err)
 	}
 
+	// The per-IP limit runs before auditing and the JWT check so that
+	// unauthenticated callers are limited too; logging and auditing still
+	// run before the JWT check so they can report the calls it rejects
 	serverOpts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(internal.JWTInterceptor(secretKey)),
-		grpc.StreamInterceptor(internal.JWTStreamInterceptor(secretKey)),
+		grpc.ChainUnaryInterceptor(
+			internal.LoggingInterceptor(logger),
+			limiter.UnaryInterceptor(),
+			internal.AuditInterceptor(auditLog),
+			internal.JWTInterceptor(secretKey, sessions),
+			limiter.PrincipalUnaryInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			internal.LoggingStreamInterceptor(logger),
+			limiter.StreamInterceptor(),
+			internal.JWTStreamInterceptor(secretKey, sessions),
+			limiter.PrincipalStreamInterceptor(),
+		),
 	}
 	grpcServer := grpc.NewServer(serverOpts...)
 
 	// Register CatalogService and HealthService with DB-backed implementations
-	catalogpb.RegisterCatalogServiceServer(grpcServer, internal.NewCatalogServer(db, redisClient))
-	healthpb.RegisterHealthServiceServer(grpcServer, internal.NewHealthServer(db, redisClient))
+	catalogpb.RegisterCatalogServiceServer(grpcServer, internal.NewCatalogServer(db, catalogCache))
+	healthServer := internal.NewHealthServer(db, redisClient, catalogCache, maintenance, history, hub)
+	// Random samples for watched services that do not push their own health
+	healthServer.Simulate(getEnv("HEALTH_SIMULATOR", "true") == "true")
+	anomalyCfg := internal.DefaultAnomalyConfig
+	if v := getEnv("ANOMALY_THRESHOLD", ""); v != "" {
+		if anomalyCfg.Threshold, err = strconv.ParseFloat(v, 64); err != nil || anomalyCfg.Threshold <= 0 {
+			fatal("invalid ANOMALY_THRESHOLD", fmt.Errorf("%q is not a positive number", v))
+		}
+	}
+	healthServer.DetectAnomalies(internal.NewAnomalyDetector(db, redisClient, anomalyCfg))
+	healthpb.RegisterHealthServiceServer(grpcServer, healthServer)
+	go internal.NewScraper(healthServer, &http.Client{}).Run(ctx, time.Second)
+	alertingpb.RegisterAlertingServiceServer(grpcServer, internal.NewAlertingServer(db))
+	notificationpb.RegisterNotificationServiceServer(grpcServer, internal.NewNotificationServer(db))
+	slopb.RegisterSLOServiceServer(grpcServer, internal.NewSLOServer(db, history))
+	incidentpb.RegisterIncidentServiceServer(grpcServer, incidentServer)
+	maintenancepb.RegisterMaintenanceServiceServer(grpcServer, internal.NewMaintenanceServer(db, maintenance))
+	auditpb.RegisterAuditServiceServer(grpcServer, internal.NewAuditServer(db))
 
 	// Enable server reflection so grpcurl (and other tools) can probe
 	reflection.Register(grpcServer)
 
-	log.Println("gRPC server listening on 0.0.0.0:50051") // Modified log message for clarity
+	go func() {
+		if err := grpcServer.Serve(gatewayLis); err != nil {
+			fatal("gateway listener error", err)
+		}
+	}()
+	logger.Info("gRPC server listening", "addr", "0.0.0.0:50051")
 	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("server error: %v", err)
+		fatal("server error", err)
 	}
 }
 
+// fatal logs msg at error level and exits, like log.Fatalf did before slog.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
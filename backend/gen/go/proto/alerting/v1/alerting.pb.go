@@ -0,0 +1,876 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/alerting/v1/alerting.proto
+
+package alertingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AlertState int32
+
+const (
+	AlertState_ALERT_STATE_UNSPECIFIED AlertState = 0
+	AlertState_ALERT_STATE_PENDING     AlertState = 1
+	AlertState_ALERT_STATE_FIRING      AlertState = 2
+	AlertState_ALERT_STATE_RESOLVED    AlertState = 3
+)
+
+// Enum value maps for AlertState.
+var (
+	AlertState_name = map[int32]string{
+		0: "ALERT_STATE_UNSPECIFIED",
+		1: "ALERT_STATE_PENDING",
+		2: "ALERT_STATE_FIRING",
+		3: "ALERT_STATE_RESOLVED",
+	}
+	AlertState_value = map[string]int32{
+		"ALERT_STATE_UNSPECIFIED": 0,
+		"ALERT_STATE_PENDING":     1,
+		"ALERT_STATE_FIRING":      2,
+		"ALERT_STATE_RESOLVED":    3,
+	}
+)
+
+func (x AlertState) Enum() *AlertState {
+	p := new(AlertState)
+	*p = x
+	return p
+}
+
+func (x AlertState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_alerting_v1_alerting_proto_enumTypes[0].Descriptor()
+}
+
+func (AlertState) Type() protoreflect.EnumType {
+	return &file_proto_alerting_v1_alerting_proto_enumTypes[0]
+}
+
+func (x AlertState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertState.Descriptor instead.
+func (AlertState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{0}
+}
+
+// A rule evaluated periodically against stored health metrics.
+//
+// expr has the form `<metric> [<aggregation>] <op> <value> [over <window>] [for <duration> | for <n> checks]`,
+// e.g. "latency_ms p95 > 500 for 5m", "status == DOWN for 2 checks" or "error_rate > 0.05".
+type AlertRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ServiceId     string                 `protobuf:"bytes,3,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`                                                        // target a single service...
+	Selector      map[string]string      `protobuf:"bytes,4,rep,name=selector,proto3" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // ...or every service whose labels match all of these
+	Expr          string                 `protobuf:"bytes,5,opt,name=expr,proto3" json:"expr,omitempty"`
+	Severity      string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	Enabled       bool                   `protobuf:"varint,7,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AlertRule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AlertRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AlertRule) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *AlertRule) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *AlertRule) GetExpr() string {
+	if x != nil {
+		return x.Expr
+	}
+	return ""
+}
+
+func (x *AlertRule) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *AlertRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RuleId        string                 `protobuf:"bytes,2,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	RuleName      string                 `protobuf:"bytes,3,opt,name=rule_name,json=ruleName,proto3" json:"rule_name,omitempty"`
+	ServiceId     string                 `protobuf:"bytes,4,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Severity      string                 `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	State         AlertState             `protobuf:"varint,6,opt,name=state,proto3,enum=alerting.v1.AlertState" json:"state,omitempty"`
+	Value         float64                `protobuf:"fixed64,7,opt,name=value,proto3" json:"value,omitempty"`
+	Fingerprint   string                 `protobuf:"bytes,8,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	StartedAtMs   int64                  `protobuf:"varint,9,opt,name=started_at_ms,json=startedAtMs,proto3" json:"started_at_ms,omitempty"`
+	FiredAtMs     int64                  `protobuf:"varint,10,opt,name=fired_at_ms,json=firedAtMs,proto3" json:"fired_at_ms,omitempty"`
+	ResolvedAtMs  int64                  `protobuf:"varint,11,opt,name=resolved_at_ms,json=resolvedAtMs,proto3" json:"resolved_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Alert) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Alert) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *Alert) GetRuleName() string {
+	if x != nil {
+		return x.RuleName
+	}
+	return ""
+}
+
+func (x *Alert) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *Alert) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Alert) GetState() AlertState {
+	if x != nil {
+		return x.State
+	}
+	return AlertState_ALERT_STATE_UNSPECIFIED
+}
+
+func (x *Alert) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Alert) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *Alert) GetStartedAtMs() int64 {
+	if x != nil {
+		return x.StartedAtMs
+	}
+	return 0
+}
+
+func (x *Alert) GetFiredAtMs() int64 {
+	if x != nil {
+		return x.FiredAtMs
+	}
+	return 0
+}
+
+func (x *Alert) GetResolvedAtMs() int64 {
+	if x != nil {
+		return x.ResolvedAtMs
+	}
+	return 0
+}
+
+type CreateRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRuleRequest) Reset() {
+	*x = CreateRuleRequest{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRuleRequest) ProtoMessage() {}
+
+func (x *CreateRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateRuleRequest) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type CreateRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRuleResponse) Reset() {
+	*x = CreateRuleResponse{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRuleResponse) ProtoMessage() {}
+
+func (x *CreateRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRuleResponse.ProtoReflect.Descriptor instead.
+func (*CreateRuleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateRuleResponse) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// Replaces every field of the rule identified by rule.id.
+type UpdateRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRuleRequest) Reset() {
+	*x = UpdateRuleRequest{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRuleRequest) ProtoMessage() {}
+
+func (x *UpdateRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateRuleRequest) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type UpdateRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRuleResponse) Reset() {
+	*x = UpdateRuleResponse{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRuleResponse) ProtoMessage() {}
+
+func (x *UpdateRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRuleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRuleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateRuleResponse) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type DeleteRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRuleRequest) Reset() {
+	*x = DeleteRuleRequest{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRuleRequest) ProtoMessage() {}
+
+func (x *DeleteRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteRuleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRuleResponse) Reset() {
+	*x = DeleteRuleResponse{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRuleResponse) ProtoMessage() {}
+
+func (x *DeleteRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRuleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{7}
+}
+
+type ListRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRulesRequest) Reset() {
+	*x = ListRulesRequest{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRulesRequest) ProtoMessage() {}
+
+func (x *ListRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{8}
+}
+
+type ListRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*AlertRule           `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRulesResponse) Reset() {
+	*x = ListRulesResponse{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRulesResponse) ProtoMessage() {}
+
+func (x *ListRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListRulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListRulesResponse) GetRules() []*AlertRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ListAlertsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId       string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"` // optional filter
+	IncludeResolved bool                   `protobuf:"varint,2,opt,name=include_resolved,json=includeResolved,proto3" json:"include_resolved,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListAlertsRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ListAlertsRequest) GetIncludeResolved() bool {
+	if x != nil {
+		return x.IncludeResolved
+	}
+	return false
+}
+
+type ListAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alerting_v1_alerting_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_alerting_v1_alerting_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+var File_proto_alerting_v1_alerting_proto protoreflect.FileDescriptor
+
+const file_proto_alerting_v1_alerting_proto_rawDesc = "" +
+	"\n" +
+	" proto/alerting/v1/alerting.proto\x12\valerting.v1\"\x97\x02\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x03 \x01(\tR\tserviceId\x12@\n" +
+	"\bselector\x18\x04 \x03(\v2$.alerting.v1.AlertRule.SelectorEntryR\bselector\x12\x12\n" +
+	"\x04expr\x18\x05 \x01(\tR\x04expr\x12\x1a\n" +
+	"\bseverity\x18\x06 \x01(\tR\bseverity\x12\x18\n" +
+	"\aenabled\x18\a \x01(\bR\aenabled\x1a;\n" +
+	"\rSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd9\x02\n" +
+	"\x05Alert\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\arule_id\x18\x02 \x01(\tR\x06ruleId\x12\x1b\n" +
+	"\trule_name\x18\x03 \x01(\tR\bruleName\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x04 \x01(\tR\tserviceId\x12\x1a\n" +
+	"\bseverity\x18\x05 \x01(\tR\bseverity\x12-\n" +
+	"\x05state\x18\x06 \x01(\x0e2\x17.alerting.v1.AlertStateR\x05state\x12\x14\n" +
+	"\x05value\x18\a \x01(\x01R\x05value\x12 \n" +
+	"\vfingerprint\x18\b \x01(\tR\vfingerprint\x12\"\n" +
+	"\rstarted_at_ms\x18\t \x01(\x03R\vstartedAtMs\x12\x1e\n" +
+	"\vfired_at_ms\x18\n" +
+	" \x01(\x03R\tfiredAtMs\x12$\n" +
+	"\x0eresolved_at_ms\x18\v \x01(\x03R\fresolvedAtMs\"?\n" +
+	"\x11CreateRuleRequest\x12*\n" +
+	"\x04rule\x18\x01 \x01(\v2\x16.alerting.v1.AlertRuleR\x04rule\"@\n" +
+	"\x12CreateRuleResponse\x12*\n" +
+	"\x04rule\x18\x01 \x01(\v2\x16.alerting.v1.AlertRuleR\x04rule\"?\n" +
+	"\x11UpdateRuleRequest\x12*\n" +
+	"\x04rule\x18\x01 \x01(\v2\x16.alerting.v1.AlertRuleR\x04rule\"@\n" +
+	"\x12UpdateRuleResponse\x12*\n" +
+	"\x04rule\x18\x01 \x01(\v2\x16.alerting.v1.AlertRuleR\x04rule\"#\n" +
+	"\x11DeleteRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
+	"\x12DeleteRuleResponse\"\x12\n" +
+	"\x10ListRulesRequest\"A\n" +
+	"\x11ListRulesResponse\x12,\n" +
+	"\x05rules\x18\x01 \x03(\v2\x16.alerting.v1.AlertRuleR\x05rules\"]\n" +
+	"\x11ListAlertsRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12)\n" +
+	"\x10include_resolved\x18\x02 \x01(\bR\x0fincludeResolved\"@\n" +
+	"\x12ListAlertsResponse\x12*\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x12.alerting.v1.AlertR\x06alerts*t\n" +
+	"\n" +
+	"AlertState\x12\x1b\n" +
+	"\x17ALERT_STATE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13ALERT_STATE_PENDING\x10\x01\x12\x16\n" +
+	"\x12ALERT_STATE_FIRING\x10\x02\x12\x18\n" +
+	"\x14ALERT_STATE_RESOLVED\x10\x032\x99\x03\n" +
+	"\x0fAlertingService\x12M\n" +
+	"\n" +
+	"CreateRule\x12\x1e.alerting.v1.CreateRuleRequest\x1a\x1f.alerting.v1.CreateRuleResponse\x12M\n" +
+	"\n" +
+	"UpdateRule\x12\x1e.alerting.v1.UpdateRuleRequest\x1a\x1f.alerting.v1.UpdateRuleResponse\x12M\n" +
+	"\n" +
+	"DeleteRule\x12\x1e.alerting.v1.DeleteRuleRequest\x1a\x1f.alerting.v1.DeleteRuleResponse\x12J\n" +
+	"\tListRules\x12\x1d.alerting.v1.ListRulesRequest\x1a\x1e.alerting.v1.ListRulesResponse\x12M\n" +
+	"\n" +
+	"ListAlerts\x12\x1e.alerting.v1.ListAlertsRequest\x1a\x1f.alerting.v1.ListAlertsResponseBIZGgithub.com/Prof-Rosario-UCLA/team15/gen/go/proto/alerting/v1;alertingpbb\x06proto3"
+
+var (
+	file_proto_alerting_v1_alerting_proto_rawDescOnce sync.Once
+	file_proto_alerting_v1_alerting_proto_rawDescData []byte
+)
+
+func file_proto_alerting_v1_alerting_proto_rawDescGZIP() []byte {
+	file_proto_alerting_v1_alerting_proto_rawDescOnce.Do(func() {
+		file_proto_alerting_v1_alerting_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_alerting_v1_alerting_proto_rawDesc), len(file_proto_alerting_v1_alerting_proto_rawDesc)))
+	})
+	return file_proto_alerting_v1_alerting_proto_rawDescData
+}
+
+var file_proto_alerting_v1_alerting_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_alerting_v1_alerting_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_alerting_v1_alerting_proto_goTypes = []any{
+	(AlertState)(0),            // 0: alerting.v1.AlertState
+	(*AlertRule)(nil),          // 1: alerting.v1.AlertRule
+	(*Alert)(nil),              // 2: alerting.v1.Alert
+	(*CreateRuleRequest)(nil),  // 3: alerting.v1.CreateRuleRequest
+	(*CreateRuleResponse)(nil), // 4: alerting.v1.CreateRuleResponse
+	(*UpdateRuleRequest)(nil),  // 5: alerting.v1.UpdateRuleRequest
+	(*UpdateRuleResponse)(nil), // 6: alerting.v1.UpdateRuleResponse
+	(*DeleteRuleRequest)(nil),  // 7: alerting.v1.DeleteRuleRequest
+	(*DeleteRuleResponse)(nil), // 8: alerting.v1.DeleteRuleResponse
+	(*ListRulesRequest)(nil),   // 9: alerting.v1.ListRulesRequest
+	(*ListRulesResponse)(nil),  // 10: alerting.v1.ListRulesResponse
+	(*ListAlertsRequest)(nil),  // 11: alerting.v1.ListAlertsRequest
+	(*ListAlertsResponse)(nil), // 12: alerting.v1.ListAlertsResponse
+	nil,                        // 13: alerting.v1.AlertRule.SelectorEntry
+}
+var file_proto_alerting_v1_alerting_proto_depIdxs = []int32{
+	13, // 0: alerting.v1.AlertRule.selector:type_name -> alerting.v1.AlertRule.SelectorEntry
+	0,  // 1: alerting.v1.Alert.state:type_name -> alerting.v1.AlertState
+	1,  // 2: alerting.v1.CreateRuleRequest.rule:type_name -> alerting.v1.AlertRule
+	1,  // 3: alerting.v1.CreateRuleResponse.rule:type_name -> alerting.v1.AlertRule
+	1,  // 4: alerting.v1.UpdateRuleRequest.rule:type_name -> alerting.v1.AlertRule
+	1,  // 5: alerting.v1.UpdateRuleResponse.rule:type_name -> alerting.v1.AlertRule
+	1,  // 6: alerting.v1.ListRulesResponse.rules:type_name -> alerting.v1.AlertRule
+	2,  // 7: alerting.v1.ListAlertsResponse.alerts:type_name -> alerting.v1.Alert
+	3,  // 8: alerting.v1.AlertingService.CreateRule:input_type -> alerting.v1.CreateRuleRequest
+	5,  // 9: alerting.v1.AlertingService.UpdateRule:input_type -> alerting.v1.UpdateRuleRequest
+	7,  // 10: alerting.v1.AlertingService.DeleteRule:input_type -> alerting.v1.DeleteRuleRequest
+	9,  // 11: alerting.v1.AlertingService.ListRules:input_type -> alerting.v1.ListRulesRequest
+	11, // 12: alerting.v1.AlertingService.ListAlerts:input_type -> alerting.v1.ListAlertsRequest
+	4,  // 13: alerting.v1.AlertingService.CreateRule:output_type -> alerting.v1.CreateRuleResponse
+	6,  // 14: alerting.v1.AlertingService.UpdateRule:output_type -> alerting.v1.UpdateRuleResponse
+	8,  // 15: alerting.v1.AlertingService.DeleteRule:output_type -> alerting.v1.DeleteRuleResponse
+	10, // 16: alerting.v1.AlertingService.ListRules:output_type -> alerting.v1.ListRulesResponse
+	12, // 17: alerting.v1.AlertingService.ListAlerts:output_type -> alerting.v1.ListAlertsResponse
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_proto_alerting_v1_alerting_proto_init() }
+func file_proto_alerting_v1_alerting_proto_init() {
+	if File_proto_alerting_v1_alerting_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_alerting_v1_alerting_proto_rawDesc), len(file_proto_alerting_v1_alerting_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_alerting_v1_alerting_proto_goTypes,
+		DependencyIndexes: file_proto_alerting_v1_alerting_proto_depIdxs,
+		EnumInfos:         file_proto_alerting_v1_alerting_proto_enumTypes,
+		MessageInfos:      file_proto_alerting_v1_alerting_proto_msgTypes,
+	}.Build()
+	File_proto_alerting_v1_alerting_proto = out.File
+	file_proto_alerting_v1_alerting_proto_goTypes = nil
+	file_proto_alerting_v1_alerting_proto_depIdxs = nil
+}
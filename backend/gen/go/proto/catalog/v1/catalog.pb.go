@@ -7,6 +7,7 @@
 package catalogpb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -29,6 +30,10 @@ type Service struct {
 	Owner         string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
 	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
 	ProtoUrl      string                 `protobuf:"bytes,5,opt,name=proto_url,json=protoUrl,proto3" json:"proto_url,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // free-form labels, e.g. tier=critical
+	Public        bool                   `protobuf:"varint,7,opt,name=public,proto3" json:"public,omitempty"`                                                                          // shown on the unauthenticated status page
+	Scrape        *ScrapeConfig          `protobuf:"bytes,8,opt,name=scrape,proto3" json:"scrape,omitempty"`                                                                           // set to collect health from the service's Prometheus metrics
+	Thresholds    *HealthThresholds      `protobuf:"bytes,9,opt,name=thresholds,proto3" json:"thresholds,omitempty"`                                                                   // derive DEGRADED / DOWN from latency and error rate
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -98,6 +103,220 @@ func (x *Service) GetProtoUrl() string {
 	return ""
 }
 
+func (x *Service) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Service) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *Service) GetScrape() *ScrapeConfig {
+	if x != nil {
+		return x.Scrape
+	}
+	return nil
+}
+
+func (x *Service) GetThresholds() *HealthThresholds {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+// Limits past which a sample makes a service DEGRADED or DOWN; zero disables
+// a limit. A new status only takes effect once that many consecutive samples
+// agree, so a single slow sample does not flip the state.
+type HealthThresholds struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	DegradedLatencyMs int32                  `protobuf:"varint,1,opt,name=degraded_latency_ms,json=degradedLatencyMs,proto3" json:"degraded_latency_ms,omitempty"`
+	DownLatencyMs     int32                  `protobuf:"varint,2,opt,name=down_latency_ms,json=downLatencyMs,proto3" json:"down_latency_ms,omitempty"`
+	DegradedErrorRate float32                `protobuf:"fixed32,3,opt,name=degraded_error_rate,json=degradedErrorRate,proto3" json:"degraded_error_rate,omitempty"`
+	DownErrorRate     float32                `protobuf:"fixed32,4,opt,name=down_error_rate,json=downErrorRate,proto3" json:"down_error_rate,omitempty"`
+	RaiseAfter        int32                  `protobuf:"varint,5,opt,name=raise_after,json=raiseAfter,proto3" json:"raise_after,omitempty"` // samples to move to a worse status; default 3
+	ClearAfter        int32                  `protobuf:"varint,6,opt,name=clear_after,json=clearAfter,proto3" json:"clear_after,omitempty"` // samples to move to a better status; default 3
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *HealthThresholds) Reset() {
+	*x = HealthThresholds{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthThresholds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthThresholds) ProtoMessage() {}
+
+func (x *HealthThresholds) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthThresholds.ProtoReflect.Descriptor instead.
+func (*HealthThresholds) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HealthThresholds) GetDegradedLatencyMs() int32 {
+	if x != nil {
+		return x.DegradedLatencyMs
+	}
+	return 0
+}
+
+func (x *HealthThresholds) GetDownLatencyMs() int32 {
+	if x != nil {
+		return x.DownLatencyMs
+	}
+	return 0
+}
+
+func (x *HealthThresholds) GetDegradedErrorRate() float32 {
+	if x != nil {
+		return x.DegradedErrorRate
+	}
+	return 0
+}
+
+func (x *HealthThresholds) GetDownErrorRate() float32 {
+	if x != nil {
+		return x.DownErrorRate
+	}
+	return 0
+}
+
+func (x *HealthThresholds) GetRaiseAfter() int32 {
+	if x != nil {
+		return x.RaiseAfter
+	}
+	return 0
+}
+
+func (x *HealthThresholds) GetClearAfter() int32 {
+	if x != nil {
+		return x.ClearAfter
+	}
+	return 0
+}
+
+// Where and how to derive health samples from a Prometheus /metrics endpoint.
+// Metrics are PromQL-style selectors such as http_requests_total{code=~"5.."};
+// matching series are summed.
+type ScrapeConfig struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Url             string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	IntervalSeconds int32                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // default 15
+	// A histogram (quantile of the observations since the previous scrape),
+	// a summary (its quantile series) or a gauge.
+	LatencyMetric   string  `protobuf:"bytes,3,opt,name=latency_metric,json=latencyMetric,proto3" json:"latency_metric,omitempty"`
+	LatencyQuantile float64 `protobuf:"fixed64,4,opt,name=latency_quantile,json=latencyQuantile,proto3" json:"latency_quantile,omitempty"` // default 0.99
+	LatencyUnit     string  `protobuf:"bytes,5,opt,name=latency_unit,json=latencyUnit,proto3" json:"latency_unit,omitempty"`               // "s" (default) or "ms"
+	// The error rate is the increase of errors_metric over the increase of
+	// total_metric since the previous scrape.
+	ErrorsMetric  string `protobuf:"bytes,6,opt,name=errors_metric,json=errorsMetric,proto3" json:"errors_metric,omitempty"`
+	TotalMetric   string `protobuf:"bytes,7,opt,name=total_metric,json=totalMetric,proto3" json:"total_metric,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScrapeConfig) Reset() {
+	*x = ScrapeConfig{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScrapeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrapeConfig) ProtoMessage() {}
+
+func (x *ScrapeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrapeConfig.ProtoReflect.Descriptor instead.
+func (*ScrapeConfig) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ScrapeConfig) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ScrapeConfig) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *ScrapeConfig) GetLatencyMetric() string {
+	if x != nil {
+		return x.LatencyMetric
+	}
+	return ""
+}
+
+func (x *ScrapeConfig) GetLatencyQuantile() float64 {
+	if x != nil {
+		return x.LatencyQuantile
+	}
+	return 0
+}
+
+func (x *ScrapeConfig) GetLatencyUnit() string {
+	if x != nil {
+		return x.LatencyUnit
+	}
+	return ""
+}
+
+func (x *ScrapeConfig) GetErrorsMetric() string {
+	if x != nil {
+		return x.ErrorsMetric
+	}
+	return ""
+}
+
+func (x *ScrapeConfig) GetTotalMetric() string {
+	if x != nil {
+		return x.TotalMetric
+	}
+	return ""
+}
+
 // A request just to list all services (empty body).
 type ListServicesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -107,7 +326,7 @@ type ListServicesRequest struct {
 
 func (x *ListServicesRequest) Reset() {
 	*x = ListServicesRequest{}
-	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[1]
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -119,7 +338,7 @@ func (x *ListServicesRequest) String() string {
 func (*ListServicesRequest) ProtoMessage() {}
 
 func (x *ListServicesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[1]
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -132,7 +351,7 @@ func (x *ListServicesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListServicesRequest.ProtoReflect.Descriptor instead.
 func (*ListServicesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{1}
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{3}
 }
 
 // Because the lint rule wants a response type, wrap the repeated Service here:
@@ -145,7 +364,7 @@ type ListServicesResponse struct {
 
 func (x *ListServicesResponse) Reset() {
 	*x = ListServicesResponse{}
-	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[2]
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -157,7 +376,7 @@ func (x *ListServicesResponse) String() string {
 func (*ListServicesResponse) ProtoMessage() {}
 
 func (x *ListServicesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[2]
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -170,7 +389,7 @@ func (x *ListServicesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListServicesResponse.ProtoReflect.Descriptor instead.
 func (*ListServicesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{2}
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ListServicesResponse) GetServices() []*Service {
@@ -180,23 +399,724 @@ func (x *ListServicesResponse) GetServices() []*Service {
 	return nil
 }
 
-var File_proto_catalog_v1_catalog_proto protoreflect.FileDescriptor
+type CreateServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_catalog_v1_catalog_proto_rawDesc = "" +
-	"\n" +
-	"\x1eproto/catalog/v1/catalog.proto\x12\n" +
-	"catalog.v1\"z\n" +
-	"\aService\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x18\n" +
-	"\aversion\x18\x04 \x01(\tR\aversion\x12\x1b\n" +
-	"\tproto_url\x18\x05 \x01(\tR\bprotoUrl\"\x15\n" +
-	"\x13ListServicesRequest\"G\n" +
-	"\x14ListServicesResponse\x12/\n" +
-	"\bservices\x18\x01 \x03(\v2\x13.catalog.v1.ServiceR\bservices2e\n" +
-	"\x0eCatalogService\x12S\n" +
-	"\fListServices\x12\x1f.catalog.v1.ListServicesRequest\x1a .catalog.v1.ListServicesResponse0\x01BGZEgithub.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1;catalogpbb\x06proto3"
+func (x *CreateServiceRequest) Reset() {
+	*x = CreateServiceRequest{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceRequest) ProtoMessage() {}
+
+func (x *CreateServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateServiceRequest) GetService() *Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+type CreateServiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceResponse) Reset() {
+	*x = CreateServiceResponse{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceResponse) ProtoMessage() {}
+
+func (x *CreateServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceResponse.ProtoReflect.Descriptor instead.
+func (*CreateServiceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateServiceResponse) GetService() *Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+// Replaces every field of the service identified by service.id.
+type UpdateServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateServiceRequest) Reset() {
+	*x = UpdateServiceRequest{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServiceRequest) ProtoMessage() {}
+
+func (x *UpdateServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServiceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateServiceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateServiceRequest) GetService() *Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+type UpdateServiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateServiceResponse) Reset() {
+	*x = UpdateServiceResponse{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServiceResponse) ProtoMessage() {}
+
+func (x *UpdateServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServiceResponse.ProtoReflect.Descriptor instead.
+func (*UpdateServiceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateServiceResponse) GetService() *Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+type DeleteServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceRequest) Reset() {
+	*x = DeleteServiceRequest{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceRequest) ProtoMessage() {}
+
+func (x *DeleteServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServiceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteServiceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteServiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceResponse) Reset() {
+	*x = DeleteServiceResponse{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceResponse) ProtoMessage() {}
+
+func (x *DeleteServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServiceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+// A credential a service uses to push its own health samples
+// (health.v1.HealthService/ReportHealth). Only a hash of the secret is stored.
+type ServiceToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceId     string                 `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Prefix        string                 `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"` // first characters of the token, to tell tokens apart
+	CreatedBy     string                 `protobuf:"bytes,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAtMs   int64                  `protobuf:"varint,6,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	LastUsedMs    int64                  `protobuf:"varint,7,opt,name=last_used_ms,json=lastUsedMs,proto3" json:"last_used_ms,omitempty"`
+	Revoked       bool                   `protobuf:"varint,8,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceToken) Reset() {
+	*x = ServiceToken{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceToken) ProtoMessage() {}
+
+func (x *ServiceToken) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceToken.ProtoReflect.Descriptor instead.
+func (*ServiceToken) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ServiceToken) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServiceToken) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ServiceToken) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceToken) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ServiceToken) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ServiceToken) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *ServiceToken) GetLastUsedMs() int64 {
+	if x != nil {
+		return x.LastUsedMs
+	}
+	return 0
+}
+
+func (x *ServiceToken) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+type CreateServiceTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceTokenRequest) Reset() {
+	*x = CreateServiceTokenRequest{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceTokenRequest) ProtoMessage() {}
+
+func (x *CreateServiceTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateServiceTokenRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *CreateServiceTokenRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateServiceTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         *ServiceToken          `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"` // the token itself; it cannot be retrieved again
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceTokenResponse) Reset() {
+	*x = CreateServiceTokenResponse{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceTokenResponse) ProtoMessage() {}
+
+func (x *CreateServiceTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceTokenResponse.ProtoReflect.Descriptor instead.
+func (*CreateServiceTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateServiceTokenResponse) GetToken() *ServiceToken {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *CreateServiceTokenResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListServiceTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServiceTokensRequest) Reset() {
+	*x = ListServiceTokensRequest{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceTokensRequest) ProtoMessage() {}
+
+func (x *ListServiceTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListServiceTokensRequest) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListServiceTokensRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+type ListServiceTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*ServiceToken        `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServiceTokensResponse) Reset() {
+	*x = ListServiceTokensResponse{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceTokensResponse) ProtoMessage() {}
+
+func (x *ListServiceTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListServiceTokensResponse) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListServiceTokensResponse) GetTokens() []*ServiceToken {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type RevokeServiceTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeServiceTokenRequest) Reset() {
+	*x = RevokeServiceTokenRequest{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeServiceTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeServiceTokenRequest) ProtoMessage() {}
+
+func (x *RevokeServiceTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeServiceTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeServiceTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RevokeServiceTokenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeServiceTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeServiceTokenResponse) Reset() {
+	*x = RevokeServiceTokenResponse{}
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeServiceTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeServiceTokenResponse) ProtoMessage() {}
+
+func (x *RevokeServiceTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_catalog_v1_catalog_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeServiceTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeServiceTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_catalog_v1_catalog_proto_rawDescGZIP(), []int{17}
+}
+
+var File_proto_catalog_v1_catalog_proto protoreflect.FileDescriptor
+
+const file_proto_catalog_v1_catalog_proto_rawDesc = "" +
+	"\n" +
+	"\x1eproto/catalog/v1/catalog.proto\x12\n" +
+	"catalog.v1\x1a\x1cgoogle/api/annotations.proto\"\xf6\x02\n" +
+	"\aService\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x1b\n" +
+	"\tproto_url\x18\x05 \x01(\tR\bprotoUrl\x127\n" +
+	"\x06labels\x18\x06 \x03(\v2\x1f.catalog.v1.Service.LabelsEntryR\x06labels\x12\x16\n" +
+	"\x06public\x18\a \x01(\bR\x06public\x120\n" +
+	"\x06scrape\x18\b \x01(\v2\x18.catalog.v1.ScrapeConfigR\x06scrape\x12<\n" +
+	"\n" +
+	"thresholds\x18\t \x01(\v2\x1c.catalog.v1.HealthThresholdsR\n" +
+	"thresholds\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x84\x02\n" +
+	"\x10HealthThresholds\x12.\n" +
+	"\x13degraded_latency_ms\x18\x01 \x01(\x05R\x11degradedLatencyMs\x12&\n" +
+	"\x0fdown_latency_ms\x18\x02 \x01(\x05R\rdownLatencyMs\x12.\n" +
+	"\x13degraded_error_rate\x18\x03 \x01(\x02R\x11degradedErrorRate\x12&\n" +
+	"\x0fdown_error_rate\x18\x04 \x01(\x02R\rdownErrorRate\x12\x1f\n" +
+	"\vraise_after\x18\x05 \x01(\x05R\n" +
+	"raiseAfter\x12\x1f\n" +
+	"\vclear_after\x18\x06 \x01(\x05R\n" +
+	"clearAfter\"\x88\x02\n" +
+	"\fScrapeConfig\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\x12%\n" +
+	"\x0elatency_metric\x18\x03 \x01(\tR\rlatencyMetric\x12)\n" +
+	"\x10latency_quantile\x18\x04 \x01(\x01R\x0flatencyQuantile\x12!\n" +
+	"\flatency_unit\x18\x05 \x01(\tR\vlatencyUnit\x12#\n" +
+	"\rerrors_metric\x18\x06 \x01(\tR\ferrorsMetric\x12!\n" +
+	"\ftotal_metric\x18\a \x01(\tR\vtotalMetric\"\x15\n" +
+	"\x13ListServicesRequest\"G\n" +
+	"\x14ListServicesResponse\x12/\n" +
+	"\bservices\x18\x01 \x03(\v2\x13.catalog.v1.ServiceR\bservices\"E\n" +
+	"\x14CreateServiceRequest\x12-\n" +
+	"\aservice\x18\x01 \x01(\v2\x13.catalog.v1.ServiceR\aservice\"F\n" +
+	"\x15CreateServiceResponse\x12-\n" +
+	"\aservice\x18\x01 \x01(\v2\x13.catalog.v1.ServiceR\aservice\"E\n" +
+	"\x14UpdateServiceRequest\x12-\n" +
+	"\aservice\x18\x01 \x01(\v2\x13.catalog.v1.ServiceR\aservice\"F\n" +
+	"\x15UpdateServiceResponse\x12-\n" +
+	"\aservice\x18\x01 \x01(\v2\x13.catalog.v1.ServiceR\aservice\"&\n" +
+	"\x14DeleteServiceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x17\n" +
+	"\x15DeleteServiceResponse\"\xe8\x01\n" +
+	"\fServiceToken\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x02 \x01(\tR\tserviceId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x04 \x01(\tR\x06prefix\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\tR\tcreatedBy\x12\"\n" +
+	"\rcreated_at_ms\x18\x06 \x01(\x03R\vcreatedAtMs\x12 \n" +
+	"\flast_used_ms\x18\a \x01(\x03R\n" +
+	"lastUsedMs\x12\x18\n" +
+	"\arevoked\x18\b \x01(\bR\arevoked\"N\n" +
+	"\x19CreateServiceTokenRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"d\n" +
+	"\x1aCreateServiceTokenResponse\x12.\n" +
+	"\x05token\x18\x01 \x01(\v2\x18.catalog.v1.ServiceTokenR\x05token\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"9\n" +
+	"\x18ListServiceTokensRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\"M\n" +
+	"\x19ListServiceTokensResponse\x120\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x18.catalog.v1.ServiceTokenR\x06tokens\"+\n" +
+	"\x19RevokeServiceTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1c\n" +
+	"\x1aRevokeServiceTokenResponse2\x8b\a\n" +
+	"\x0eCatalogService\x12i\n" +
+	"\fListServices\x12\x1f.catalog.v1.ListServicesRequest\x1a .catalog.v1.ListServicesResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/services0\x01\x12s\n" +
+	"\rCreateService\x12 .catalog.v1.CreateServiceRequest\x1a!.catalog.v1.CreateServiceResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\aservice\"\f/v1/services\x12\x80\x01\n" +
+	"\rUpdateService\x12 .catalog.v1.UpdateServiceRequest\x1a!.catalog.v1.UpdateServiceResponse\"*\x82\xd3\xe4\x93\x02$:\aservice\x1a\x19/v1/services/{service.id}\x12o\n" +
+	"\rDeleteService\x12 .catalog.v1.DeleteServiceRequest\x1a!.catalog.v1.DeleteServiceResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/services/{id}\x12\x90\x01\n" +
+	"\x12CreateServiceToken\x12%.catalog.v1.CreateServiceTokenRequest\x1a&.catalog.v1.CreateServiceTokenResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /v1/services/{service_id}/tokens\x12\x8a\x01\n" +
+	"\x11ListServiceTokens\x12$.catalog.v1.ListServiceTokensRequest\x1a%.catalog.v1.ListServiceTokensResponse\"(\x82\xd3\xe4\x93\x02\"\x12 /v1/services/{service_id}/tokens\x12\x84\x01\n" +
+	"\x12RevokeServiceToken\x12%.catalog.v1.RevokeServiceTokenRequest\x1a&.catalog.v1.RevokeServiceTokenResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/v1/service-tokens/{id}BGZEgithub.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1;catalogpbb\x06proto3"
 
 var (
 	file_proto_catalog_v1_catalog_proto_rawDescOnce sync.Once
@@ -210,21 +1130,58 @@ func file_proto_catalog_v1_catalog_proto_rawDescGZIP() []byte {
 	return file_proto_catalog_v1_catalog_proto_rawDescData
 }
 
-var file_proto_catalog_v1_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_catalog_v1_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_proto_catalog_v1_catalog_proto_goTypes = []any{
-	(*Service)(nil),              // 0: catalog.v1.Service
-	(*ListServicesRequest)(nil),  // 1: catalog.v1.ListServicesRequest
-	(*ListServicesResponse)(nil), // 2: catalog.v1.ListServicesResponse
+	(*Service)(nil),                    // 0: catalog.v1.Service
+	(*HealthThresholds)(nil),           // 1: catalog.v1.HealthThresholds
+	(*ScrapeConfig)(nil),               // 2: catalog.v1.ScrapeConfig
+	(*ListServicesRequest)(nil),        // 3: catalog.v1.ListServicesRequest
+	(*ListServicesResponse)(nil),       // 4: catalog.v1.ListServicesResponse
+	(*CreateServiceRequest)(nil),       // 5: catalog.v1.CreateServiceRequest
+	(*CreateServiceResponse)(nil),      // 6: catalog.v1.CreateServiceResponse
+	(*UpdateServiceRequest)(nil),       // 7: catalog.v1.UpdateServiceRequest
+	(*UpdateServiceResponse)(nil),      // 8: catalog.v1.UpdateServiceResponse
+	(*DeleteServiceRequest)(nil),       // 9: catalog.v1.DeleteServiceRequest
+	(*DeleteServiceResponse)(nil),      // 10: catalog.v1.DeleteServiceResponse
+	(*ServiceToken)(nil),               // 11: catalog.v1.ServiceToken
+	(*CreateServiceTokenRequest)(nil),  // 12: catalog.v1.CreateServiceTokenRequest
+	(*CreateServiceTokenResponse)(nil), // 13: catalog.v1.CreateServiceTokenResponse
+	(*ListServiceTokensRequest)(nil),   // 14: catalog.v1.ListServiceTokensRequest
+	(*ListServiceTokensResponse)(nil),  // 15: catalog.v1.ListServiceTokensResponse
+	(*RevokeServiceTokenRequest)(nil),  // 16: catalog.v1.RevokeServiceTokenRequest
+	(*RevokeServiceTokenResponse)(nil), // 17: catalog.v1.RevokeServiceTokenResponse
+	nil,                                // 18: catalog.v1.Service.LabelsEntry
 }
 var file_proto_catalog_v1_catalog_proto_depIdxs = []int32{
-	0, // 0: catalog.v1.ListServicesResponse.services:type_name -> catalog.v1.Service
-	1, // 1: catalog.v1.CatalogService.ListServices:input_type -> catalog.v1.ListServicesRequest
-	2, // 2: catalog.v1.CatalogService.ListServices:output_type -> catalog.v1.ListServicesResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	18, // 0: catalog.v1.Service.labels:type_name -> catalog.v1.Service.LabelsEntry
+	2,  // 1: catalog.v1.Service.scrape:type_name -> catalog.v1.ScrapeConfig
+	1,  // 2: catalog.v1.Service.thresholds:type_name -> catalog.v1.HealthThresholds
+	0,  // 3: catalog.v1.ListServicesResponse.services:type_name -> catalog.v1.Service
+	0,  // 4: catalog.v1.CreateServiceRequest.service:type_name -> catalog.v1.Service
+	0,  // 5: catalog.v1.CreateServiceResponse.service:type_name -> catalog.v1.Service
+	0,  // 6: catalog.v1.UpdateServiceRequest.service:type_name -> catalog.v1.Service
+	0,  // 7: catalog.v1.UpdateServiceResponse.service:type_name -> catalog.v1.Service
+	11, // 8: catalog.v1.CreateServiceTokenResponse.token:type_name -> catalog.v1.ServiceToken
+	11, // 9: catalog.v1.ListServiceTokensResponse.tokens:type_name -> catalog.v1.ServiceToken
+	3,  // 10: catalog.v1.CatalogService.ListServices:input_type -> catalog.v1.ListServicesRequest
+	5,  // 11: catalog.v1.CatalogService.CreateService:input_type -> catalog.v1.CreateServiceRequest
+	7,  // 12: catalog.v1.CatalogService.UpdateService:input_type -> catalog.v1.UpdateServiceRequest
+	9,  // 13: catalog.v1.CatalogService.DeleteService:input_type -> catalog.v1.DeleteServiceRequest
+	12, // 14: catalog.v1.CatalogService.CreateServiceToken:input_type -> catalog.v1.CreateServiceTokenRequest
+	14, // 15: catalog.v1.CatalogService.ListServiceTokens:input_type -> catalog.v1.ListServiceTokensRequest
+	16, // 16: catalog.v1.CatalogService.RevokeServiceToken:input_type -> catalog.v1.RevokeServiceTokenRequest
+	4,  // 17: catalog.v1.CatalogService.ListServices:output_type -> catalog.v1.ListServicesResponse
+	6,  // 18: catalog.v1.CatalogService.CreateService:output_type -> catalog.v1.CreateServiceResponse
+	8,  // 19: catalog.v1.CatalogService.UpdateService:output_type -> catalog.v1.UpdateServiceResponse
+	10, // 20: catalog.v1.CatalogService.DeleteService:output_type -> catalog.v1.DeleteServiceResponse
+	13, // 21: catalog.v1.CatalogService.CreateServiceToken:output_type -> catalog.v1.CreateServiceTokenResponse
+	15, // 22: catalog.v1.CatalogService.ListServiceTokens:output_type -> catalog.v1.ListServiceTokensResponse
+	17, // 23: catalog.v1.CatalogService.RevokeServiceToken:output_type -> catalog.v1.RevokeServiceTokenResponse
+	17, // [17:24] is the sub-list for method output_type
+	10, // [10:17] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_proto_catalog_v1_catalog_proto_init() }
@@ -238,7 +1195,7 @@ func file_proto_catalog_v1_catalog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_catalog_v1_catalog_proto_rawDesc), len(file_proto_catalog_v1_catalog_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -7,6 +7,7 @@
 package healthpb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -28,6 +29,8 @@ const (
 	Status_STATUS_UNKNOWN_UNSPECIFIED Status = 0
 	Status_STATUS_UP                  Status = 1
 	Status_STATUS_DOWN                Status = 2
+	Status_STATUS_DEGRADED            Status = 3 // up, but past a degraded threshold
+	Status_STATUS_MAINTENANCE         Status = 4 // a maintenance window covers the service
 )
 
 // Enum value maps for Status.
@@ -36,11 +39,15 @@ var (
 		0: "STATUS_UNKNOWN_UNSPECIFIED",
 		1: "STATUS_UP",
 		2: "STATUS_DOWN",
+		3: "STATUS_DEGRADED",
+		4: "STATUS_MAINTENANCE",
 	}
 	Status_value = map[string]int32{
 		"STATUS_UNKNOWN_UNSPECIFIED": 0,
 		"STATUS_UP":                  1,
 		"STATUS_DOWN":                2,
+		"STATUS_DEGRADED":            3,
+		"STATUS_MAINTENANCE":         4,
 	}
 )
 
@@ -121,7 +128,14 @@ type WatchHealthResponse struct {
 	Status        Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=health.v1.Status" json:"status,omitempty"`
 	LatencyMs     int32                  `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
 	ErrorRate     float32                `protobuf:"fixed32,4,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
-	TimestampMs   int64                  `protobuf:"varint,5,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"` // lower_snake_case
+	TimestampMs   int64                  `protobuf:"varint,5,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`       // lower_snake_case
+	InMaintenance bool                   `protobuf:"varint,6,opt,name=in_maintenance,json=inMaintenance,proto3" json:"in_maintenance,omitempty"` // a maintenance window covers the service
+	// Why the service has this status, e.g. "latency 812ms >= 500ms". Set by
+	// the server; ignored on ReportHealth.
+	Reasons []string `protobuf:"bytes,7,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	// How far the sample is above its seasonal baseline, in standard
+	// deviations (the larger of latency and error rate); set by the server.
+	AnomalyScore  float64 `protobuf:"fixed64,8,opt,name=anomaly_score,json=anomalyScore,proto3" json:"anomaly_score,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -191,14 +205,658 @@ func (x *WatchHealthResponse) GetTimestampMs() int64 {
 	return 0
 }
 
+func (x *WatchHealthResponse) GetInMaintenance() bool {
+	if x != nil {
+		return x.InMaintenance
+	}
+	return false
+}
+
+func (x *WatchHealthResponse) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *WatchHealthResponse) GetAnomalyScore() float64 {
+	if x != nil {
+		return x.AnomalyScore
+	}
+	return 0
+}
+
+type GetHealthHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	FromMs        int64                  `protobuf:"varint,2,opt,name=from_ms,json=fromMs,proto3" json:"from_ms,omitempty"` // default: one hour before to_ms
+	ToMs          int64                  `protobuf:"varint,3,opt,name=to_ms,json=toMs,proto3" json:"to_ms,omitempty"`       // default: now
+	StepMs        int64                  `protobuf:"varint,4,opt,name=step_ms,json=stepMs,proto3" json:"step_ms,omitempty"` // bucket width; default: the range split into ~300 buckets
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHealthHistoryRequest) Reset() {
+	*x = GetHealthHistoryRequest{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHealthHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHealthHistoryRequest) ProtoMessage() {}
+
+func (x *GetHealthHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHealthHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHealthHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetHealthHistoryRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *GetHealthHistoryRequest) GetFromMs() int64 {
+	if x != nil {
+		return x.FromMs
+	}
+	return 0
+}
+
+func (x *GetHealthHistoryRequest) GetToMs() int64 {
+	if x != nil {
+		return x.ToMs
+	}
+	return 0
+}
+
+func (x *GetHealthHistoryRequest) GetStepMs() int64 {
+	if x != nil {
+		return x.StepMs
+	}
+	return 0
+}
+
+// HealthHistoryPoint aggregates the samples of one bucket.
+type HealthHistoryPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartMs       int64                  `protobuf:"varint,1,opt,name=start_ms,json=startMs,proto3" json:"start_ms,omitempty"`
+	Samples       int64                  `protobuf:"varint,2,opt,name=samples,proto3" json:"samples,omitempty"`
+	UpSamples     int64                  `protobuf:"varint,3,opt,name=up_samples,json=upSamples,proto3" json:"up_samples,omitempty"`
+	AvgLatencyMs  float64                `protobuf:"fixed64,4,opt,name=avg_latency_ms,json=avgLatencyMs,proto3" json:"avg_latency_ms,omitempty"`
+	MaxLatencyMs  int32                  `protobuf:"varint,5,opt,name=max_latency_ms,json=maxLatencyMs,proto3" json:"max_latency_ms,omitempty"`
+	AvgErrorRate  float64                `protobuf:"fixed64,6,opt,name=avg_error_rate,json=avgErrorRate,proto3" json:"avg_error_rate,omitempty"`
+	MaxErrorRate  float32                `protobuf:"fixed32,7,opt,name=max_error_rate,json=maxErrorRate,proto3" json:"max_error_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthHistoryPoint) Reset() {
+	*x = HealthHistoryPoint{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthHistoryPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthHistoryPoint) ProtoMessage() {}
+
+func (x *HealthHistoryPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthHistoryPoint.ProtoReflect.Descriptor instead.
+func (*HealthHistoryPoint) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HealthHistoryPoint) GetStartMs() int64 {
+	if x != nil {
+		return x.StartMs
+	}
+	return 0
+}
+
+func (x *HealthHistoryPoint) GetSamples() int64 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *HealthHistoryPoint) GetUpSamples() int64 {
+	if x != nil {
+		return x.UpSamples
+	}
+	return 0
+}
+
+func (x *HealthHistoryPoint) GetAvgLatencyMs() float64 {
+	if x != nil {
+		return x.AvgLatencyMs
+	}
+	return 0
+}
+
+func (x *HealthHistoryPoint) GetMaxLatencyMs() int32 {
+	if x != nil {
+		return x.MaxLatencyMs
+	}
+	return 0
+}
+
+func (x *HealthHistoryPoint) GetAvgErrorRate() float64 {
+	if x != nil {
+		return x.AvgErrorRate
+	}
+	return 0
+}
+
+func (x *HealthHistoryPoint) GetMaxErrorRate() float32 {
+	if x != nil {
+		return x.MaxErrorRate
+	}
+	return 0
+}
+
+type GetHealthHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// step_ms actually used: widened when the range reaches back past the
+	// retention of finer resolutions
+	StepMs        int64                 `protobuf:"varint,1,opt,name=step_ms,json=stepMs,proto3" json:"step_ms,omitempty"`
+	Points        []*HealthHistoryPoint `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHealthHistoryResponse) Reset() {
+	*x = GetHealthHistoryResponse{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHealthHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHealthHistoryResponse) ProtoMessage() {}
+
+func (x *GetHealthHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHealthHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHealthHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetHealthHistoryResponse) GetStepMs() int64 {
+	if x != nil {
+		return x.StepMs
+	}
+	return 0
+}
+
+func (x *GetHealthHistoryResponse) GetPoints() []*HealthHistoryPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+// Samples pushed by a service, authenticated with one of its service tokens
+// in the x-service-token metadata. in_maintenance is set by the server;
+// timestamp_ms defaults to the time of receipt.
+type ReportHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sample        *WatchHealthResponse   `protobuf:"bytes,1,opt,name=sample,proto3" json:"sample,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportHealthRequest) Reset() {
+	*x = ReportHealthRequest{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportHealthRequest) ProtoMessage() {}
+
+func (x *ReportHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportHealthRequest.ProtoReflect.Descriptor instead.
+func (*ReportHealthRequest) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReportHealthRequest) GetSample() *WatchHealthResponse {
+	if x != nil {
+		return x.Sample
+	}
+	return nil
+}
+
+type ReportHealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      int64                  `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportHealthResponse) Reset() {
+	*x = ReportHealthResponse{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportHealthResponse) ProtoMessage() {}
+
+func (x *ReportHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportHealthResponse.ProtoReflect.Descriptor instead.
+func (*ReportHealthResponse) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReportHealthResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+type ReportHealthBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Samples       []*WatchHealthResponse `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportHealthBatchRequest) Reset() {
+	*x = ReportHealthBatchRequest{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportHealthBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportHealthBatchRequest) ProtoMessage() {}
+
+func (x *ReportHealthBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportHealthBatchRequest.ProtoReflect.Descriptor instead.
+func (*ReportHealthBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReportHealthBatchRequest) GetSamples() []*WatchHealthResponse {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type ReportHealthBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      int64                  `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportHealthBatchResponse) Reset() {
+	*x = ReportHealthBatchResponse{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportHealthBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportHealthBatchResponse) ProtoMessage() {}
+
+func (x *ReportHealthBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportHealthBatchResponse.ProtoReflect.Descriptor instead.
+func (*ReportHealthBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReportHealthBatchResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+// A run of consecutive anomalous samples of one metric.
+type AnomalyEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceId     string                 `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Metric        string                 `protobuf:"bytes,3,opt,name=metric,proto3" json:"metric,omitempty"` // latency_ms or error_rate
+	StartedAtMs   int64                  `protobuf:"varint,4,opt,name=started_at_ms,json=startedAtMs,proto3" json:"started_at_ms,omitempty"`
+	EndedAtMs     int64                  `protobuf:"varint,5,opt,name=ended_at_ms,json=endedAtMs,proto3" json:"ended_at_ms,omitempty"` // 0 while ongoing
+	Samples       int64                  `protobuf:"varint,6,opt,name=samples,proto3" json:"samples,omitempty"`
+	MaxScore      float64                `protobuf:"fixed64,7,opt,name=max_score,json=maxScore,proto3" json:"max_score,omitempty"`
+	PeakValue     float64                `protobuf:"fixed64,8,opt,name=peak_value,json=peakValue,proto3" json:"peak_value,omitempty"`
+	Baseline      float64                `protobuf:"fixed64,9,opt,name=baseline,proto3" json:"baseline,omitempty"` // expected value when the anomaly started
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnomalyEvent) Reset() {
+	*x = AnomalyEvent{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnomalyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnomalyEvent) ProtoMessage() {}
+
+func (x *AnomalyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnomalyEvent.ProtoReflect.Descriptor instead.
+func (*AnomalyEvent) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AnomalyEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AnomalyEvent) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *AnomalyEvent) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *AnomalyEvent) GetStartedAtMs() int64 {
+	if x != nil {
+		return x.StartedAtMs
+	}
+	return 0
+}
+
+func (x *AnomalyEvent) GetEndedAtMs() int64 {
+	if x != nil {
+		return x.EndedAtMs
+	}
+	return 0
+}
+
+func (x *AnomalyEvent) GetSamples() int64 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *AnomalyEvent) GetMaxScore() float64 {
+	if x != nil {
+		return x.MaxScore
+	}
+	return 0
+}
+
+func (x *AnomalyEvent) GetPeakValue() float64 {
+	if x != nil {
+		return x.PeakValue
+	}
+	return 0
+}
+
+func (x *AnomalyEvent) GetBaseline() float64 {
+	if x != nil {
+		return x.Baseline
+	}
+	return 0
+}
+
+type ListAnomaliesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"` // default: all services
+	FromMs        int64                  `protobuf:"varint,2,opt,name=from_ms,json=fromMs,proto3" json:"from_ms,omitempty"`         // default: 24 hours before to_ms
+	ToMs          int64                  `protobuf:"varint,3,opt,name=to_ms,json=toMs,proto3" json:"to_ms,omitempty"`               // default: now
+	Ongoing       bool                   `protobuf:"varint,4,opt,name=ongoing,proto3" json:"ongoing,omitempty"`                     // only anomalies that have not ended
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                         // default 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAnomaliesRequest) Reset() {
+	*x = ListAnomaliesRequest{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAnomaliesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAnomaliesRequest) ProtoMessage() {}
+
+func (x *ListAnomaliesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAnomaliesRequest.ProtoReflect.Descriptor instead.
+func (*ListAnomaliesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListAnomaliesRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ListAnomaliesRequest) GetFromMs() int64 {
+	if x != nil {
+		return x.FromMs
+	}
+	return 0
+}
+
+func (x *ListAnomaliesRequest) GetToMs() int64 {
+	if x != nil {
+		return x.ToMs
+	}
+	return 0
+}
+
+func (x *ListAnomaliesRequest) GetOngoing() bool {
+	if x != nil {
+		return x.Ongoing
+	}
+	return false
+}
+
+func (x *ListAnomaliesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListAnomaliesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Anomalies     []*AnomalyEvent        `protobuf:"bytes,1,rep,name=anomalies,proto3" json:"anomalies,omitempty"` // newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAnomaliesResponse) Reset() {
+	*x = ListAnomaliesResponse{}
+	mi := &file_proto_health_v1_health_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAnomaliesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAnomaliesResponse) ProtoMessage() {}
+
+func (x *ListAnomaliesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_health_v1_health_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAnomaliesResponse.ProtoReflect.Descriptor instead.
+func (*ListAnomaliesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_health_v1_health_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAnomaliesResponse) GetAnomalies() []*AnomalyEvent {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
 var File_proto_health_v1_health_proto protoreflect.FileDescriptor
 
 const file_proto_health_v1_health_proto_rawDesc = "" +
 	"\n" +
-	"\x1cproto/health/v1/health.proto\x12\thealth.v1\"3\n" +
+	"\x1cproto/health/v1/health.proto\x12\thealth.v1\x1a\x1cgoogle/api/annotations.proto\"3\n" +
 	"\x12WatchHealthRequest\x12\x1d\n" +
 	"\n" +
-	"service_id\x18\x01 \x01(\tR\tserviceId\"\xc0\x01\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\"\xa6\x02\n" +
 	"\x13WatchHealthResponse\x12\x1d\n" +
 	"\n" +
 	"service_id\x18\x01 \x01(\tR\tserviceId\x12)\n" +
@@ -207,13 +865,69 @@ const file_proto_health_v1_health_proto_rawDesc = "" +
 	"latency_ms\x18\x03 \x01(\x05R\tlatencyMs\x12\x1d\n" +
 	"\n" +
 	"error_rate\x18\x04 \x01(\x02R\terrorRate\x12!\n" +
-	"\ftimestamp_ms\x18\x05 \x01(\x03R\vtimestampMs*H\n" +
+	"\ftimestamp_ms\x18\x05 \x01(\x03R\vtimestampMs\x12%\n" +
+	"\x0ein_maintenance\x18\x06 \x01(\bR\rinMaintenance\x12\x18\n" +
+	"\areasons\x18\a \x03(\tR\areasons\x12#\n" +
+	"\ranomaly_score\x18\b \x01(\x01R\fanomalyScore\"\x7f\n" +
+	"\x17GetHealthHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x17\n" +
+	"\afrom_ms\x18\x02 \x01(\x03R\x06fromMs\x12\x13\n" +
+	"\x05to_ms\x18\x03 \x01(\x03R\x04toMs\x12\x17\n" +
+	"\astep_ms\x18\x04 \x01(\x03R\x06stepMs\"\x80\x02\n" +
+	"\x12HealthHistoryPoint\x12\x19\n" +
+	"\bstart_ms\x18\x01 \x01(\x03R\astartMs\x12\x18\n" +
+	"\asamples\x18\x02 \x01(\x03R\asamples\x12\x1d\n" +
+	"\n" +
+	"up_samples\x18\x03 \x01(\x03R\tupSamples\x12$\n" +
+	"\x0eavg_latency_ms\x18\x04 \x01(\x01R\favgLatencyMs\x12$\n" +
+	"\x0emax_latency_ms\x18\x05 \x01(\x05R\fmaxLatencyMs\x12$\n" +
+	"\x0eavg_error_rate\x18\x06 \x01(\x01R\favgErrorRate\x12$\n" +
+	"\x0emax_error_rate\x18\a \x01(\x02R\fmaxErrorRate\"j\n" +
+	"\x18GetHealthHistoryResponse\x12\x17\n" +
+	"\astep_ms\x18\x01 \x01(\x03R\x06stepMs\x125\n" +
+	"\x06points\x18\x02 \x03(\v2\x1d.health.v1.HealthHistoryPointR\x06points\"M\n" +
+	"\x13ReportHealthRequest\x126\n" +
+	"\x06sample\x18\x01 \x01(\v2\x1e.health.v1.WatchHealthResponseR\x06sample\"2\n" +
+	"\x14ReportHealthResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x03R\baccepted\"T\n" +
+	"\x18ReportHealthBatchRequest\x128\n" +
+	"\asamples\x18\x01 \x03(\v2\x1e.health.v1.WatchHealthResponseR\asamples\"7\n" +
+	"\x19ReportHealthBatchResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x03R\baccepted\"\x8b\x02\n" +
+	"\fAnomalyEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x02 \x01(\tR\tserviceId\x12\x16\n" +
+	"\x06metric\x18\x03 \x01(\tR\x06metric\x12\"\n" +
+	"\rstarted_at_ms\x18\x04 \x01(\x03R\vstartedAtMs\x12\x1e\n" +
+	"\vended_at_ms\x18\x05 \x01(\x03R\tendedAtMs\x12\x18\n" +
+	"\asamples\x18\x06 \x01(\x03R\asamples\x12\x1b\n" +
+	"\tmax_score\x18\a \x01(\x01R\bmaxScore\x12\x1d\n" +
+	"\n" +
+	"peak_value\x18\b \x01(\x01R\tpeakValue\x12\x1a\n" +
+	"\bbaseline\x18\t \x01(\x01R\bbaseline\"\x93\x01\n" +
+	"\x14ListAnomaliesRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x17\n" +
+	"\afrom_ms\x18\x02 \x01(\x03R\x06fromMs\x12\x13\n" +
+	"\x05to_ms\x18\x03 \x01(\x03R\x04toMs\x12\x18\n" +
+	"\aongoing\x18\x04 \x01(\bR\aongoing\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"N\n" +
+	"\x15ListAnomaliesResponse\x125\n" +
+	"\tanomalies\x18\x01 \x03(\v2\x17.health.v1.AnomalyEventR\tanomalies*u\n" +
 	"\x06Status\x12\x1e\n" +
 	"\x1aSTATUS_UNKNOWN_UNSPECIFIED\x10\x00\x12\r\n" +
 	"\tSTATUS_UP\x10\x01\x12\x0f\n" +
-	"\vSTATUS_DOWN\x10\x022_\n" +
-	"\rHealthService\x12N\n" +
-	"\vWatchHealth\x12\x1d.health.v1.WatchHealthRequest\x1a\x1e.health.v1.WatchHealthResponse0\x01BEZCgithub.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1;healthpbb\x06proto3"
+	"\vSTATUS_DOWN\x10\x02\x12\x13\n" +
+	"\x0fSTATUS_DEGRADED\x10\x03\x12\x16\n" +
+	"\x12STATUS_MAINTENANCE\x10\x042\xd6\x04\n" +
+	"\rHealthService\x12x\n" +
+	"\vWatchHealth\x12\x1d.health.v1.WatchHealthRequest\x1a\x1e.health.v1.WatchHealthResponse\"(\x82\xd3\xe4\x93\x02\"\x12 /v1/services/{service_id}/health0\x01\x12\x8d\x01\n" +
+	"\x10GetHealthHistory\x12\".health.v1.GetHealthHistoryRequest\x1a#.health.v1.GetHealthHistoryResponse\"0\x82\xd3\xe4\x93\x02*\x12(/v1/services/{service_id}/health/history\x12Q\n" +
+	"\fReportHealth\x12\x1e.health.v1.ReportHealthRequest\x1a\x1f.health.v1.ReportHealthResponse(\x01\x12}\n" +
+	"\x11ReportHealthBatch\x12#.health.v1.ReportHealthBatchRequest\x1a$.health.v1.ReportHealthBatchResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/health/reports\x12i\n" +
+	"\rListAnomalies\x12\x1f.health.v1.ListAnomaliesRequest\x1a .health.v1.ListAnomaliesResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/anomaliesBEZCgithub.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1;healthpbb\x06proto3"
 
 var (
 	file_proto_health_v1_health_proto_rawDescOnce sync.Once
@@ -228,21 +942,43 @@ func file_proto_health_v1_health_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_health_v1_health_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_health_v1_health_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_health_v1_health_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_proto_health_v1_health_proto_goTypes = []any{
-	(Status)(0),                 // 0: health.v1.Status
-	(*WatchHealthRequest)(nil),  // 1: health.v1.WatchHealthRequest
-	(*WatchHealthResponse)(nil), // 2: health.v1.WatchHealthResponse
+	(Status)(0),                       // 0: health.v1.Status
+	(*WatchHealthRequest)(nil),        // 1: health.v1.WatchHealthRequest
+	(*WatchHealthResponse)(nil),       // 2: health.v1.WatchHealthResponse
+	(*GetHealthHistoryRequest)(nil),   // 3: health.v1.GetHealthHistoryRequest
+	(*HealthHistoryPoint)(nil),        // 4: health.v1.HealthHistoryPoint
+	(*GetHealthHistoryResponse)(nil),  // 5: health.v1.GetHealthHistoryResponse
+	(*ReportHealthRequest)(nil),       // 6: health.v1.ReportHealthRequest
+	(*ReportHealthResponse)(nil),      // 7: health.v1.ReportHealthResponse
+	(*ReportHealthBatchRequest)(nil),  // 8: health.v1.ReportHealthBatchRequest
+	(*ReportHealthBatchResponse)(nil), // 9: health.v1.ReportHealthBatchResponse
+	(*AnomalyEvent)(nil),              // 10: health.v1.AnomalyEvent
+	(*ListAnomaliesRequest)(nil),      // 11: health.v1.ListAnomaliesRequest
+	(*ListAnomaliesResponse)(nil),     // 12: health.v1.ListAnomaliesResponse
 }
 var file_proto_health_v1_health_proto_depIdxs = []int32{
-	0, // 0: health.v1.WatchHealthResponse.status:type_name -> health.v1.Status
-	1, // 1: health.v1.HealthService.WatchHealth:input_type -> health.v1.WatchHealthRequest
-	2, // 2: health.v1.HealthService.WatchHealth:output_type -> health.v1.WatchHealthResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: health.v1.WatchHealthResponse.status:type_name -> health.v1.Status
+	4,  // 1: health.v1.GetHealthHistoryResponse.points:type_name -> health.v1.HealthHistoryPoint
+	2,  // 2: health.v1.ReportHealthRequest.sample:type_name -> health.v1.WatchHealthResponse
+	2,  // 3: health.v1.ReportHealthBatchRequest.samples:type_name -> health.v1.WatchHealthResponse
+	10, // 4: health.v1.ListAnomaliesResponse.anomalies:type_name -> health.v1.AnomalyEvent
+	1,  // 5: health.v1.HealthService.WatchHealth:input_type -> health.v1.WatchHealthRequest
+	3,  // 6: health.v1.HealthService.GetHealthHistory:input_type -> health.v1.GetHealthHistoryRequest
+	6,  // 7: health.v1.HealthService.ReportHealth:input_type -> health.v1.ReportHealthRequest
+	8,  // 8: health.v1.HealthService.ReportHealthBatch:input_type -> health.v1.ReportHealthBatchRequest
+	11, // 9: health.v1.HealthService.ListAnomalies:input_type -> health.v1.ListAnomaliesRequest
+	2,  // 10: health.v1.HealthService.WatchHealth:output_type -> health.v1.WatchHealthResponse
+	5,  // 11: health.v1.HealthService.GetHealthHistory:output_type -> health.v1.GetHealthHistoryResponse
+	7,  // 12: health.v1.HealthService.ReportHealth:output_type -> health.v1.ReportHealthResponse
+	9,  // 13: health.v1.HealthService.ReportHealthBatch:output_type -> health.v1.ReportHealthBatchResponse
+	12, // 14: health.v1.HealthService.ListAnomalies:output_type -> health.v1.ListAnomaliesResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_proto_health_v1_health_proto_init() }
@@ -256,7 +992,7 @@ func file_proto_health_v1_health_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_health_v1_health_proto_rawDesc), len(file_proto_health_v1_health_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   2,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	HealthService_WatchHealth_FullMethodName = "/health.v1.HealthService/WatchHealth"
+	HealthService_WatchHealth_FullMethodName       = "/health.v1.HealthService/WatchHealth"
+	HealthService_GetHealthHistory_FullMethodName  = "/health.v1.HealthService/GetHealthHistory"
+	HealthService_ReportHealth_FullMethodName      = "/health.v1.HealthService/ReportHealth"
+	HealthService_ReportHealthBatch_FullMethodName = "/health.v1.HealthService/ReportHealthBatch"
+	HealthService_ListAnomalies_FullMethodName     = "/health.v1.HealthService/ListAnomalies"
 )
 
 // HealthServiceClient is the client API for HealthService service.
@@ -27,6 +31,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type HealthServiceClient interface {
 	WatchHealth(ctx context.Context, in *WatchHealthRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchHealthResponse], error)
+	GetHealthHistory(ctx context.Context, in *GetHealthHistoryRequest, opts ...grpc.CallOption) (*GetHealthHistoryResponse, error)
+	// Client streaming; use ReportHealthBatch over HTTP.
+	ReportHealth(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ReportHealthRequest, ReportHealthResponse], error)
+	ReportHealthBatch(ctx context.Context, in *ReportHealthBatchRequest, opts ...grpc.CallOption) (*ReportHealthBatchResponse, error)
+	ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*ListAnomaliesResponse, error)
 }
 
 type healthServiceClient struct {
@@ -56,11 +65,59 @@ func (c *healthServiceClient) WatchHealth(ctx context.Context, in *WatchHealthRe
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type HealthService_WatchHealthClient = grpc.ServerStreamingClient[WatchHealthResponse]
 
+func (c *healthServiceClient) GetHealthHistory(ctx context.Context, in *GetHealthHistoryRequest, opts ...grpc.CallOption) (*GetHealthHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHealthHistoryResponse)
+	err := c.cc.Invoke(ctx, HealthService_GetHealthHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) ReportHealth(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ReportHealthRequest, ReportHealthResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &HealthService_ServiceDesc.Streams[1], HealthService_ReportHealth_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReportHealthRequest, ReportHealthResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type HealthService_ReportHealthClient = grpc.ClientStreamingClient[ReportHealthRequest, ReportHealthResponse]
+
+func (c *healthServiceClient) ReportHealthBatch(ctx context.Context, in *ReportHealthBatchRequest, opts ...grpc.CallOption) (*ReportHealthBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportHealthBatchResponse)
+	err := c.cc.Invoke(ctx, HealthService_ReportHealthBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*ListAnomaliesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAnomaliesResponse)
+	err := c.cc.Invoke(ctx, HealthService_ListAnomalies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HealthServiceServer is the server API for HealthService service.
 // All implementations must embed UnimplementedHealthServiceServer
 // for forward compatibility.
 type HealthServiceServer interface {
 	WatchHealth(*WatchHealthRequest, grpc.ServerStreamingServer[WatchHealthResponse]) error
+	GetHealthHistory(context.Context, *GetHealthHistoryRequest) (*GetHealthHistoryResponse, error)
+	// Client streaming; use ReportHealthBatch over HTTP.
+	ReportHealth(grpc.ClientStreamingServer[ReportHealthRequest, ReportHealthResponse]) error
+	ReportHealthBatch(context.Context, *ReportHealthBatchRequest) (*ReportHealthBatchResponse, error)
+	ListAnomalies(context.Context, *ListAnomaliesRequest) (*ListAnomaliesResponse, error)
 	mustEmbedUnimplementedHealthServiceServer()
 }
 
@@ -74,6 +131,18 @@ type UnimplementedHealthServiceServer struct{}
 func (UnimplementedHealthServiceServer) WatchHealth(*WatchHealthRequest, grpc.ServerStreamingServer[WatchHealthResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method WatchHealth not implemented")
 }
+func (UnimplementedHealthServiceServer) GetHealthHistory(context.Context, *GetHealthHistoryRequest) (*GetHealthHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHealthHistory not implemented")
+}
+func (UnimplementedHealthServiceServer) ReportHealth(grpc.ClientStreamingServer[ReportHealthRequest, ReportHealthResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ReportHealth not implemented")
+}
+func (UnimplementedHealthServiceServer) ReportHealthBatch(context.Context, *ReportHealthBatchRequest) (*ReportHealthBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportHealthBatch not implemented")
+}
+func (UnimplementedHealthServiceServer) ListAnomalies(context.Context, *ListAnomaliesRequest) (*ListAnomaliesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnomalies not implemented")
+}
 func (UnimplementedHealthServiceServer) mustEmbedUnimplementedHealthServiceServer() {}
 func (UnimplementedHealthServiceServer) testEmbeddedByValue()                       {}
 
@@ -106,19 +175,98 @@ func _HealthService_WatchHealth_Handler(srv interface{}, stream grpc.ServerStrea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type HealthService_WatchHealthServer = grpc.ServerStreamingServer[WatchHealthResponse]
 
+func _HealthService_GetHealthHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHealthHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).GetHealthHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_GetHealthHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).GetHealthHistory(ctx, req.(*GetHealthHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_ReportHealth_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HealthServiceServer).ReportHealth(&grpc.GenericServerStream[ReportHealthRequest, ReportHealthResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type HealthService_ReportHealthServer = grpc.ClientStreamingServer[ReportHealthRequest, ReportHealthResponse]
+
+func _HealthService_ReportHealthBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportHealthBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).ReportHealthBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_ReportHealthBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).ReportHealthBatch(ctx, req.(*ReportHealthBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_ListAnomalies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAnomaliesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).ListAnomalies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_ListAnomalies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).ListAnomalies(ctx, req.(*ListAnomaliesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HealthService_ServiceDesc is the grpc.ServiceDesc for HealthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var HealthService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "health.v1.HealthService",
 	HandlerType: (*HealthServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHealthHistory",
+			Handler:    _HealthService_GetHealthHistory_Handler,
+		},
+		{
+			MethodName: "ReportHealthBatch",
+			Handler:    _HealthService_ReportHealthBatch_Handler,
+		},
+		{
+			MethodName: "ListAnomalies",
+			Handler:    _HealthService_ListAnomalies_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "WatchHealth",
 			Handler:       _HealthService_WatchHealth_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ReportHealth",
+			Handler:       _HealthService_ReportHealth_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/health/v1/health.proto",
 }
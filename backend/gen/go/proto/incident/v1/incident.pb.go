@@ -0,0 +1,1014 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/incident/v1/incident.proto
+
+package incidentpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_SEV1        Severity = 1 // critical, customer-facing outage
+	Severity_SEVERITY_SEV2        Severity = 2
+	Severity_SEVERITY_SEV3        Severity = 3
+	Severity_SEVERITY_SEV4        Severity = 4 // minor
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_SEV1",
+		2: "SEVERITY_SEV2",
+		3: "SEVERITY_SEV3",
+		4: "SEVERITY_SEV4",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_SEV1":        1,
+		"SEVERITY_SEV2":        2,
+		"SEVERITY_SEV3":        3,
+		"SEVERITY_SEV4":        4,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_incident_v1_incident_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_proto_incident_v1_incident_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{0}
+}
+
+// Incidents move forward through these states; a resolved incident can be
+// reopened.
+type IncidentState int32
+
+const (
+	IncidentState_INCIDENT_STATE_UNSPECIFIED  IncidentState = 0
+	IncidentState_INCIDENT_STATE_OPEN         IncidentState = 1
+	IncidentState_INCIDENT_STATE_ACKNOWLEDGED IncidentState = 2
+	IncidentState_INCIDENT_STATE_MITIGATED    IncidentState = 3
+	IncidentState_INCIDENT_STATE_RESOLVED     IncidentState = 4
+)
+
+// Enum value maps for IncidentState.
+var (
+	IncidentState_name = map[int32]string{
+		0: "INCIDENT_STATE_UNSPECIFIED",
+		1: "INCIDENT_STATE_OPEN",
+		2: "INCIDENT_STATE_ACKNOWLEDGED",
+		3: "INCIDENT_STATE_MITIGATED",
+		4: "INCIDENT_STATE_RESOLVED",
+	}
+	IncidentState_value = map[string]int32{
+		"INCIDENT_STATE_UNSPECIFIED":  0,
+		"INCIDENT_STATE_OPEN":         1,
+		"INCIDENT_STATE_ACKNOWLEDGED": 2,
+		"INCIDENT_STATE_MITIGATED":    3,
+		"INCIDENT_STATE_RESOLVED":     4,
+	}
+)
+
+func (x IncidentState) Enum() *IncidentState {
+	p := new(IncidentState)
+	*p = x
+	return p
+}
+
+func (x IncidentState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IncidentState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_incident_v1_incident_proto_enumTypes[1].Descriptor()
+}
+
+func (IncidentState) Type() protoreflect.EnumType {
+	return &file_proto_incident_v1_incident_proto_enumTypes[1]
+}
+
+func (x IncidentState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IncidentState.Descriptor instead.
+func (IncidentState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{1}
+}
+
+type TimelineEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AtMs          int64                  `protobuf:"varint,2,opt,name=at_ms,json=atMs,proto3" json:"at_ms,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"` // authenticated principal, or "system"
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`   // "created", "state_changed", "severity_changed" or "note"
+	FromState     IncidentState          `protobuf:"varint,5,opt,name=from_state,json=fromState,proto3,enum=incident.v1.IncidentState" json:"from_state,omitempty"`
+	ToState       IncidentState          `protobuf:"varint,6,opt,name=to_state,json=toState,proto3,enum=incident.v1.IncidentState" json:"to_state,omitempty"`
+	Note          string                 `protobuf:"bytes,7,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimelineEntry) Reset() {
+	*x = TimelineEntry{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimelineEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineEntry) ProtoMessage() {}
+
+func (x *TimelineEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineEntry.ProtoReflect.Descriptor instead.
+func (*TimelineEntry) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TimelineEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TimelineEntry) GetAtMs() int64 {
+	if x != nil {
+		return x.AtMs
+	}
+	return 0
+}
+
+func (x *TimelineEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *TimelineEntry) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TimelineEntry) GetFromState() IncidentState {
+	if x != nil {
+		return x.FromState
+	}
+	return IncidentState_INCIDENT_STATE_UNSPECIFIED
+}
+
+func (x *TimelineEntry) GetToState() IncidentState {
+	if x != nil {
+		return x.ToState
+	}
+	return IncidentState_INCIDENT_STATE_UNSPECIFIED
+}
+
+func (x *TimelineEntry) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type Incident struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Summary       string                 `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	ServiceIds    []string               `protobuf:"bytes,4,rep,name=service_ids,json=serviceIds,proto3" json:"service_ids,omitempty"`
+	Severity      Severity               `protobuf:"varint,5,opt,name=severity,proto3,enum=incident.v1.Severity" json:"severity,omitempty"`
+	State         IncidentState          `protobuf:"varint,6,opt,name=state,proto3,enum=incident.v1.IncidentState" json:"state,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAtMs   int64                  `protobuf:"varint,8,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	UpdatedAtMs   int64                  `protobuf:"varint,9,opt,name=updated_at_ms,json=updatedAtMs,proto3" json:"updated_at_ms,omitempty"`
+	ResolvedAtMs  int64                  `protobuf:"varint,10,opt,name=resolved_at_ms,json=resolvedAtMs,proto3" json:"resolved_at_ms,omitempty"`
+	AutoOpened    bool                   `protobuf:"varint,11,opt,name=auto_opened,json=autoOpened,proto3" json:"auto_opened,omitempty"`
+	Timeline      []*TimelineEntry       `protobuf:"bytes,12,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Incident) Reset() {
+	*x = Incident{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Incident) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Incident) ProtoMessage() {}
+
+func (x *Incident) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Incident.ProtoReflect.Descriptor instead.
+func (*Incident) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Incident) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Incident) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Incident) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Incident) GetServiceIds() []string {
+	if x != nil {
+		return x.ServiceIds
+	}
+	return nil
+}
+
+func (x *Incident) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Incident) GetState() IncidentState {
+	if x != nil {
+		return x.State
+	}
+	return IncidentState_INCIDENT_STATE_UNSPECIFIED
+}
+
+func (x *Incident) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Incident) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *Incident) GetUpdatedAtMs() int64 {
+	if x != nil {
+		return x.UpdatedAtMs
+	}
+	return 0
+}
+
+func (x *Incident) GetResolvedAtMs() int64 {
+	if x != nil {
+		return x.ResolvedAtMs
+	}
+	return 0
+}
+
+func (x *Incident) GetAutoOpened() bool {
+	if x != nil {
+		return x.AutoOpened
+	}
+	return false
+}
+
+func (x *Incident) GetTimeline() []*TimelineEntry {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+type CreateIncidentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Summary       string                 `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	ServiceIds    []string               `protobuf:"bytes,3,rep,name=service_ids,json=serviceIds,proto3" json:"service_ids,omitempty"`
+	Severity      Severity               `protobuf:"varint,4,opt,name=severity,proto3,enum=incident.v1.Severity" json:"severity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateIncidentRequest) Reset() {
+	*x = CreateIncidentRequest{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateIncidentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIncidentRequest) ProtoMessage() {}
+
+func (x *CreateIncidentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIncidentRequest.ProtoReflect.Descriptor instead.
+func (*CreateIncidentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateIncidentRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateIncidentRequest) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *CreateIncidentRequest) GetServiceIds() []string {
+	if x != nil {
+		return x.ServiceIds
+	}
+	return nil
+}
+
+func (x *CreateIncidentRequest) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+type CreateIncidentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incident      *Incident              `protobuf:"bytes,1,opt,name=incident,proto3" json:"incident,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateIncidentResponse) Reset() {
+	*x = CreateIncidentResponse{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateIncidentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIncidentResponse) ProtoMessage() {}
+
+func (x *CreateIncidentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIncidentResponse.ProtoReflect.Descriptor instead.
+func (*CreateIncidentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateIncidentResponse) GetIncident() *Incident {
+	if x != nil {
+		return x.Incident
+	}
+	return nil
+}
+
+// Applies every non-default field: a state transition, a severity change
+// and/or a note that is added to the timeline.
+type UpdateIncidentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State         IncidentState          `protobuf:"varint,2,opt,name=state,proto3,enum=incident.v1.IncidentState" json:"state,omitempty"`
+	Severity      Severity               `protobuf:"varint,3,opt,name=severity,proto3,enum=incident.v1.Severity" json:"severity,omitempty"`
+	Note          string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateIncidentRequest) Reset() {
+	*x = UpdateIncidentRequest{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateIncidentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateIncidentRequest) ProtoMessage() {}
+
+func (x *UpdateIncidentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateIncidentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateIncidentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateIncidentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateIncidentRequest) GetState() IncidentState {
+	if x != nil {
+		return x.State
+	}
+	return IncidentState_INCIDENT_STATE_UNSPECIFIED
+}
+
+func (x *UpdateIncidentRequest) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *UpdateIncidentRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type UpdateIncidentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incident      *Incident              `protobuf:"bytes,1,opt,name=incident,proto3" json:"incident,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateIncidentResponse) Reset() {
+	*x = UpdateIncidentResponse{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateIncidentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateIncidentResponse) ProtoMessage() {}
+
+func (x *UpdateIncidentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateIncidentResponse.ProtoReflect.Descriptor instead.
+func (*UpdateIncidentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateIncidentResponse) GetIncident() *Incident {
+	if x != nil {
+		return x.Incident
+	}
+	return nil
+}
+
+type GetIncidentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIncidentRequest) Reset() {
+	*x = GetIncidentRequest{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIncidentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIncidentRequest) ProtoMessage() {}
+
+func (x *GetIncidentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIncidentRequest.ProtoReflect.Descriptor instead.
+func (*GetIncidentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetIncidentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetIncidentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incident      *Incident              `protobuf:"bytes,1,opt,name=incident,proto3" json:"incident,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIncidentResponse) Reset() {
+	*x = GetIncidentResponse{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIncidentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIncidentResponse) ProtoMessage() {}
+
+func (x *GetIncidentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIncidentResponse.ProtoReflect.Descriptor instead.
+func (*GetIncidentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetIncidentResponse) GetIncident() *Incident {
+	if x != nil {
+		return x.Incident
+	}
+	return nil
+}
+
+type ListIncidentsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId       string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"` // optional filter
+	IncludeResolved bool                   `protobuf:"varint,2,opt,name=include_resolved,json=includeResolved,proto3" json:"include_resolved,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListIncidentsRequest) Reset() {
+	*x = ListIncidentsRequest{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncidentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncidentsRequest) ProtoMessage() {}
+
+func (x *ListIncidentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncidentsRequest.ProtoReflect.Descriptor instead.
+func (*ListIncidentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListIncidentsRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ListIncidentsRequest) GetIncludeResolved() bool {
+	if x != nil {
+		return x.IncludeResolved
+	}
+	return false
+}
+
+type ListIncidentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incidents     []*Incident            `protobuf:"bytes,1,rep,name=incidents,proto3" json:"incidents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncidentsResponse) Reset() {
+	*x = ListIncidentsResponse{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncidentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncidentsResponse) ProtoMessage() {}
+
+func (x *ListIncidentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncidentsResponse.ProtoReflect.Descriptor instead.
+func (*ListIncidentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListIncidentsResponse) GetIncidents() []*Incident {
+	if x != nil {
+		return x.Incidents
+	}
+	return nil
+}
+
+type WatchIncidentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchIncidentsRequest) Reset() {
+	*x = WatchIncidentsRequest{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchIncidentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchIncidentsRequest) ProtoMessage() {}
+
+func (x *WatchIncidentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchIncidentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchIncidentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{10}
+}
+
+// The current state of an incident that was created or changed. The stream
+// starts with every unresolved incident.
+type WatchIncidentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incident      *Incident              `protobuf:"bytes,1,opt,name=incident,proto3" json:"incident,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchIncidentsResponse) Reset() {
+	*x = WatchIncidentsResponse{}
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchIncidentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchIncidentsResponse) ProtoMessage() {}
+
+func (x *WatchIncidentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_incident_v1_incident_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchIncidentsResponse.ProtoReflect.Descriptor instead.
+func (*WatchIncidentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_incident_v1_incident_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchIncidentsResponse) GetIncident() *Incident {
+	if x != nil {
+		return x.Incident
+	}
+	return nil
+}
+
+var File_proto_incident_v1_incident_proto protoreflect.FileDescriptor
+
+const file_proto_incident_v1_incident_proto_rawDesc = "" +
+	"\n" +
+	" proto/incident/v1/incident.proto\x12\vincident.v1\"\xe4\x01\n" +
+	"\rTimelineEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x13\n" +
+	"\x05at_ms\x18\x02 \x01(\x03R\x04atMs\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x129\n" +
+	"\n" +
+	"from_state\x18\x05 \x01(\x0e2\x1a.incident.v1.IncidentStateR\tfromState\x125\n" +
+	"\bto_state\x18\x06 \x01(\x0e2\x1a.incident.v1.IncidentStateR\atoState\x12\x12\n" +
+	"\x04note\x18\a \x01(\tR\x04note\"\xb6\x03\n" +
+	"\bIncident\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x03 \x01(\tR\asummary\x12\x1f\n" +
+	"\vservice_ids\x18\x04 \x03(\tR\n" +
+	"serviceIds\x121\n" +
+	"\bseverity\x18\x05 \x01(\x0e2\x15.incident.v1.SeverityR\bseverity\x120\n" +
+	"\x05state\x18\x06 \x01(\x0e2\x1a.incident.v1.IncidentStateR\x05state\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\a \x01(\tR\tcreatedBy\x12\"\n" +
+	"\rcreated_at_ms\x18\b \x01(\x03R\vcreatedAtMs\x12\"\n" +
+	"\rupdated_at_ms\x18\t \x01(\x03R\vupdatedAtMs\x12$\n" +
+	"\x0eresolved_at_ms\x18\n" +
+	" \x01(\x03R\fresolvedAtMs\x12\x1f\n" +
+	"\vauto_opened\x18\v \x01(\bR\n" +
+	"autoOpened\x126\n" +
+	"\btimeline\x18\f \x03(\v2\x1a.incident.v1.TimelineEntryR\btimeline\"\x9b\x01\n" +
+	"\x15CreateIncidentRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\x12\x1f\n" +
+	"\vservice_ids\x18\x03 \x03(\tR\n" +
+	"serviceIds\x121\n" +
+	"\bseverity\x18\x04 \x01(\x0e2\x15.incident.v1.SeverityR\bseverity\"K\n" +
+	"\x16CreateIncidentResponse\x121\n" +
+	"\bincident\x18\x01 \x01(\v2\x15.incident.v1.IncidentR\bincident\"\xa0\x01\n" +
+	"\x15UpdateIncidentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x1a.incident.v1.IncidentStateR\x05state\x121\n" +
+	"\bseverity\x18\x03 \x01(\x0e2\x15.incident.v1.SeverityR\bseverity\x12\x12\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\"K\n" +
+	"\x16UpdateIncidentResponse\x121\n" +
+	"\bincident\x18\x01 \x01(\v2\x15.incident.v1.IncidentR\bincident\"$\n" +
+	"\x12GetIncidentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"H\n" +
+	"\x13GetIncidentResponse\x121\n" +
+	"\bincident\x18\x01 \x01(\v2\x15.incident.v1.IncidentR\bincident\"`\n" +
+	"\x14ListIncidentsRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12)\n" +
+	"\x10include_resolved\x18\x02 \x01(\bR\x0fincludeResolved\"L\n" +
+	"\x15ListIncidentsResponse\x123\n" +
+	"\tincidents\x18\x01 \x03(\v2\x15.incident.v1.IncidentR\tincidents\"\x17\n" +
+	"\x15WatchIncidentsRequest\"K\n" +
+	"\x16WatchIncidentsResponse\x121\n" +
+	"\bincident\x18\x01 \x01(\v2\x15.incident.v1.IncidentR\bincident*p\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSEVERITY_SEV1\x10\x01\x12\x11\n" +
+	"\rSEVERITY_SEV2\x10\x02\x12\x11\n" +
+	"\rSEVERITY_SEV3\x10\x03\x12\x11\n" +
+	"\rSEVERITY_SEV4\x10\x04*\xa4\x01\n" +
+	"\rIncidentState\x12\x1e\n" +
+	"\x1aINCIDENT_STATE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13INCIDENT_STATE_OPEN\x10\x01\x12\x1f\n" +
+	"\x1bINCIDENT_STATE_ACKNOWLEDGED\x10\x02\x12\x1c\n" +
+	"\x18INCIDENT_STATE_MITIGATED\x10\x03\x12\x1b\n" +
+	"\x17INCIDENT_STATE_RESOLVED\x10\x042\xce\x03\n" +
+	"\x0fIncidentService\x12Y\n" +
+	"\x0eCreateIncident\x12\".incident.v1.CreateIncidentRequest\x1a#.incident.v1.CreateIncidentResponse\x12Y\n" +
+	"\x0eUpdateIncident\x12\".incident.v1.UpdateIncidentRequest\x1a#.incident.v1.UpdateIncidentResponse\x12P\n" +
+	"\vGetIncident\x12\x1f.incident.v1.GetIncidentRequest\x1a .incident.v1.GetIncidentResponse\x12V\n" +
+	"\rListIncidents\x12!.incident.v1.ListIncidentsRequest\x1a\".incident.v1.ListIncidentsResponse\x12[\n" +
+	"\x0eWatchIncidents\x12\".incident.v1.WatchIncidentsRequest\x1a#.incident.v1.WatchIncidentsResponse0\x01BIZGgithub.com/Prof-Rosario-UCLA/team15/gen/go/proto/incident/v1;incidentpbb\x06proto3"
+
+var (
+	file_proto_incident_v1_incident_proto_rawDescOnce sync.Once
+	file_proto_incident_v1_incident_proto_rawDescData []byte
+)
+
+func file_proto_incident_v1_incident_proto_rawDescGZIP() []byte {
+	file_proto_incident_v1_incident_proto_rawDescOnce.Do(func() {
+		file_proto_incident_v1_incident_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_incident_v1_incident_proto_rawDesc), len(file_proto_incident_v1_incident_proto_rawDesc)))
+	})
+	return file_proto_incident_v1_incident_proto_rawDescData
+}
+
+var file_proto_incident_v1_incident_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_incident_v1_incident_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_proto_incident_v1_incident_proto_goTypes = []any{
+	(Severity)(0),                  // 0: incident.v1.Severity
+	(IncidentState)(0),             // 1: incident.v1.IncidentState
+	(*TimelineEntry)(nil),          // 2: incident.v1.TimelineEntry
+	(*Incident)(nil),               // 3: incident.v1.Incident
+	(*CreateIncidentRequest)(nil),  // 4: incident.v1.CreateIncidentRequest
+	(*CreateIncidentResponse)(nil), // 5: incident.v1.CreateIncidentResponse
+	(*UpdateIncidentRequest)(nil),  // 6: incident.v1.UpdateIncidentRequest
+	(*UpdateIncidentResponse)(nil), // 7: incident.v1.UpdateIncidentResponse
+	(*GetIncidentRequest)(nil),     // 8: incident.v1.GetIncidentRequest
+	(*GetIncidentResponse)(nil),    // 9: incident.v1.GetIncidentResponse
+	(*ListIncidentsRequest)(nil),   // 10: incident.v1.ListIncidentsRequest
+	(*ListIncidentsResponse)(nil),  // 11: incident.v1.ListIncidentsResponse
+	(*WatchIncidentsRequest)(nil),  // 12: incident.v1.WatchIncidentsRequest
+	(*WatchIncidentsResponse)(nil), // 13: incident.v1.WatchIncidentsResponse
+}
+var file_proto_incident_v1_incident_proto_depIdxs = []int32{
+	1,  // 0: incident.v1.TimelineEntry.from_state:type_name -> incident.v1.IncidentState
+	1,  // 1: incident.v1.TimelineEntry.to_state:type_name -> incident.v1.IncidentState
+	0,  // 2: incident.v1.Incident.severity:type_name -> incident.v1.Severity
+	1,  // 3: incident.v1.Incident.state:type_name -> incident.v1.IncidentState
+	2,  // 4: incident.v1.Incident.timeline:type_name -> incident.v1.TimelineEntry
+	0,  // 5: incident.v1.CreateIncidentRequest.severity:type_name -> incident.v1.Severity
+	3,  // 6: incident.v1.CreateIncidentResponse.incident:type_name -> incident.v1.Incident
+	1,  // 7: incident.v1.UpdateIncidentRequest.state:type_name -> incident.v1.IncidentState
+	0,  // 8: incident.v1.UpdateIncidentRequest.severity:type_name -> incident.v1.Severity
+	3,  // 9: incident.v1.UpdateIncidentResponse.incident:type_name -> incident.v1.Incident
+	3,  // 10: incident.v1.GetIncidentResponse.incident:type_name -> incident.v1.Incident
+	3,  // 11: incident.v1.ListIncidentsResponse.incidents:type_name -> incident.v1.Incident
+	3,  // 12: incident.v1.WatchIncidentsResponse.incident:type_name -> incident.v1.Incident
+	4,  // 13: incident.v1.IncidentService.CreateIncident:input_type -> incident.v1.CreateIncidentRequest
+	6,  // 14: incident.v1.IncidentService.UpdateIncident:input_type -> incident.v1.UpdateIncidentRequest
+	8,  // 15: incident.v1.IncidentService.GetIncident:input_type -> incident.v1.GetIncidentRequest
+	10, // 16: incident.v1.IncidentService.ListIncidents:input_type -> incident.v1.ListIncidentsRequest
+	12, // 17: incident.v1.IncidentService.WatchIncidents:input_type -> incident.v1.WatchIncidentsRequest
+	5,  // 18: incident.v1.IncidentService.CreateIncident:output_type -> incident.v1.CreateIncidentResponse
+	7,  // 19: incident.v1.IncidentService.UpdateIncident:output_type -> incident.v1.UpdateIncidentResponse
+	9,  // 20: incident.v1.IncidentService.GetIncident:output_type -> incident.v1.GetIncidentResponse
+	11, // 21: incident.v1.IncidentService.ListIncidents:output_type -> incident.v1.ListIncidentsResponse
+	13, // 22: incident.v1.IncidentService.WatchIncidents:output_type -> incident.v1.WatchIncidentsResponse
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_proto_incident_v1_incident_proto_init() }
+func file_proto_incident_v1_incident_proto_init() {
+	if File_proto_incident_v1_incident_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_incident_v1_incident_proto_rawDesc), len(file_proto_incident_v1_incident_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_incident_v1_incident_proto_goTypes,
+		DependencyIndexes: file_proto_incident_v1_incident_proto_depIdxs,
+		EnumInfos:         file_proto_incident_v1_incident_proto_enumTypes,
+		MessageInfos:      file_proto_incident_v1_incident_proto_msgTypes,
+	}.Build()
+	File_proto_incident_v1_incident_proto = out.File
+	file_proto_incident_v1_incident_proto_goTypes = nil
+	file_proto_incident_v1_incident_proto_depIdxs = nil
+}
@@ -0,0 +1,544 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/maintenance/v1/maintenance.proto
+
+package maintenancepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A planned maintenance window. While it is active, alerts for the covered
+// services are not evaluated and their health samples are flagged.
+//
+// A one-off window covers [starts_at_ms, ends_at_ms). A recurring window sets
+// rrule (RFC 5545 subset: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, COUNT, UNTIL,
+// BYDAY), in which case starts_at_ms is the first occurrence and every
+// occurrence lasts ends_at_ms - starts_at_ms. Times are evaluated in UTC.
+type MaintenanceWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	ServiceIds    []string               `protobuf:"bytes,3,rep,name=service_ids,json=serviceIds,proto3" json:"service_ids,omitempty"`                                                     // cover these services...
+	Selector      map[string]string      `protobuf:"bytes,4,rep,name=selector,proto3" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // ...and/or every service whose labels match all of these
+	StartsAtMs    int64                  `protobuf:"varint,5,opt,name=starts_at_ms,json=startsAtMs,proto3" json:"starts_at_ms,omitempty"`
+	EndsAtMs      int64                  `protobuf:"varint,6,opt,name=ends_at_ms,json=endsAtMs,proto3" json:"ends_at_ms,omitempty"`
+	Rrule         string                 `protobuf:"bytes,7,opt,name=rrule,proto3" json:"rrule,omitempty"` // e.g. "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=8"
+	Cancelled     bool                   `protobuf:"varint,8,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,9,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Active        bool                   `protobuf:"varint,10,opt,name=active,proto3" json:"active,omitempty"`                                // output only: an occurrence is in progress
+	NextStartMs   int64                  `protobuf:"varint,11,opt,name=next_start_ms,json=nextStartMs,proto3" json:"next_start_ms,omitempty"` // output only: start of the current or next occurrence, 0 if none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaintenanceWindow) Reset() {
+	*x = MaintenanceWindow{}
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceWindow) ProtoMessage() {}
+
+func (x *MaintenanceWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceWindow.ProtoReflect.Descriptor instead.
+func (*MaintenanceWindow) Descriptor() ([]byte, []int) {
+	return file_proto_maintenance_v1_maintenance_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MaintenanceWindow) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetServiceIds() []string {
+	if x != nil {
+		return x.ServiceIds
+	}
+	return nil
+}
+
+func (x *MaintenanceWindow) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *MaintenanceWindow) GetStartsAtMs() int64 {
+	if x != nil {
+		return x.StartsAtMs
+	}
+	return 0
+}
+
+func (x *MaintenanceWindow) GetEndsAtMs() int64 {
+	if x != nil {
+		return x.EndsAtMs
+	}
+	return 0
+}
+
+func (x *MaintenanceWindow) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+func (x *MaintenanceWindow) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *MaintenanceWindow) GetNextStartMs() int64 {
+	if x != nil {
+		return x.NextStartMs
+	}
+	return 0
+}
+
+type ScheduleMaintenanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *MaintenanceWindow     `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleMaintenanceRequest) Reset() {
+	*x = ScheduleMaintenanceRequest{}
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMaintenanceRequest) ProtoMessage() {}
+
+func (x *ScheduleMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_maintenance_v1_maintenance_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ScheduleMaintenanceRequest) GetWindow() *MaintenanceWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+type ScheduleMaintenanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *MaintenanceWindow     `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleMaintenanceResponse) Reset() {
+	*x = ScheduleMaintenanceResponse{}
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMaintenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMaintenanceResponse) ProtoMessage() {}
+
+func (x *ScheduleMaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleMaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_maintenance_v1_maintenance_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ScheduleMaintenanceResponse) GetWindow() *MaintenanceWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+type ListMaintenanceRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId        string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"` // optional: only windows covering this service
+	IncludeCancelled bool                   `protobuf:"varint,2,opt,name=include_cancelled,json=includeCancelled,proto3" json:"include_cancelled,omitempty"`
+	IncludePast      bool                   `protobuf:"varint,3,opt,name=include_past,json=includePast,proto3" json:"include_past,omitempty"` // include windows with no remaining occurrence
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListMaintenanceRequest) Reset() {
+	*x = ListMaintenanceRequest{}
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaintenanceRequest) ProtoMessage() {}
+
+func (x *ListMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*ListMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_maintenance_v1_maintenance_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListMaintenanceRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ListMaintenanceRequest) GetIncludeCancelled() bool {
+	if x != nil {
+		return x.IncludeCancelled
+	}
+	return false
+}
+
+func (x *ListMaintenanceRequest) GetIncludePast() bool {
+	if x != nil {
+		return x.IncludePast
+	}
+	return false
+}
+
+type ListMaintenanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Windows       []*MaintenanceWindow   `protobuf:"bytes,1,rep,name=windows,proto3" json:"windows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMaintenanceResponse) Reset() {
+	*x = ListMaintenanceResponse{}
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMaintenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaintenanceResponse) ProtoMessage() {}
+
+func (x *ListMaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*ListMaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_maintenance_v1_maintenance_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListMaintenanceResponse) GetWindows() []*MaintenanceWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+type CancelMaintenanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelMaintenanceRequest) Reset() {
+	*x = CancelMaintenanceRequest{}
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelMaintenanceRequest) ProtoMessage() {}
+
+func (x *CancelMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*CancelMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_maintenance_v1_maintenance_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelMaintenanceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelMaintenanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *MaintenanceWindow     `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelMaintenanceResponse) Reset() {
+	*x = CancelMaintenanceResponse{}
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelMaintenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelMaintenanceResponse) ProtoMessage() {}
+
+func (x *CancelMaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_maintenance_v1_maintenance_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelMaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*CancelMaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_maintenance_v1_maintenance_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CancelMaintenanceResponse) GetWindow() *MaintenanceWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+var File_proto_maintenance_v1_maintenance_proto protoreflect.FileDescriptor
+
+const file_proto_maintenance_v1_maintenance_proto_rawDesc = "" +
+	"\n" +
+	"&proto/maintenance/v1/maintenance.proto\x12\x0emaintenance.v1\"\xb3\x03\n" +
+	"\x11MaintenanceWindow\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1f\n" +
+	"\vservice_ids\x18\x03 \x03(\tR\n" +
+	"serviceIds\x12K\n" +
+	"\bselector\x18\x04 \x03(\v2/.maintenance.v1.MaintenanceWindow.SelectorEntryR\bselector\x12 \n" +
+	"\fstarts_at_ms\x18\x05 \x01(\x03R\n" +
+	"startsAtMs\x12\x1c\n" +
+	"\n" +
+	"ends_at_ms\x18\x06 \x01(\x03R\bendsAtMs\x12\x14\n" +
+	"\x05rrule\x18\a \x01(\tR\x05rrule\x12\x1c\n" +
+	"\tcancelled\x18\b \x01(\bR\tcancelled\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\t \x01(\tR\tcreatedBy\x12\x16\n" +
+	"\x06active\x18\n" +
+	" \x01(\bR\x06active\x12\"\n" +
+	"\rnext_start_ms\x18\v \x01(\x03R\vnextStartMs\x1a;\n" +
+	"\rSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"W\n" +
+	"\x1aScheduleMaintenanceRequest\x129\n" +
+	"\x06window\x18\x01 \x01(\v2!.maintenance.v1.MaintenanceWindowR\x06window\"X\n" +
+	"\x1bScheduleMaintenanceResponse\x129\n" +
+	"\x06window\x18\x01 \x01(\v2!.maintenance.v1.MaintenanceWindowR\x06window\"\x87\x01\n" +
+	"\x16ListMaintenanceRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12+\n" +
+	"\x11include_cancelled\x18\x02 \x01(\bR\x10includeCancelled\x12!\n" +
+	"\finclude_past\x18\x03 \x01(\bR\vincludePast\"V\n" +
+	"\x17ListMaintenanceResponse\x12;\n" +
+	"\awindows\x18\x01 \x03(\v2!.maintenance.v1.MaintenanceWindowR\awindows\"*\n" +
+	"\x18CancelMaintenanceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"V\n" +
+	"\x19CancelMaintenanceResponse\x129\n" +
+	"\x06window\x18\x01 \x01(\v2!.maintenance.v1.MaintenanceWindowR\x06window2\xd2\x02\n" +
+	"\x12MaintenanceService\x12n\n" +
+	"\x13ScheduleMaintenance\x12*.maintenance.v1.ScheduleMaintenanceRequest\x1a+.maintenance.v1.ScheduleMaintenanceResponse\x12b\n" +
+	"\x0fListMaintenance\x12&.maintenance.v1.ListMaintenanceRequest\x1a'.maintenance.v1.ListMaintenanceResponse\x12h\n" +
+	"\x11CancelMaintenance\x12(.maintenance.v1.CancelMaintenanceRequest\x1a).maintenance.v1.CancelMaintenanceResponseBOZMgithub.com/Prof-Rosario-UCLA/team15/gen/go/proto/maintenance/v1;maintenancepbb\x06proto3"
+
+var (
+	file_proto_maintenance_v1_maintenance_proto_rawDescOnce sync.Once
+	file_proto_maintenance_v1_maintenance_proto_rawDescData []byte
+)
+
+func file_proto_maintenance_v1_maintenance_proto_rawDescGZIP() []byte {
+	file_proto_maintenance_v1_maintenance_proto_rawDescOnce.Do(func() {
+		file_proto_maintenance_v1_maintenance_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_maintenance_v1_maintenance_proto_rawDesc), len(file_proto_maintenance_v1_maintenance_proto_rawDesc)))
+	})
+	return file_proto_maintenance_v1_maintenance_proto_rawDescData
+}
+
+var file_proto_maintenance_v1_maintenance_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_maintenance_v1_maintenance_proto_goTypes = []any{
+	(*MaintenanceWindow)(nil),           // 0: maintenance.v1.MaintenanceWindow
+	(*ScheduleMaintenanceRequest)(nil),  // 1: maintenance.v1.ScheduleMaintenanceRequest
+	(*ScheduleMaintenanceResponse)(nil), // 2: maintenance.v1.ScheduleMaintenanceResponse
+	(*ListMaintenanceRequest)(nil),      // 3: maintenance.v1.ListMaintenanceRequest
+	(*ListMaintenanceResponse)(nil),     // 4: maintenance.v1.ListMaintenanceResponse
+	(*CancelMaintenanceRequest)(nil),    // 5: maintenance.v1.CancelMaintenanceRequest
+	(*CancelMaintenanceResponse)(nil),   // 6: maintenance.v1.CancelMaintenanceResponse
+	nil,                                 // 7: maintenance.v1.MaintenanceWindow.SelectorEntry
+}
+var file_proto_maintenance_v1_maintenance_proto_depIdxs = []int32{
+	7, // 0: maintenance.v1.MaintenanceWindow.selector:type_name -> maintenance.v1.MaintenanceWindow.SelectorEntry
+	0, // 1: maintenance.v1.ScheduleMaintenanceRequest.window:type_name -> maintenance.v1.MaintenanceWindow
+	0, // 2: maintenance.v1.ScheduleMaintenanceResponse.window:type_name -> maintenance.v1.MaintenanceWindow
+	0, // 3: maintenance.v1.ListMaintenanceResponse.windows:type_name -> maintenance.v1.MaintenanceWindow
+	0, // 4: maintenance.v1.CancelMaintenanceResponse.window:type_name -> maintenance.v1.MaintenanceWindow
+	1, // 5: maintenance.v1.MaintenanceService.ScheduleMaintenance:input_type -> maintenance.v1.ScheduleMaintenanceRequest
+	3, // 6: maintenance.v1.MaintenanceService.ListMaintenance:input_type -> maintenance.v1.ListMaintenanceRequest
+	5, // 7: maintenance.v1.MaintenanceService.CancelMaintenance:input_type -> maintenance.v1.CancelMaintenanceRequest
+	2, // 8: maintenance.v1.MaintenanceService.ScheduleMaintenance:output_type -> maintenance.v1.ScheduleMaintenanceResponse
+	4, // 9: maintenance.v1.MaintenanceService.ListMaintenance:output_type -> maintenance.v1.ListMaintenanceResponse
+	6, // 10: maintenance.v1.MaintenanceService.CancelMaintenance:output_type -> maintenance.v1.CancelMaintenanceResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_maintenance_v1_maintenance_proto_init() }
+func file_proto_maintenance_v1_maintenance_proto_init() {
+	if File_proto_maintenance_v1_maintenance_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_maintenance_v1_maintenance_proto_rawDesc), len(file_proto_maintenance_v1_maintenance_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_maintenance_v1_maintenance_proto_goTypes,
+		DependencyIndexes: file_proto_maintenance_v1_maintenance_proto_depIdxs,
+		MessageInfos:      file_proto_maintenance_v1_maintenance_proto_msgTypes,
+	}.Build()
+	File_proto_maintenance_v1_maintenance_proto = out.File
+	file_proto_maintenance_v1_maintenance_proto_goTypes = nil
+	file_proto_maintenance_v1_maintenance_proto_depIdxs = nil
+}
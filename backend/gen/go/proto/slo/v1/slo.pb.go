@@ -0,0 +1,834 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/slo/v1/slo.proto
+
+package slopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SLOKind int32
+
+const (
+	SLOKind_SLO_KIND_UNSPECIFIED  SLOKind = 0
+	SLOKind_SLO_KIND_AVAILABILITY SLOKind = 1 // a check is good when its status is UP
+	SLOKind_SLO_KIND_LATENCY      SLOKind = 2 // a check is good when latency_ms < latency_threshold_ms
+)
+
+// Enum value maps for SLOKind.
+var (
+	SLOKind_name = map[int32]string{
+		0: "SLO_KIND_UNSPECIFIED",
+		1: "SLO_KIND_AVAILABILITY",
+		2: "SLO_KIND_LATENCY",
+	}
+	SLOKind_value = map[string]int32{
+		"SLO_KIND_UNSPECIFIED":  0,
+		"SLO_KIND_AVAILABILITY": 1,
+		"SLO_KIND_LATENCY":      2,
+	}
+)
+
+func (x SLOKind) Enum() *SLOKind {
+	p := new(SLOKind)
+	*p = x
+	return p
+}
+
+func (x SLOKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SLOKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_slo_v1_slo_proto_enumTypes[0].Descriptor()
+}
+
+func (SLOKind) Type() protoreflect.EnumType {
+	return &file_proto_slo_v1_slo_proto_enumTypes[0]
+}
+
+func (x SLOKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SLOKind.Descriptor instead.
+func (SLOKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{0}
+}
+
+// A service level objective: `target` of the checks in the last
+// `window_days` must be good. "p95 latency under 300 ms" is a latency SLO
+// with target 0.95 and latency_threshold_ms 300.
+type SLO struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceId          string                 `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Name               string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Kind               SLOKind                `protobuf:"varint,4,opt,name=kind,proto3,enum=slo.v1.SLOKind" json:"kind,omitempty"`
+	Target             float64                `protobuf:"fixed64,5,opt,name=target,proto3" json:"target,omitempty"` // e.g. 0.999
+	LatencyThresholdMs int32                  `protobuf:"varint,6,opt,name=latency_threshold_ms,json=latencyThresholdMs,proto3" json:"latency_threshold_ms,omitempty"`
+	WindowDays         int32                  `protobuf:"varint,7,opt,name=window_days,json=windowDays,proto3" json:"window_days,omitempty"`                         // defaults to 30
+	ExcludeMaintenance bool                   `protobuf:"varint,8,opt,name=exclude_maintenance,json=excludeMaintenance,proto3" json:"exclude_maintenance,omitempty"` // ignore checks taken during maintenance windows
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SLO) Reset() {
+	*x = SLO{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLO) ProtoMessage() {}
+
+func (x *SLO) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLO.ProtoReflect.Descriptor instead.
+func (*SLO) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SLO) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SLO) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *SLO) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SLO) GetKind() SLOKind {
+	if x != nil {
+		return x.Kind
+	}
+	return SLOKind_SLO_KIND_UNSPECIFIED
+}
+
+func (x *SLO) GetTarget() float64 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *SLO) GetLatencyThresholdMs() int32 {
+	if x != nil {
+		return x.LatencyThresholdMs
+	}
+	return 0
+}
+
+func (x *SLO) GetWindowDays() int32 {
+	if x != nil {
+		return x.WindowDays
+	}
+	return 0
+}
+
+func (x *SLO) GetExcludeMaintenance() bool {
+	if x != nil {
+		return x.ExcludeMaintenance
+	}
+	return false
+}
+
+// How fast the error budget is being spent over one window; 1.0 spends
+// exactly the whole budget by the end of the SLO window.
+type BurnRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"` // e.g. "1h"
+	Rate          float64                `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BurnRate) Reset() {
+	*x = BurnRate{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BurnRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BurnRate) ProtoMessage() {}
+
+func (x *BurnRate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BurnRate.ProtoReflect.Descriptor instead.
+func (*BurnRate) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BurnRate) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *BurnRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+type SLOStatus struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	SloId                string                 `protobuf:"bytes,1,opt,name=slo_id,json=sloId,proto3" json:"slo_id,omitempty"`
+	Attainment           float64                `protobuf:"fixed64,2,opt,name=attainment,proto3" json:"attainment,omitempty"`                                                   // good / total over the SLO window
+	ErrorBudgetRemaining float64                `protobuf:"fixed64,3,opt,name=error_budget_remaining,json=errorBudgetRemaining,proto3" json:"error_budget_remaining,omitempty"` // fraction of the budget left; negative once exhausted
+	TotalChecks          int64                  `protobuf:"varint,4,opt,name=total_checks,json=totalChecks,proto3" json:"total_checks,omitempty"`
+	GoodChecks           int64                  `protobuf:"varint,5,opt,name=good_checks,json=goodChecks,proto3" json:"good_checks,omitempty"`
+	BurnRates            []*BurnRate            `protobuf:"bytes,6,rep,name=burn_rates,json=burnRates,proto3" json:"burn_rates,omitempty"`
+	FastBurn             bool                   `protobuf:"varint,7,opt,name=fast_burn,json=fastBurn,proto3" json:"fast_burn,omitempty"` // 1h and 5m burn rates both above 14.4
+	SlowBurn             bool                   `protobuf:"varint,8,opt,name=slow_burn,json=slowBurn,proto3" json:"slow_burn,omitempty"` // 6h and 30m burn rates both above 6
+	ComputedAtMs         int64                  `protobuf:"varint,9,opt,name=computed_at_ms,json=computedAtMs,proto3" json:"computed_at_ms,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SLOStatus) Reset() {
+	*x = SLOStatus{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLOStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLOStatus) ProtoMessage() {}
+
+func (x *SLOStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLOStatus.ProtoReflect.Descriptor instead.
+func (*SLOStatus) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SLOStatus) GetSloId() string {
+	if x != nil {
+		return x.SloId
+	}
+	return ""
+}
+
+func (x *SLOStatus) GetAttainment() float64 {
+	if x != nil {
+		return x.Attainment
+	}
+	return 0
+}
+
+func (x *SLOStatus) GetErrorBudgetRemaining() float64 {
+	if x != nil {
+		return x.ErrorBudgetRemaining
+	}
+	return 0
+}
+
+func (x *SLOStatus) GetTotalChecks() int64 {
+	if x != nil {
+		return x.TotalChecks
+	}
+	return 0
+}
+
+func (x *SLOStatus) GetGoodChecks() int64 {
+	if x != nil {
+		return x.GoodChecks
+	}
+	return 0
+}
+
+func (x *SLOStatus) GetBurnRates() []*BurnRate {
+	if x != nil {
+		return x.BurnRates
+	}
+	return nil
+}
+
+func (x *SLOStatus) GetFastBurn() bool {
+	if x != nil {
+		return x.FastBurn
+	}
+	return false
+}
+
+func (x *SLOStatus) GetSlowBurn() bool {
+	if x != nil {
+		return x.SlowBurn
+	}
+	return false
+}
+
+func (x *SLOStatus) GetComputedAtMs() int64 {
+	if x != nil {
+		return x.ComputedAtMs
+	}
+	return 0
+}
+
+type CreateSLORequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slo           *SLO                   `protobuf:"bytes,1,opt,name=slo,proto3" json:"slo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSLORequest) Reset() {
+	*x = CreateSLORequest{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSLORequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSLORequest) ProtoMessage() {}
+
+func (x *CreateSLORequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSLORequest.ProtoReflect.Descriptor instead.
+func (*CreateSLORequest) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateSLORequest) GetSlo() *SLO {
+	if x != nil {
+		return x.Slo
+	}
+	return nil
+}
+
+type CreateSLOResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slo           *SLO                   `protobuf:"bytes,1,opt,name=slo,proto3" json:"slo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSLOResponse) Reset() {
+	*x = CreateSLOResponse{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSLOResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSLOResponse) ProtoMessage() {}
+
+func (x *CreateSLOResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSLOResponse.ProtoReflect.Descriptor instead.
+func (*CreateSLOResponse) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateSLOResponse) GetSlo() *SLO {
+	if x != nil {
+		return x.Slo
+	}
+	return nil
+}
+
+type ListSLOsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"` // optional filter
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSLOsRequest) Reset() {
+	*x = ListSLOsRequest{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSLOsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSLOsRequest) ProtoMessage() {}
+
+func (x *ListSLOsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSLOsRequest.ProtoReflect.Descriptor instead.
+func (*ListSLOsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSLOsRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+type ListSLOsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slos          []*SLO                 `protobuf:"bytes,1,rep,name=slos,proto3" json:"slos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSLOsResponse) Reset() {
+	*x = ListSLOsResponse{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSLOsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSLOsResponse) ProtoMessage() {}
+
+func (x *ListSLOsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSLOsResponse.ProtoReflect.Descriptor instead.
+func (*ListSLOsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListSLOsResponse) GetSlos() []*SLO {
+	if x != nil {
+		return x.Slos
+	}
+	return nil
+}
+
+type GetSLOStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SloId         string                 `protobuf:"bytes,1,opt,name=slo_id,json=sloId,proto3" json:"slo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSLOStatusRequest) Reset() {
+	*x = GetSLOStatusRequest{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSLOStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSLOStatusRequest) ProtoMessage() {}
+
+func (x *GetSLOStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSLOStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetSLOStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSLOStatusRequest) GetSloId() string {
+	if x != nil {
+		return x.SloId
+	}
+	return ""
+}
+
+type GetSLOStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *SLOStatus             `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSLOStatusResponse) Reset() {
+	*x = GetSLOStatusResponse{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSLOStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSLOStatusResponse) ProtoMessage() {}
+
+func (x *GetSLOStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSLOStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetSLOStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetSLOStatusResponse) GetStatus() *SLOStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type WatchSLOStatusRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SloId           string                 `protobuf:"bytes,1,opt,name=slo_id,json=sloId,proto3" json:"slo_id,omitempty"`
+	IntervalSeconds int32                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // defaults to 10
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchSLOStatusRequest) Reset() {
+	*x = WatchSLOStatusRequest{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSLOStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSLOStatusRequest) ProtoMessage() {}
+
+func (x *WatchSLOStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSLOStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchSLOStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchSLOStatusRequest) GetSloId() string {
+	if x != nil {
+		return x.SloId
+	}
+	return ""
+}
+
+func (x *WatchSLOStatusRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type WatchSLOStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *SLOStatus             `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSLOStatusResponse) Reset() {
+	*x = WatchSLOStatusResponse{}
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSLOStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSLOStatusResponse) ProtoMessage() {}
+
+func (x *WatchSLOStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slo_v1_slo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSLOStatusResponse.ProtoReflect.Descriptor instead.
+func (*WatchSLOStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_slo_v1_slo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchSLOStatusResponse) GetStatus() *SLOStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+var File_proto_slo_v1_slo_proto protoreflect.FileDescriptor
+
+const file_proto_slo_v1_slo_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/slo/v1/slo.proto\x12\x06slo.v1\"\x89\x02\n" +
+	"\x03SLO\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x02 \x01(\tR\tserviceId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12#\n" +
+	"\x04kind\x18\x04 \x01(\x0e2\x0f.slo.v1.SLOKindR\x04kind\x12\x16\n" +
+	"\x06target\x18\x05 \x01(\x01R\x06target\x120\n" +
+	"\x14latency_threshold_ms\x18\x06 \x01(\x05R\x12latencyThresholdMs\x12\x1f\n" +
+	"\vwindow_days\x18\a \x01(\x05R\n" +
+	"windowDays\x12/\n" +
+	"\x13exclude_maintenance\x18\b \x01(\bR\x12excludeMaintenance\"6\n" +
+	"\bBurnRate\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\x01R\x04rate\"\xcd\x02\n" +
+	"\tSLOStatus\x12\x15\n" +
+	"\x06slo_id\x18\x01 \x01(\tR\x05sloId\x12\x1e\n" +
+	"\n" +
+	"attainment\x18\x02 \x01(\x01R\n" +
+	"attainment\x124\n" +
+	"\x16error_budget_remaining\x18\x03 \x01(\x01R\x14errorBudgetRemaining\x12!\n" +
+	"\ftotal_checks\x18\x04 \x01(\x03R\vtotalChecks\x12\x1f\n" +
+	"\vgood_checks\x18\x05 \x01(\x03R\n" +
+	"goodChecks\x12/\n" +
+	"\n" +
+	"burn_rates\x18\x06 \x03(\v2\x10.slo.v1.BurnRateR\tburnRates\x12\x1b\n" +
+	"\tfast_burn\x18\a \x01(\bR\bfastBurn\x12\x1b\n" +
+	"\tslow_burn\x18\b \x01(\bR\bslowBurn\x12$\n" +
+	"\x0ecomputed_at_ms\x18\t \x01(\x03R\fcomputedAtMs\"1\n" +
+	"\x10CreateSLORequest\x12\x1d\n" +
+	"\x03slo\x18\x01 \x01(\v2\v.slo.v1.SLOR\x03slo\"2\n" +
+	"\x11CreateSLOResponse\x12\x1d\n" +
+	"\x03slo\x18\x01 \x01(\v2\v.slo.v1.SLOR\x03slo\"0\n" +
+	"\x0fListSLOsRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\"3\n" +
+	"\x10ListSLOsResponse\x12\x1f\n" +
+	"\x04slos\x18\x01 \x03(\v2\v.slo.v1.SLOR\x04slos\",\n" +
+	"\x13GetSLOStatusRequest\x12\x15\n" +
+	"\x06slo_id\x18\x01 \x01(\tR\x05sloId\"A\n" +
+	"\x14GetSLOStatusResponse\x12)\n" +
+	"\x06status\x18\x01 \x01(\v2\x11.slo.v1.SLOStatusR\x06status\"Y\n" +
+	"\x15WatchSLOStatusRequest\x12\x15\n" +
+	"\x06slo_id\x18\x01 \x01(\tR\x05sloId\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\"C\n" +
+	"\x16WatchSLOStatusResponse\x12)\n" +
+	"\x06status\x18\x01 \x01(\v2\x11.slo.v1.SLOStatusR\x06status*T\n" +
+	"\aSLOKind\x12\x18\n" +
+	"\x14SLO_KIND_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SLO_KIND_AVAILABILITY\x10\x01\x12\x14\n" +
+	"\x10SLO_KIND_LATENCY\x10\x022\xab\x02\n" +
+	"\n" +
+	"SLOService\x12@\n" +
+	"\tCreateSLO\x12\x18.slo.v1.CreateSLORequest\x1a\x19.slo.v1.CreateSLOResponse\x12=\n" +
+	"\bListSLOs\x12\x17.slo.v1.ListSLOsRequest\x1a\x18.slo.v1.ListSLOsResponse\x12I\n" +
+	"\fGetSLOStatus\x12\x1b.slo.v1.GetSLOStatusRequest\x1a\x1c.slo.v1.GetSLOStatusResponse\x12Q\n" +
+	"\x0eWatchSLOStatus\x12\x1d.slo.v1.WatchSLOStatusRequest\x1a\x1e.slo.v1.WatchSLOStatusResponse0\x01B?Z=github.com/Prof-Rosario-UCLA/team15/gen/go/proto/slo/v1;slopbb\x06proto3"
+
+var (
+	file_proto_slo_v1_slo_proto_rawDescOnce sync.Once
+	file_proto_slo_v1_slo_proto_rawDescData []byte
+)
+
+func file_proto_slo_v1_slo_proto_rawDescGZIP() []byte {
+	file_proto_slo_v1_slo_proto_rawDescOnce.Do(func() {
+		file_proto_slo_v1_slo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_slo_v1_slo_proto_rawDesc), len(file_proto_slo_v1_slo_proto_rawDesc)))
+	})
+	return file_proto_slo_v1_slo_proto_rawDescData
+}
+
+var file_proto_slo_v1_slo_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_slo_v1_slo_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_slo_v1_slo_proto_goTypes = []any{
+	(SLOKind)(0),                   // 0: slo.v1.SLOKind
+	(*SLO)(nil),                    // 1: slo.v1.SLO
+	(*BurnRate)(nil),               // 2: slo.v1.BurnRate
+	(*SLOStatus)(nil),              // 3: slo.v1.SLOStatus
+	(*CreateSLORequest)(nil),       // 4: slo.v1.CreateSLORequest
+	(*CreateSLOResponse)(nil),      // 5: slo.v1.CreateSLOResponse
+	(*ListSLOsRequest)(nil),        // 6: slo.v1.ListSLOsRequest
+	(*ListSLOsResponse)(nil),       // 7: slo.v1.ListSLOsResponse
+	(*GetSLOStatusRequest)(nil),    // 8: slo.v1.GetSLOStatusRequest
+	(*GetSLOStatusResponse)(nil),   // 9: slo.v1.GetSLOStatusResponse
+	(*WatchSLOStatusRequest)(nil),  // 10: slo.v1.WatchSLOStatusRequest
+	(*WatchSLOStatusResponse)(nil), // 11: slo.v1.WatchSLOStatusResponse
+}
+var file_proto_slo_v1_slo_proto_depIdxs = []int32{
+	0,  // 0: slo.v1.SLO.kind:type_name -> slo.v1.SLOKind
+	2,  // 1: slo.v1.SLOStatus.burn_rates:type_name -> slo.v1.BurnRate
+	1,  // 2: slo.v1.CreateSLORequest.slo:type_name -> slo.v1.SLO
+	1,  // 3: slo.v1.CreateSLOResponse.slo:type_name -> slo.v1.SLO
+	1,  // 4: slo.v1.ListSLOsResponse.slos:type_name -> slo.v1.SLO
+	3,  // 5: slo.v1.GetSLOStatusResponse.status:type_name -> slo.v1.SLOStatus
+	3,  // 6: slo.v1.WatchSLOStatusResponse.status:type_name -> slo.v1.SLOStatus
+	4,  // 7: slo.v1.SLOService.CreateSLO:input_type -> slo.v1.CreateSLORequest
+	6,  // 8: slo.v1.SLOService.ListSLOs:input_type -> slo.v1.ListSLOsRequest
+	8,  // 9: slo.v1.SLOService.GetSLOStatus:input_type -> slo.v1.GetSLOStatusRequest
+	10, // 10: slo.v1.SLOService.WatchSLOStatus:input_type -> slo.v1.WatchSLOStatusRequest
+	5,  // 11: slo.v1.SLOService.CreateSLO:output_type -> slo.v1.CreateSLOResponse
+	7,  // 12: slo.v1.SLOService.ListSLOs:output_type -> slo.v1.ListSLOsResponse
+	9,  // 13: slo.v1.SLOService.GetSLOStatus:output_type -> slo.v1.GetSLOStatusResponse
+	11, // 14: slo.v1.SLOService.WatchSLOStatus:output_type -> slo.v1.WatchSLOStatusResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_proto_slo_v1_slo_proto_init() }
+func file_proto_slo_v1_slo_proto_init() {
+	if File_proto_slo_v1_slo_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_slo_v1_slo_proto_rawDesc), len(file_proto_slo_v1_slo_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_slo_v1_slo_proto_goTypes,
+		DependencyIndexes: file_proto_slo_v1_slo_proto_depIdxs,
+		EnumInfos:         file_proto_slo_v1_slo_proto_enumTypes,
+		MessageInfos:      file_proto_slo_v1_slo_proto_msgTypes,
+	}.Build()
+	File_proto_slo_v1_slo_proto = out.File
+	file_proto_slo_v1_slo_proto_goTypes = nil
+	file_proto_slo_v1_slo_proto_depIdxs = nil
+}
@@ -0,0 +1,239 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/slo/v1/slo.proto
+
+package slopb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SLOService_CreateSLO_FullMethodName      = "/slo.v1.SLOService/CreateSLO"
+	SLOService_ListSLOs_FullMethodName       = "/slo.v1.SLOService/ListSLOs"
+	SLOService_GetSLOStatus_FullMethodName   = "/slo.v1.SLOService/GetSLOStatus"
+	SLOService_WatchSLOStatus_FullMethodName = "/slo.v1.SLOService/WatchSLOStatus"
+)
+
+// SLOServiceClient is the client API for SLOService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SLOServiceClient interface {
+	CreateSLO(ctx context.Context, in *CreateSLORequest, opts ...grpc.CallOption) (*CreateSLOResponse, error)
+	ListSLOs(ctx context.Context, in *ListSLOsRequest, opts ...grpc.CallOption) (*ListSLOsResponse, error)
+	GetSLOStatus(ctx context.Context, in *GetSLOStatusRequest, opts ...grpc.CallOption) (*GetSLOStatusResponse, error)
+	WatchSLOStatus(ctx context.Context, in *WatchSLOStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchSLOStatusResponse], error)
+}
+
+type sLOServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSLOServiceClient(cc grpc.ClientConnInterface) SLOServiceClient {
+	return &sLOServiceClient{cc}
+}
+
+func (c *sLOServiceClient) CreateSLO(ctx context.Context, in *CreateSLORequest, opts ...grpc.CallOption) (*CreateSLOResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSLOResponse)
+	err := c.cc.Invoke(ctx, SLOService_CreateSLO_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sLOServiceClient) ListSLOs(ctx context.Context, in *ListSLOsRequest, opts ...grpc.CallOption) (*ListSLOsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSLOsResponse)
+	err := c.cc.Invoke(ctx, SLOService_ListSLOs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sLOServiceClient) GetSLOStatus(ctx context.Context, in *GetSLOStatusRequest, opts ...grpc.CallOption) (*GetSLOStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSLOStatusResponse)
+	err := c.cc.Invoke(ctx, SLOService_GetSLOStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sLOServiceClient) WatchSLOStatus(ctx context.Context, in *WatchSLOStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchSLOStatusResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SLOService_ServiceDesc.Streams[0], SLOService_WatchSLOStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchSLOStatusRequest, WatchSLOStatusResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SLOService_WatchSLOStatusClient = grpc.ServerStreamingClient[WatchSLOStatusResponse]
+
+// SLOServiceServer is the server API for SLOService service.
+// All implementations must embed UnimplementedSLOServiceServer
+// for forward compatibility.
+type SLOServiceServer interface {
+	CreateSLO(context.Context, *CreateSLORequest) (*CreateSLOResponse, error)
+	ListSLOs(context.Context, *ListSLOsRequest) (*ListSLOsResponse, error)
+	GetSLOStatus(context.Context, *GetSLOStatusRequest) (*GetSLOStatusResponse, error)
+	WatchSLOStatus(*WatchSLOStatusRequest, grpc.ServerStreamingServer[WatchSLOStatusResponse]) error
+	mustEmbedUnimplementedSLOServiceServer()
+}
+
+// UnimplementedSLOServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSLOServiceServer struct{}
+
+func (UnimplementedSLOServiceServer) CreateSLO(context.Context, *CreateSLORequest) (*CreateSLOResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSLO not implemented")
+}
+func (UnimplementedSLOServiceServer) ListSLOs(context.Context, *ListSLOsRequest) (*ListSLOsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSLOs not implemented")
+}
+func (UnimplementedSLOServiceServer) GetSLOStatus(context.Context, *GetSLOStatusRequest) (*GetSLOStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSLOStatus not implemented")
+}
+func (UnimplementedSLOServiceServer) WatchSLOStatus(*WatchSLOStatusRequest, grpc.ServerStreamingServer[WatchSLOStatusResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSLOStatus not implemented")
+}
+func (UnimplementedSLOServiceServer) mustEmbedUnimplementedSLOServiceServer() {}
+func (UnimplementedSLOServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeSLOServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SLOServiceServer will
+// result in compilation errors.
+type UnsafeSLOServiceServer interface {
+	mustEmbedUnimplementedSLOServiceServer()
+}
+
+func RegisterSLOServiceServer(s grpc.ServiceRegistrar, srv SLOServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSLOServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SLOService_ServiceDesc, srv)
+}
+
+func _SLOService_CreateSLO_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSLORequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SLOServiceServer).CreateSLO(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SLOService_CreateSLO_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SLOServiceServer).CreateSLO(ctx, req.(*CreateSLORequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SLOService_ListSLOs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSLOsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SLOServiceServer).ListSLOs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SLOService_ListSLOs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SLOServiceServer).ListSLOs(ctx, req.(*ListSLOsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SLOService_GetSLOStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSLOStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SLOServiceServer).GetSLOStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SLOService_GetSLOStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SLOServiceServer).GetSLOStatus(ctx, req.(*GetSLOStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SLOService_WatchSLOStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSLOStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SLOServiceServer).WatchSLOStatus(m, &grpc.GenericServerStream[WatchSLOStatusRequest, WatchSLOStatusResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SLOService_WatchSLOStatusServer = grpc.ServerStreamingServer[WatchSLOStatusResponse]
+
+// SLOService_ServiceDesc is the grpc.ServiceDesc for SLOService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SLOService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "slo.v1.SLOService",
+	HandlerType: (*SLOServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateSLO",
+			Handler:    _SLOService_CreateSLO_Handler,
+		},
+		{
+			MethodName: "ListSLOs",
+			Handler:    _SLOService_ListSLOs_Handler,
+		},
+		{
+			MethodName: "GetSLOStatus",
+			Handler:    _SLOService_GetSLOStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSLOStatus",
+			Handler:       _SLOService_WatchSLOStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/slo/v1/slo.proto",
+}
@@ -0,0 +1,8 @@
+// Package openapi holds the OpenAPI document of the REST gateway, which buf
+// generates from the google.api.http options of the RPCs.
+package openapi
+
+import _ "embed"
+
+//go:embed api.swagger.json
+var Document []byte
@@ -0,0 +1,605 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	accessTokenTTL  = time.Hour
+	mfaChallengeTTL = 5 * time.Minute
+
+	// Challenges are signed like access tokens but carry an audience, which
+	// parseAccessToken rejects.
+	mfaChallengeAudience = "team15-mfa"
+	mfaEnrollAudience    = "team15-mfa-enroll"
+
+	settingMFARequiredForAdmins = "mfa.require_for_admins"
+)
+
+// ErrInvalidCredentials is returned for an unknown username or a wrong
+// password alike.
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// dummyPasswordHash is compared against for unknown usernames, so response
+// times do not reveal which usernames exist.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Accounts checks local users' passwords and second factors and issues
+// the access tokens. A login is two steps for users with MFA: the password
+// gets a short-lived challenge token, which a TOTP or recovery code turns
+// into the access token. While MFA is required for admins, an admin
+// without it gets an enrollment challenge instead, good only for enrolling.
+//
+// Users reset forgotten passwords and verify their email through links
+// mailed to them (see password_reset.go); appURL is where those links point.
+// Without a mailer those endpoints are not mounted.
+type Accounts struct {
+	db       *gorm.DB
+	key      []byte
+	guard    *LoginGuard
+	sessions *Sessions
+	mailer   Mailer
+	policy   *PasswordPolicy
+	appURL   string
+	limiter  *RateLimiter
+}
+
+func NewAccounts(db *gorm.DB, secretKey []byte, guard *LoginGuard, sessions *Sessions, mailer Mailer, policy *PasswordPolicy, appURL string) *Accounts {
+	return &Accounts{
+		db:       db,
+		key:      secretKey,
+		guard:    guard,
+		sessions: sessions,
+		mailer:   mailer,
+		policy:   policy,
+		appURL:   strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+// SeedAdmin creates the admin account when there are no users yet.
+func (a *Accounts) SeedAdmin(ctx context.Context, password string) error {
+	var n int64
+	if err := a.db.WithContext(ctx).Model(&UserModel{}).Count(&n).Error; err != nil || n > 0 {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return a.db.WithContext(ctx).Create(&UserModel{
+		ID:           newID(),
+		Username:     "admin",
+		PasswordHash: string(hash),
+		Roles:        []string{roleAdmin},
+	}).Error
+}
+
+func (a *Accounts) user(ctx context.Context, username string) (*UserModel, error) {
+	var u UserModel
+	if err := a.db.WithContext(ctx).Where("username = ?", strings.ToLower(username)).Take(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Authenticate checks a password against the local user store.
+func (a *Accounts) Authenticate(ctx context.Context, username, password string) (*UserModel, error) {
+	u, err := a.user(ctx, username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return u, nil
+}
+
+// MFARequiredForAdmins reports the admin switch; it is off until set.
+func (a *Accounts) MFARequiredForAdmins(ctx context.Context) bool {
+	var s SettingModel
+	if err := a.db.WithContext(ctx).Where("key = ?", settingMFARequiredForAdmins).Take(&s).Error; err != nil {
+		return false
+	}
+	required, _ := strconv.ParseBool(s.Value)
+	return required
+}
+
+func (a *Accounts) mfaRequired(ctx context.Context, u *UserModel) bool {
+	return slices.Contains(u.Roles, roleAdmin) && a.MFARequiredForAdmins(ctx)
+}
+
+// BeginLogin continues a login whose password was right: it completes it,
+// or answers with an MFA challenge when a second factor is needed.
+func (a *Accounts) BeginLogin(w http.ResponseWriter, r *http.Request, u *UserModel) {
+	audience, flag := a.loginChallenge(r.Context(), u)
+	if flag == "" {
+		a.completeLogin(w, r, u, nil)
+		return
+	}
+	tokenString, err := a.challengeToken(r.Context(), u, audience, flag)
+	if err != nil {
+		http.Error(w, "Token generation failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		flag:        true,
+		"mfa_token": tokenString,
+		"message":   "Password accepted, second factor required",
+	})
+}
+
+// BeginRedirectLogin is BeginLogin for logins that end by redirecting the
+// browser to target, such as OIDC callbacks. A completed login sets the
+// access token cookie; a needed challenge is handed to the frontend in
+// target's fragment, which is not sent to servers, as the same fields
+// BeginLogin answers with.
+func (a *Accounts) BeginRedirectLogin(w http.ResponseWriter, r *http.Request, u *UserModel, target string) error {
+	audience, flag := a.loginChallenge(r.Context(), u)
+	if flag == "" {
+		if _, _, err := a.setAccessToken(w, r, u); err != nil {
+			return err
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+		return nil
+	}
+	tokenString, err := a.challengeToken(r.Context(), u, audience, flag)
+	if err != nil {
+		return err
+	}
+	target, _, _ = strings.Cut(target, "#")
+	http.Redirect(w, r, target+"#"+url.Values{flag: {"true"}, "mfa_token": {tokenString}}.Encode(), http.StatusFound)
+	return nil
+}
+
+// loginChallenge is the second factor step a login whose first factor was
+// right still needs, as the audience of its MFA token and the flag that
+// names it to the client, or "" when the login can complete.
+func (a *Accounts) loginChallenge(ctx context.Context, u *UserModel) (audience, flag string) {
+	switch {
+	case u.MFAEnabled:
+		return mfaChallengeAudience, "mfa_required"
+	case a.mfaRequired(ctx, u):
+		return mfaEnrollAudience, "mfa_enrollment_required"
+	}
+	return "", ""
+}
+
+func (a *Accounts) challengeToken(ctx context.Context, u *UserModel, audience, flag string) (string, error) {
+	tokenString, _, err := issueToken(a.key, "", u.Username, nil, audience, mfaChallengeTTL)
+	if err != nil {
+		return "", err
+	}
+	Logger(ctx).Info("login needs a second factor", "username", u.Username, "step", flag)
+	return tokenString, nil
+}
+
+// completeLogin issues the access token, as a cookie for the browser and in
+// the body for clients that send it themselves, along with the CSRF token
+// (for frontends on another origin, which cannot read its cookie) and any
+// extra fields.
+func (a *Accounts) completeLogin(w http.ResponseWriter, r *http.Request, u *UserModel, extra map[string]interface{}) {
+	tokenString, csrfToken, err := a.setAccessToken(w, r, u)
+	if err != nil {
+		http.Error(w, "Token generation failed", http.StatusInternalServerError)
+		return
+	}
+
+	body := map[string]interface{}{"token": tokenString, "csrf_token": csrfToken, "message": "Login successful"}
+	for k, v := range extra {
+		body[k] = v
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// setAccessToken issues the access token of a completed login and sets it
+// as the access_token cookie, with a new CSRF token beside it.
+func (a *Accounts) setAccessToken(w http.ResponseWriter, r *http.Request, u *UserModel) (string, string, error) {
+	expires := time.Now().Add(accessTokenTTL)
+	id, err := a.sessions.Create(r.Context(), u.Username, r.UserAgent(), HTTPClientIP(r), expires)
+	if err != nil {
+		return "", "", err
+	}
+	tokenString, expires, err := issueToken(a.key, id, u.Username, u.Roles, "", accessTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+	a.guard.Succeeded(r.Context(), u.Username)
+	SetPrincipal(r.Context(), u.Username)
+	Logger(r.Context()).Info("login succeeded")
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "access_token",
+		Value:    tokenString,
+		Expires:  expires,
+		HttpOnly: true, // Important for security
+		Path:     "/",  // Cookie is valid for all paths
+		SameSite: CookieSameSite,
+		Secure:   CookieSecure,
+	})
+	return tokenString, setCSRFCookie(w, expires), nil
+}
+
+// ErrUsernameTaken is returned when an external identity's username already
+// belongs to another account.
+var ErrUsernameTaken = errors.New("username belongs to another account")
+
+// ProvisionExternal finds or creates the user for an identity of an
+// external provider ("oidc", "ldap"), keyed by the provider's stable ID.
+// Roles and email follow the provider on every login. Users are never
+// linked to an existing account by username alone.
+func (a *Accounts) ProvisionExternal(ctx context.Context, provider, externalID, username, email string, roles []string) (*UserModel, error) {
+	var u UserModel
+	err := a.db.WithContext(ctx).Where("provider = ? AND external_id = ?", provider, externalID).Take(&u).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		u = UserModel{
+			ID:         newID(),
+			Username:   strings.ToLower(username),
+			Email:      email,
+			Roles:      roles,
+			Provider:   provider,
+			ExternalID: externalID,
+		}
+		// Names like service account subjects are reserved.
+		if strings.HasPrefix(u.Username, serviceAccountSubjectPrefix) {
+			return nil, ErrUsernameTaken
+		}
+		var taken int64
+		if err := a.db.WithContext(ctx).Model(&UserModel{}).Where("username = ?", u.Username).Count(&taken).Error; err != nil {
+			return nil, err
+		}
+		if taken > 0 {
+			return nil, ErrUsernameTaken
+		}
+		if err := a.db.WithContext(ctx).Create(&u).Error; err != nil {
+			return nil, err
+		}
+		Logger(ctx).Info("provisioned user", "username", u.Username, "provider", provider, "roles", roles)
+		return &u, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.Email, u.Roles = email, roles
+	if err := a.db.WithContext(ctx).Model(&u).Select("email", "roles").Updates(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// mfaRequest is the body of the MFA endpoints. MFAToken is the challenge
+// from /login; without it the caller must already be logged in.
+type mfaRequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// mfaCaller resolves who an MFA request is for, from a challenge token
+// with the given audience or else the access token. It writes the error
+// response itself and returns nil when there is no valid caller.
+func (a *Accounts) mfaCaller(w http.ResponseWriter, r *http.Request, req mfaRequest, audience string) *UserModel {
+	var claims *AccessClaims
+	var err error
+	if req.MFAToken != "" {
+		claims, err = parseToken(a.key, req.MFAToken)
+		if err == nil && !slices.Contains(claims.Audience, audience) {
+			err = errors.New("wrong token audience")
+		}
+	} else if tokenString, fromCookie := httpAccessToken(r); tokenString != "" {
+		if fromCookie && !httpCSRFValid(r) {
+			http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+			return nil
+		}
+		claims, err = verifyAccessToken(r.Context(), a.key, a.sessions, tokenString)
+	} else {
+		err = errors.New("no token")
+	}
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+
+	SetAuditResource(r.Context(), "user", claims.Subject)
+	if wait := a.guard.Locked(r.Context(), HTTPClientIP(r), claims.Subject); wait > 0 {
+		w.Header().Set("Retry-After", retryAfter(wait))
+		http.Error(w, "Too many failed login attempts", http.StatusTooManyRequests)
+		return nil
+	}
+	u, err := a.user(r.Context(), claims.Subject)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+	if req.MFAToken == "" {
+		SetPrincipal(r.Context(), u.Username)
+	}
+	return u
+}
+
+// errSecondFactor is returned when neither a code nor a recovery code matched.
+var errSecondFactor = errors.New("invalid MFA code")
+
+// useSecondFactor checks a TOTP code or recovery code against the enrolled
+// secret, and consumes it so it cannot be used again.
+func (a *Accounts) useSecondFactor(ctx context.Context, userID string, req mfaRequest) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var u UserModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).Take(&u).Error; err != nil {
+			return err
+		}
+		if !u.MFAEnabled {
+			return errSecondFactor
+		}
+		if req.RecoveryCode != "" {
+			h := hashRecoveryCode(req.RecoveryCode)
+			i := slices.Index(u.RecoveryCodes, h)
+			if i < 0 {
+				return errSecondFactor
+			}
+			u.RecoveryCodes = slices.Delete(u.RecoveryCodes, i, i+1)
+			return tx.Model(&u).Select("recovery_codes").Updates(&u).Error
+		}
+		step, ok := verifyTOTP(u.TOTPSecret, req.Code, time.Now(), u.TOTPLastStep)
+		if !ok {
+			return errSecondFactor
+		}
+		return tx.Model(&u).Update("totp_last_step", step).Error
+	})
+}
+
+// secondFactorFailed answers a wrong code; it counts towards the lockout
+// like a wrong password.
+func (a *Accounts) secondFactorFailed(w http.ResponseWriter, r *http.Request, u *UserModel, err error) {
+	if !errors.Is(err, errSecondFactor) {
+		Logger(r.Context()).Error("checking second factor failed", "error", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	Logger(r.Context()).Warn("MFA code rejected", "username", u.Username)
+	a.guard.Failed(r.Context(), HTTPClientIP(r), u.Username)
+	http.Error(w, "Invalid MFA code", http.StatusUnauthorized)
+}
+
+func decodeMFARequest(w http.ResponseWriter, r *http.Request) (mfaRequest, bool) {
+	var req mfaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+// LoginMFA is the second login step: the challenge from /login and a TOTP
+// code or recovery code.
+func (a *Accounts) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeMFARequest(w, r)
+	if !ok {
+		return
+	}
+	if req.MFAToken == "" {
+		http.Error(w, "mfa_token is required", http.StatusBadRequest)
+		return
+	}
+	u := a.mfaCaller(w, r, req, mfaChallengeAudience)
+	if u == nil {
+		return
+	}
+	if err := a.useSecondFactor(r.Context(), u.ID, req); err != nil {
+		a.secondFactorFailed(w, r, u, err)
+		return
+	}
+	a.completeLogin(w, r, u, nil)
+}
+
+// Enroll starts TOTP enrollment with a new secret, returned with its
+// provisioning URI to show as a QR code. MFA is not on until Activate
+// verifies a code from it.
+func (a *Accounts) Enroll(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeMFARequest(w, r)
+	if !ok {
+		return
+	}
+	u := a.mfaCaller(w, r, req, mfaEnrollAudience)
+	if u == nil {
+		return
+	}
+	if u.MFAEnabled {
+		http.Error(w, "MFA is already enabled", http.StatusConflict)
+		return
+	}
+	secret := newTOTPSecret()
+	if err := a.db.WithContext(r.Context()).Model(u).Update("totp_pending", secret).Error; err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"secret":           secret,
+		"provisioning_uri": totpURI(u.Username, secret),
+	})
+}
+
+// Activate turns MFA on once a code from the pending secret verifies, and
+// returns the recovery codes, which are shown only this once. Enrolling
+// from a login challenge also completes that login.
+func (a *Accounts) Activate(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeMFARequest(w, r)
+	if !ok {
+		return
+	}
+	u := a.mfaCaller(w, r, req, mfaEnrollAudience)
+	if u == nil {
+		return
+	}
+	if u.MFAEnabled || u.TOTPPending == "" {
+		http.Error(w, "No MFA enrollment in progress", http.StatusConflict)
+		return
+	}
+	step, ok := verifyTOTP(u.TOTPPending, req.Code, time.Now(), 0)
+	if !ok {
+		a.secondFactorFailed(w, r, u, errSecondFactor)
+		return
+	}
+	codes, hashes := newRecoveryCodes()
+	u.MFAEnabled, u.TOTPSecret, u.TOTPPending, u.TOTPLastStep, u.RecoveryCodes = true, u.TOTPPending, "", step, hashes
+	err := a.db.WithContext(r.Context()).Model(u).
+		Select("mfa_enabled", "totp_secret", "totp_pending", "totp_last_step", "recovery_codes").Updates(u).Error
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	AuditChange(r.Context(), "mfa_enabled", false, true)
+	Logger(r.Context()).Info("MFA enabled", "username", u.Username)
+
+	if req.MFAToken != "" {
+		a.completeLogin(w, r, u, map[string]interface{}{"recovery_codes": codes})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"recovery_codes": codes})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, given a TOTP code.
+func (a *Accounts) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeMFARequest(w, r)
+	if !ok {
+		return
+	}
+	req.MFAToken, req.RecoveryCode = "", ""
+	u := a.mfaCaller(w, r, req, "")
+	if u == nil {
+		return
+	}
+	if err := a.useSecondFactor(r.Context(), u.ID, req); err != nil {
+		a.secondFactorFailed(w, r, u, err)
+		return
+	}
+	codes, hashes := newRecoveryCodes()
+	u.RecoveryCodes = hashes
+	if err := a.db.WithContext(r.Context()).Model(u).Select("recovery_codes").Updates(u).Error; err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	AuditChange(r.Context(), "recovery_codes", "", "regenerated")
+	writeJSON(w, http.StatusOK, map[string]interface{}{"recovery_codes": codes})
+}
+
+// Disable turns MFA off, given a TOTP code or recovery code, unless the
+// user's role requires it.
+func (a *Accounts) Disable(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeMFARequest(w, r)
+	if !ok {
+		return
+	}
+	req.MFAToken = ""
+	u := a.mfaCaller(w, r, req, "")
+	if u == nil {
+		return
+	}
+	if a.mfaRequired(r.Context(), u) {
+		http.Error(w, "MFA is required for your role", http.StatusForbidden)
+		return
+	}
+	if err := a.useSecondFactor(r.Context(), u.ID, req); err != nil {
+		a.secondFactorFailed(w, r, u, err)
+		return
+	}
+	u.MFAEnabled, u.TOTPSecret, u.TOTPPending, u.RecoveryCodes = false, "", "", nil
+	err := a.db.WithContext(r.Context()).Model(u).
+		Select("mfa_enabled", "totp_secret", "totp_pending", "recovery_codes").Updates(u).Error
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	AuditChange(r.Context(), "mfa_enabled", true, false)
+	Logger(r.Context()).Info("MFA disabled", "username", u.Username)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MFAPolicyHandler reports the MFA policy on GET and changes it on PUT,
+// e.g. `{"require_for_admins":true}`. Admins without MFA are made to enroll
+// at their next login; existing sessions are not ended.
+func (a *Accounts) MFAPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		var body struct {
+			RequireForAdmins *bool `json:"require_for_admins"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RequireForAdmins == nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		SetAuditResource(r.Context(), "setting", settingMFARequiredForAdmins)
+		before := a.MFARequiredForAdmins(r.Context())
+		err := a.db.WithContext(r.Context()).Save(&SettingModel{
+			Key:   settingMFARequiredForAdmins,
+			Value: strconv.FormatBool(*body.RequireForAdmins),
+		}).Error
+		if err != nil {
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		AuditChange(r.Context(), "require_for_admins", before, *body.RequireForAdmins)
+		Logger(r.Context()).Info("MFA policy changed", "require_for_admins", *body.RequireForAdmins)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"require_for_admins": a.MFARequiredForAdmins(r.Context())})
+}
+
+// LimitPerPrincipal applies the per-principal limits of l to the routes
+// that need a signed-in user.
+func (a *Accounts) LimitPerPrincipal(l *RateLimiter) {
+	a.limiter = l
+}
+
+// Routes mounts the second login step, MFA self-service, password reset
+// and email verification. The MFA endpoints check tokens themselves, since
+// enrollment also accepts a login challenge.
+func (a *Accounts) Routes(r chi.Router) {
+	r.Post("/login/mfa", a.LoginMFA)
+	r.Route("/auth/mfa", func(r chi.Router) {
+		r.Post("/enroll", a.Enroll)
+		r.Post("/activate", a.Activate)
+		r.Post("/recovery-codes", a.RegenerateRecoveryCodes)
+		r.Post("/disable", a.Disable)
+	})
+	if a.mailer == nil {
+		return
+	}
+	r.Route("/auth/password", func(r chi.Router) {
+		r.Post("/forgot", a.ForgotPassword)
+		r.Post("/reset", a.ResetPassword)
+	})
+	r.Route("/auth/email", func(r chi.Router) {
+		authenticated := r.With(RequireJWT(a.key, a.sessions))
+		if a.limiter != nil {
+			authenticated = authenticated.With(a.limiter.PrincipalMiddleware)
+		}
+		authenticated.Put("/", a.ChangeEmail)
+		r.Post("/verify", a.VerifyEmail)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
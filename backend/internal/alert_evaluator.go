@@ -0,0 +1,295 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	alertingpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/alerting/v1"
+	"gorm.io/gorm"
+)
+
+// alertLockKey makes one instance at a time evaluate the rules, so
+// replicas do not each open and notify the same alert.
+const alertLockKey = 0x616c7274 // "alrt"
+
+// AlertTransition describes an alert that just started firing or resolved.
+type AlertTransition struct {
+	Alert AlertModel
+	Rule  AlertRuleModel
+}
+
+// AlertEvaluator periodically evaluates every enabled rule against the
+// stored health metrics and moves alerts through
+// pending -> firing -> resolved. Each pass runs in one transaction holding
+// an advisory lock; instances that cannot take it skip the pass.
+type AlertEvaluator struct {
+	db       *gorm.DB
+	interval time.Duration
+	hooks    []func(context.Context, *gorm.DB, AlertTransition) error
+	suppress func(context.Context, ServiceModel, time.Time) bool
+}
+
+func NewAlertEvaluator(db *gorm.DB, interval time.Duration) *AlertEvaluator {
+	return &AlertEvaluator{db: db, interval: interval}
+}
+
+// OnTransition registers fn to be called whenever an alert fires or
+// resolves. fn runs in the transaction that records the transition, so
+// what it writes with tx is committed with it; an error rolls both back and
+// the transition is retried on the next pass.
+func (e *AlertEvaluator) OnTransition(fn func(ctx context.Context, tx *gorm.DB, t AlertTransition) error) {
+	e.hooks = append(e.hooks, fn)
+}
+
+// SuppressWhen skips evaluation for a service while fn returns true, e.g.
+// during maintenance. Its alerts keep their current state meanwhile.
+func (e *AlertEvaluator) SuppressWhen(fn func(context.Context, ServiceModel, time.Time) bool) {
+	e.suppress = fn
+}
+
+// Run evaluates the rules every interval until ctx is done.
+func (e *AlertEvaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := e.Evaluate(ctx, now); err != nil {
+				Logger(ctx).Warn("alert evaluation failed", "error", err)
+			}
+		}
+	}
+}
+
+// Evaluate runs a single evaluation pass as of now, unless another
+// instance is running one.
+func (e *AlertEvaluator) Evaluate(ctx context.Context, now time.Time) error {
+	return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", alertLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		return e.evaluateAll(ctx, tx, now)
+	})
+}
+
+// evaluateAll evaluates every rule for every targeted service. A failure
+// for one service is logged and rolled back to a savepoint, so it does not
+// hold up the others.
+func (e *AlertEvaluator) evaluateAll(ctx context.Context, db *gorm.DB, now time.Time) error {
+	var rules []AlertRuleModel
+	if err := db.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return err
+	}
+	var services []ServiceModel
+	if err := db.Find(&services).Error; err != nil {
+		return err
+	}
+
+	evaluated := map[string]bool{}
+	for _, rule := range rules {
+		expr, err := ParseAlertExpr(rule.Expr)
+		if err != nil {
+			Logger(ctx).Warn("skipping invalid alert rule", "rule_id", rule.ID, "error", err)
+			continue
+		}
+		for _, svc := range services {
+			if !ruleTargets(rule, svc) {
+				continue
+			}
+			fp := alertFingerprint(rule.ID, svc.ID)
+			evaluated[fp] = true
+			if e.suppress != nil && e.suppress(ctx, svc, now) {
+				continue
+			}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return e.evaluate(ctx, tx, rule, expr, svc.ID, fp, now)
+			})
+			if err != nil {
+				Logger(ctx).Warn("evaluating alert rule failed", "rule_id", rule.ID, "service_id", svc.ID, "error", err)
+			}
+		}
+	}
+
+	// Alerts whose rule was deleted or disabled, or whose service is no
+	// longer targeted, resolve on their own
+	var active []AlertModel
+	if err := db.Where("state IN ?", activeAlertStates).Find(&active).Error; err != nil {
+		return err
+	}
+	for _, a := range active {
+		if !evaluated[a.Fingerprint] {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return e.clear(ctx, tx, AlertRuleModel{ID: a.RuleID}, a, now)
+			})
+			if err != nil {
+				Logger(ctx).Warn("resolving orphaned alert failed", "alert_id", a.ID, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+var activeAlertStates = []int32{int32(alertingpb.AlertState_ALERT_STATE_PENDING), int32(alertingpb.AlertState_ALERT_STATE_FIRING)}
+
+func ruleTargets(rule AlertRuleModel, svc ServiceModel) bool {
+	if rule.ServiceID != "" {
+		return rule.ServiceID == svc.ID
+	}
+	return len(rule.Selector) > 0 && labelsMatch(rule.Selector, svc.Labels)
+}
+
+// labelsMatch reports whether labels contain every pair in selector.
+func labelsMatch(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func alertFingerprint(ruleID, serviceID string) string {
+	sum := sha256.Sum256([]byte(ruleID + "/" + serviceID))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (e *AlertEvaluator) evaluate(ctx context.Context, db *gorm.DB, rule AlertRuleModel, expr *AlertExpr, serviceID, fp string, now time.Time) error {
+	var firing, pending bool
+	var value float64
+	since := now.Add(-expr.Window).UnixMilli()
+	if expr.Checks > 0 {
+		// The last N samples must all match; fewer matching makes it pending.
+		// Only samples in the window count, so an alert on a service that
+		// stopped reporting resolves instead of firing on stale samples.
+		var samples []HealthMetricModel
+		if err := db.Where("service_id = ? AND timestamp >= ?", serviceID, since).Order("timestamp desc").Limit(expr.Checks).Find(&samples).Error; err != nil {
+			return err
+		}
+		matching := 0
+		for _, m := range samples {
+			if !expr.Compare(expr.Value(m)) {
+				break
+			}
+			matching++
+		}
+		if len(samples) > 0 {
+			value = expr.Value(samples[0])
+		}
+		firing = matching == expr.Checks
+		pending = matching > 0 && !firing
+	} else {
+		var samples []HealthMetricModel
+		if err := db.Where("service_id = ? AND timestamp >= ?", serviceID, since).Order("timestamp").Find(&samples).Error; err != nil {
+			return err
+		}
+		value = expr.Aggregate(samples)
+		if !math.IsNaN(value) && expr.Compare(value) {
+			pending = true
+		}
+	}
+
+	var alert AlertModel
+	err := db.Where("fingerprint = ? AND state IN ?", fp, activeAlertStates).First(&alert).Error
+	exists := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if !firing && !pending {
+		if exists {
+			return e.clear(ctx, db, rule, alert, now)
+		}
+		return nil
+	}
+
+	if !exists {
+		alert = AlertModel{
+			ID:          newID(),
+			RuleID:      rule.ID,
+			ServiceID:   serviceID,
+			Fingerprint: fp,
+			State:       int32(alertingpb.AlertState_ALERT_STATE_PENDING),
+			StartedAt:   now.UnixMilli(),
+		}
+	}
+	alert.Value = value
+
+	// Duration-based rules fire once the condition has held for `for`
+	if expr.Checks == 0 && now.Sub(time.UnixMilli(alert.StartedAt)) >= expr.For {
+		firing = true
+	}
+	becameFiring := firing && alert.State != int32(alertingpb.AlertState_ALERT_STATE_FIRING)
+	if becameFiring {
+		alert.State = int32(alertingpb.AlertState_ALERT_STATE_FIRING)
+		alert.FiredAt = now.UnixMilli()
+	}
+
+	if err := db.Save(&alert).Error; err != nil {
+		return err
+	}
+	if becameFiring {
+		return e.notify(ctx, db, AlertTransition{Alert: alert, Rule: rule})
+	}
+	return nil
+}
+
+// clear drops a pending alert or resolves a firing one.
+func (e *AlertEvaluator) clear(ctx context.Context, db *gorm.DB, rule AlertRuleModel, alert AlertModel, now time.Time) error {
+	if alert.State == int32(alertingpb.AlertState_ALERT_STATE_PENDING) {
+		return db.Delete(&alert).Error
+	}
+
+	alert.State = int32(alertingpb.AlertState_ALERT_STATE_RESOLVED)
+	alert.ResolvedAt = now.UnixMilli()
+	if err := db.Save(&alert).Error; err != nil {
+		return err
+	}
+	if rule.Name == "" {
+		// Orphaned alerts only carry the rule ID; the rule may be gone
+		if err := db.Limit(1).Find(&rule, "id = ?", rule.ID).Error; err != nil {
+			return err
+		}
+	}
+	return e.notify(ctx, db, AlertTransition{Alert: alert, Rule: rule})
+}
+
+func (e *AlertEvaluator) notify(ctx context.Context, tx *gorm.DB, t AlertTransition) error {
+	for _, fn := range e.hooks {
+		if err := fn(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	Logger(ctx).Info("alert state changed", "alert_id", t.Alert.ID, "rule_id", t.Rule.ID,
+		"service_id", t.Alert.ServiceID, "state", alertingpb.AlertState(t.Alert.State).String(), "value", t.Alert.Value)
+	return nil
+}
+
+// migrateAlerts allows one pending or firing alert per fingerprint. Extra
+// ones, left by instances that evaluated concurrently, are resolved first.
+func migrateAlerts(db *gorm.DB) error {
+	active := fmt.Sprintf("state IN (%d, %d)", alertingpb.AlertState_ALERT_STATE_PENDING, alertingpb.AlertState_ALERT_STATE_FIRING)
+	stmts := []string{
+		fmt.Sprintf(`UPDATE alert_models SET state = %d, resolved_at = %d
+WHERE %s AND id NOT IN (
+    SELECT DISTINCT ON (fingerprint) id FROM alert_models WHERE %s ORDER BY fingerprint, started_at
+)`, alertingpb.AlertState_ALERT_STATE_RESOLVED, time.Now().UnixMilli(), active, active),
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_alert_models_active_fingerprint ON alert_models (fingerprint) WHERE " + active,
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
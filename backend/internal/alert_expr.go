@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+)
+
+// defaultAlertWindow is how far back aggregations look when a rule has no
+// `over` clause.
+const defaultAlertWindow = 5 * time.Minute
+
+// AlertExpr is a parsed alert rule expression:
+//
+//	<metric> [<aggregation>] <op> <value> [over <window>] [for <duration> | for <n> checks]
+type AlertExpr struct {
+	Metric      string        // latency_ms, error_rate or status
+	Aggregation string        // last, avg, min, max, count or pNN
+	Op          string        // >, >=, <, <=, ==, !=
+	Threshold   float64       // status names are converted to their enum value
+	Window      time.Duration // samples considered by the aggregation
+	For         time.Duration // how long the condition must hold before firing
+	Checks      int           // or: how many consecutive samples in Window must match
+}
+
+var alertMetrics = map[string]bool{"latency_ms": true, "error_rate": true, "status": true}
+
+var alertOps = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// ParseAlertExpr parses expressions such as "latency_ms p95 > 500 for 5m",
+// "status == DOWN for 2 checks" or "error_rate > 0.05".
+func ParseAlertExpr(s string) (*AlertExpr, error) {
+	tokens := strings.Fields(s)
+	if len(tokens) < 3 {
+		return nil, fmt.Errorf("expression %q is too short", s)
+	}
+
+	e := &AlertExpr{Metric: tokens[0], Aggregation: "last", Window: defaultAlertWindow}
+	if !alertMetrics[e.Metric] {
+		return nil, fmt.Errorf("unknown metric %q", e.Metric)
+	}
+	tokens = tokens[1:]
+
+	if _, isOp := alertOps[tokens[0]]; !isOp {
+		if !validAggregation(tokens[0]) {
+			return nil, fmt.Errorf("unknown aggregation %q", tokens[0])
+		}
+		e.Aggregation = tokens[0]
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) < 2 {
+		return nil, fmt.Errorf("expression %q is missing a comparison", s)
+	}
+	if _, ok := alertOps[tokens[0]]; !ok {
+		return nil, fmt.Errorf("unknown operator %q", tokens[0])
+	}
+	e.Op = tokens[0]
+	threshold, err := parseAlertValue(e.Metric, tokens[1])
+	if err != nil {
+		return nil, err
+	}
+	e.Threshold = threshold
+	tokens = tokens[2:]
+
+	for len(tokens) > 0 {
+		switch {
+		case tokens[0] == "over" && len(tokens) >= 2:
+			if e.Window, err = time.ParseDuration(tokens[1]); err != nil || e.Window <= 0 {
+				return nil, fmt.Errorf("invalid window %q", tokens[1])
+			}
+			tokens = tokens[2:]
+		case tokens[0] == "for" && len(tokens) >= 3 && strings.HasPrefix(tokens[2], "check"):
+			if e.Checks, err = strconv.Atoi(tokens[1]); err != nil || e.Checks < 1 {
+				return nil, fmt.Errorf("invalid check count %q", tokens[1])
+			}
+			tokens = tokens[3:]
+		case tokens[0] == "for" && len(tokens) >= 2:
+			if e.For, err = time.ParseDuration(tokens[1]); err != nil || e.For < 0 {
+				return nil, fmt.Errorf("invalid duration %q", tokens[1])
+			}
+			tokens = tokens[2:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in expression", strings.Join(tokens, " "))
+		}
+	}
+	if e.Checks > 0 && e.For > 0 {
+		return nil, fmt.Errorf("use either `for <duration>` or `for <n> checks`, not both")
+	}
+	return e, nil
+}
+
+func validAggregation(agg string) bool {
+	switch agg {
+	case "last", "avg", "min", "max", "count":
+		return true
+	}
+	if strings.HasPrefix(agg, "p") {
+		q, err := strconv.ParseFloat(agg[1:], 64)
+		return err == nil && q > 0 && q <= 100
+	}
+	return false
+}
+
+func parseAlertValue(metric, v string) (float64, error) {
+	if metric == "status" {
+		if n, ok := healthpb.Status_value["STATUS_"+strings.ToUpper(v)]; ok {
+			return float64(n), nil
+		}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", v)
+	}
+	return f, nil
+}
+
+// Compare applies the expression's operator to value.
+func (e *AlertExpr) Compare(value float64) bool {
+	return alertOps[e.Op](value, e.Threshold)
+}
+
+// Value extracts the expression's metric from one sample.
+func (e *AlertExpr) Value(m HealthMetricModel) float64 {
+	switch e.Metric {
+	case "latency_ms":
+		return float64(m.LatencyMs)
+	case "error_rate":
+		return float64(m.ErrorRate)
+	default:
+		return float64(m.Status)
+	}
+}
+
+// Aggregate reduces samples (oldest first) to a single value.
+func (e *AlertExpr) Aggregate(samples []HealthMetricModel) float64 {
+	values := make([]float64, len(samples))
+	for i, m := range samples {
+		values[i] = e.Value(m)
+	}
+	return aggregate(e.Aggregation, values)
+}
+
+func aggregate(agg string, values []float64) float64 {
+	if agg == "count" {
+		return float64(len(values))
+	}
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	switch agg {
+	case "last":
+		return values[len(values)-1]
+	case "avg":
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	case "min", "max":
+		r := values[0]
+		for _, v := range values[1:] {
+			if (agg == "min" && v < r) || (agg == "max" && v > r) {
+				r = v
+			}
+		}
+		return r
+	default:
+		q, _ := strconv.ParseFloat(agg[1:], 64)
+		return percentile(values, q)
+	}
+}
+
+// percentile uses the nearest-rank method on a copy of values.
+func percentile(values []float64, q float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(q/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
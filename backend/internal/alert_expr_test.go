@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseAlertExpr(t *testing.T) {
+	tests := []struct {
+		in   string
+		want AlertExpr
+	}{
+		{"error_rate > 0.05", AlertExpr{Metric: "error_rate", Aggregation: "last", Op: ">", Threshold: 0.05, Window: defaultAlertWindow}},
+		{"latency_ms p95 > 500 for 5m", AlertExpr{Metric: "latency_ms", Aggregation: "p95", Op: ">", Threshold: 500, Window: defaultAlertWindow, For: 5 * time.Minute}},
+		{"status == DOWN for 2 checks", AlertExpr{Metric: "status", Aggregation: "last", Op: "==", Threshold: 2, Window: defaultAlertWindow, Checks: 2}},
+		{"status != up for 1 check", AlertExpr{Metric: "status", Aggregation: "last", Op: "!=", Threshold: 1, Window: defaultAlertWindow, Checks: 1}},
+		{"error_rate avg >= 0.1 over 10m", AlertExpr{Metric: "error_rate", Aggregation: "avg", Op: ">=", Threshold: 0.1, Window: 10 * time.Minute}},
+		{"latency_ms max < 50 over 1h for 30s", AlertExpr{Metric: "latency_ms", Aggregation: "max", Op: "<", Threshold: 50, Window: time.Hour, For: 30 * time.Second}},
+		{"latency_ms count <= 0", AlertExpr{Metric: "latency_ms", Aggregation: "count", Op: "<=", Threshold: 0, Window: defaultAlertWindow}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAlertExpr(tt.in)
+		if err != nil {
+			t.Errorf("ParseAlertExpr(%q): %v", tt.in, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("ParseAlertExpr(%q) = %+v, want %+v", tt.in, *got, tt.want)
+		}
+	}
+}
+
+func TestParseAlertExprErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"latency_ms >",
+		"cpu > 1",
+		"latency_ms p0 > 1",
+		"latency_ms p101 > 1",
+		"latency_ms median > 1",
+		"latency_ms =~ 1",
+		"latency_ms > fast",
+		"status == SIDEWAYS",
+		"latency_ms > 1 over 0s",
+		"latency_ms > 1 over soon",
+		"latency_ms > 1 for -1m",
+		"latency_ms > 1 for 0 checks",
+		"latency_ms > 1 for 5m for 2 checks",
+		"latency_ms > 1 because",
+	} {
+		if e, err := ParseAlertExpr(in); err == nil {
+			t.Errorf("ParseAlertExpr(%q) = %+v, want an error", in, *e)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	values := []float64{40, 10, 30, 20, 50}
+	tests := []struct {
+		agg    string
+		values []float64
+		want   float64
+	}{
+		{"last", values, 50},
+		{"avg", values, 30},
+		{"min", values, 10},
+		{"max", values, 50},
+		{"count", values, 5},
+		{"count", nil, 0},
+		{"p50", values, 30},
+		{"p95", values, 50},
+		{"p20", values, 10},
+		{"p100", values, 50},
+		{"p1", []float64{7}, 7},
+	}
+	for _, tt := range tests {
+		if got := aggregate(tt.agg, tt.values); got != tt.want {
+			t.Errorf("aggregate(%q, %v) = %v, want %v", tt.agg, tt.values, got, tt.want)
+		}
+	}
+	for _, agg := range []string{"last", "avg", "min", "max", "p99"} {
+		if got := aggregate(agg, nil); !math.IsNaN(got) {
+			t.Errorf("aggregate(%q, nil) = %v, want NaN", agg, got)
+		}
+	}
+	if values[0] != 40 {
+		t.Errorf("percentile sorted its input: %v", values)
+	}
+}
+
+func TestAlertExprAggregate(t *testing.T) {
+	e, err := ParseAlertExpr("error_rate avg > 0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := []HealthMetricModel{{ErrorRate: 0.5}, {ErrorRate: 0.25}, {ErrorRate: 0}}
+	got := e.Aggregate(samples)
+	if got != 0.25 || !e.Compare(got) {
+		t.Errorf("Aggregate = %v, Compare = %v; want 0.25, true", got, e.Compare(got))
+	}
+}
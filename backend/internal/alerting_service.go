@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	alertingpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/alerting/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type AlertingServerImpl struct {
+	alertingpb.UnimplementedAlertingServiceServer
+	db *gorm.DB
+}
+
+func NewAlertingServer(db *gorm.DB) *AlertingServerImpl {
+	return &AlertingServerImpl{db: db}
+}
+
+// CreateRule validates and stores a new alert rule
+func (s *AlertingServerImpl) CreateRule(ctx context.Context, req *alertingpb.CreateRuleRequest) (*alertingpb.CreateRuleResponse, error) {
+	if err := validateRule(req.Rule); err != nil {
+		return nil, err
+	}
+
+	m := ruleFromProto(req.Rule)
+	m.ID = newID()
+	if err := s.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, err
+	}
+	return &alertingpb.CreateRuleResponse{Rule: ruleToProto(m)}, nil
+}
+
+// UpdateRule replaces an existing alert rule
+func (s *AlertingServerImpl) UpdateRule(ctx context.Context, req *alertingpb.UpdateRuleRequest) (*alertingpb.UpdateRuleResponse, error) {
+	if err := validateRule(req.Rule); err != nil {
+		return nil, err
+	}
+
+	m := ruleFromProto(req.Rule)
+	if err := s.db.WithContext(ctx).First(&AlertRuleModel{}, "id = ?", m.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "rule %q not found", m.ID)
+		}
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(&m).Error; err != nil {
+		return nil, err
+	}
+	return &alertingpb.UpdateRuleResponse{Rule: ruleToProto(m)}, nil
+}
+
+// DeleteRule removes a rule; its active alerts resolve on the next evaluation
+func (s *AlertingServerImpl) DeleteRule(ctx context.Context, req *alertingpb.DeleteRuleRequest) (*alertingpb.DeleteRuleResponse, error) {
+	result := s.db.WithContext(ctx).Delete(&AlertRuleModel{}, "id = ?", req.Id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "rule %q not found", req.Id)
+	}
+	return &alertingpb.DeleteRuleResponse{}, nil
+}
+
+// ListRules returns every alert rule
+func (s *AlertingServerImpl) ListRules(ctx context.Context, req *alertingpb.ListRulesRequest) (*alertingpb.ListRulesResponse, error) {
+	var rules []AlertRuleModel
+	if err := s.db.WithContext(ctx).Order("name").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &alertingpb.ListRulesResponse{}
+	for _, m := range rules {
+		resp.Rules = append(resp.Rules, ruleToProto(m))
+	}
+	return resp, nil
+}
+
+// ListAlerts returns pending and firing alerts, and optionally resolved ones
+func (s *AlertingServerImpl) ListAlerts(ctx context.Context, req *alertingpb.ListAlertsRequest) (*alertingpb.ListAlertsResponse, error) {
+	q := s.db.WithContext(ctx).Order("started_at desc")
+	if req.ServiceId != "" {
+		q = q.Where("service_id = ?", req.ServiceId)
+	}
+	if !req.IncludeResolved {
+		q = q.Where("state IN ?", activeAlertStates)
+	}
+	var alerts []AlertModel
+	if err := q.Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	// Resolve rule names and severities in one query
+	ruleIDs := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		ruleIDs = append(ruleIDs, a.RuleID)
+	}
+	var rules []AlertRuleModel
+	if err := s.db.WithContext(ctx).Where("id IN ?", ruleIDs).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]AlertRuleModel, len(rules))
+	for _, r := range rules {
+		byID[r.ID] = r
+	}
+
+	resp := &alertingpb.ListAlertsResponse{}
+	for _, a := range alerts {
+		resp.Alerts = append(resp.Alerts, alertToProto(a, byID[a.RuleID]))
+	}
+	return resp, nil
+}
+
+func validateRule(r *alertingpb.AlertRule) error {
+	if r == nil {
+		return status.Error(codes.InvalidArgument, "rule is required")
+	}
+	if (r.ServiceId == "") == (len(r.Selector) == 0) {
+		return status.Error(codes.InvalidArgument, "exactly one of service_id or selector must be set")
+	}
+	if _, err := ParseAlertExpr(r.Expr); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid expr: %v", err)
+	}
+	return nil
+}
+
+func ruleToProto(m AlertRuleModel) *alertingpb.AlertRule {
+	return &alertingpb.AlertRule{
+		Id:        m.ID,
+		Name:      m.Name,
+		ServiceId: m.ServiceID,
+		Selector:  m.Selector,
+		Expr:      m.Expr,
+		Severity:  m.Severity,
+		Enabled:   m.Enabled,
+	}
+}
+
+func ruleFromProto(p *alertingpb.AlertRule) AlertRuleModel {
+	return AlertRuleModel{
+		ID:        p.Id,
+		Name:      p.Name,
+		ServiceID: p.ServiceId,
+		Selector:  p.Selector,
+		Expr:      p.Expr,
+		Severity:  p.Severity,
+		Enabled:   p.Enabled,
+	}
+}
+
+func alertToProto(a AlertModel, rule AlertRuleModel) *alertingpb.Alert {
+	return &alertingpb.Alert{
+		Id:           a.ID,
+		RuleId:       a.RuleID,
+		RuleName:     rule.Name,
+		ServiceId:    a.ServiceID,
+		Severity:     rule.Severity,
+		State:        alertingpb.AlertState(a.State),
+		Value:        a.Value,
+		Fingerprint:  a.Fingerprint,
+		StartedAtMs:  a.StartedAt,
+		FiredAtMs:    a.FiredAt,
+		ResolvedAtMs: a.ResolvedAt,
+	}
+}
@@ -0,0 +1,351 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	hoursPerWeek = 7 * 24
+	// anomalyGap ends an ongoing anomaly when a service stops reporting.
+	anomalyGap = 10 * time.Minute
+	// anomalyStateTTL outlives a week so seasonal slots survive until the
+	// same hour comes round again.
+	anomalyStateTTL = 5 * 7 * 24 * time.Hour
+	// minSeasonSamples is how many normal samples an hour needs before it
+	// counts towards its seasonal slot.
+	minSeasonSamples = 10
+
+	defaultAnomalyLimit = 100
+	maxAnomalyLimit     = 1000
+)
+
+// AnomalyConfig tunes the anomaly detector.
+type AnomalyConfig struct {
+	// Threshold is the score at or above which a sample is anomalous.
+	Threshold float64
+	// Warmup is how many samples the short-term baseline needs before
+	// samples are scored.
+	Warmup int64
+	// Alpha weighs each sample in the short-term baseline; anomalous
+	// samples weigh a tenth of it, so a lasting shift is absorbed slowly.
+	Alpha float64
+	// SeasonAlpha weighs the latest week in each hour-of-week baseline.
+	SeasonAlpha float64
+}
+
+var DefaultAnomalyConfig = AnomalyConfig{Threshold: 4, Warmup: 30, Alpha: 0.02, SeasonAlpha: 0.3}
+
+// anomalyMetric describes how one metric is scored. Values are transformed
+// before their mean and variance are tracked (latency is log-scaled since
+// it is heavily skewed), and floor is the smallest standard deviation used,
+// so a perfectly flat baseline does not turn tiny changes into anomalies.
+type anomalyMetric struct {
+	name      string
+	value     func(HealthMetricModel) float64
+	transform func(float64) float64
+	inverse   func(float64) float64
+	floor     float64
+	format    func(float64) string
+}
+
+var anomalyMetrics = []anomalyMetric{
+	{
+		name:      "latency_ms",
+		value:     func(m HealthMetricModel) float64 { return float64(m.LatencyMs) },
+		transform: math.Log1p,
+		inverse:   math.Expm1,
+		floor:     0.2,
+		format:    func(v float64) string { return fmt.Sprintf("%.0fms", v) },
+	},
+	{
+		name:      "error_rate",
+		value:     func(m HealthMetricModel) float64 { return float64(m.ErrorRate) },
+		transform: func(v float64) float64 { return v },
+		inverse:   func(v float64) float64 { return v },
+		floor:     0.02,
+		format:    func(v float64) string { return fmt.Sprintf("%.1f%%", 100*v) },
+	},
+}
+
+// ewma is an exponentially weighted mean and variance.
+type ewma struct {
+	N    int64   `json:"n"`
+	Mean float64 `json:"mean"`
+	Var  float64 `json:"var"`
+}
+
+func (e *ewma) add(x, alpha float64) {
+	if e.N == 0 {
+		e.Mean = x
+	} else {
+		d := x - e.Mean
+		e.Mean += alpha * d
+		e.Var = (1 - alpha) * (e.Var + alpha*d*d)
+	}
+	e.N++
+}
+
+// welford accumulates the exact mean and variance of one hour.
+type welford struct {
+	N    int64   `json:"n"`
+	Mean float64 `json:"mean"`
+	M2   float64 `json:"m2"`
+}
+
+func (w *welford) add(x float64) {
+	w.N++
+	d := x - w.Mean
+	w.Mean += d / float64(w.N)
+	w.M2 += d * (x - w.Mean)
+}
+
+// seasonSlot is the baseline of one hour of the week, updated once a week.
+type seasonSlot struct {
+	Mean  float64 `json:"mean"`
+	Var   float64 `json:"var"`
+	Weeks int     `json:"weeks"`
+}
+
+// metricBaseline is the detector state of one metric of one service.
+type metricBaseline struct {
+	Short ewma                `json:"short"`
+	Slots map[int]*seasonSlot `json:"slots"`
+	Hour  int64               `json:"hour"` // unix hour Acc belongs to
+	Acc   welford             `json:"acc"`
+	Open  *AnomalyEventModel  `json:"open,omitempty"`
+}
+
+// fold adds the finished hour to its seasonal slot.
+func (b *metricBaseline) fold(alpha float64) {
+	if b.Acc.N < minSeasonSamples {
+		return
+	}
+	k := int(b.Hour % hoursPerWeek)
+	hourVar := b.Acc.M2 / float64(b.Acc.N)
+	slot := b.Slots[k]
+	if slot == nil {
+		b.Slots[k] = &seasonSlot{Mean: b.Acc.Mean, Var: hourVar, Weeks: 1}
+		return
+	}
+	d := b.Acc.Mean - slot.Mean
+	slot.Var = (1-alpha)*slot.Var + alpha*(hourVar+d*d)
+	slot.Mean += alpha * d
+	slot.Weeks++
+}
+
+// baseline returns the expected mean and variance at hour: the seasonal
+// slot once it has seen a week, otherwise the short-term baseline.
+func (b *metricBaseline) baseline(hour int64, warmup int64) (mean, variance float64, ok bool) {
+	if slot := b.Slots[int(hour%hoursPerWeek)]; slot != nil {
+		return slot.Mean, slot.Var, true
+	}
+	return b.Short.Mean, b.Short.Var, b.Short.N >= warmup
+}
+
+// anomalyState is the detector state of one service. It depends only on
+// the samples observed, so replaying recorded samples is deterministic.
+type anomalyState struct {
+	Last    int64                      `json:"last"`
+	Metrics map[string]*metricBaseline `json:"metrics"`
+}
+
+func newAnomalyState() *anomalyState {
+	return &anomalyState{Metrics: map[string]*metricBaseline{}}
+}
+
+// observe scores one sample against the baselines and then learns from it.
+// It returns the score, a reason per anomalous metric, and the anomaly
+// events that were opened, extended or ended.
+func (s *anomalyState) observe(cfg AnomalyConfig, m HealthMetricModel) (float64, []string, []AnomalyEventModel) {
+	var (
+		score   float64
+		reasons []string
+		changed []AnomalyEventModel
+	)
+	hour := m.Timestamp / time.Hour.Milliseconds()
+	gap := s.Last > 0 && m.Timestamp-s.Last > anomalyGap.Milliseconds()
+
+	for _, am := range anomalyMetrics {
+		b := s.Metrics[am.name]
+		if b == nil {
+			b = &metricBaseline{Slots: map[int]*seasonSlot{}, Hour: hour}
+			s.Metrics[am.name] = b
+		}
+		if b.Hour != hour {
+			b.fold(cfg.SeasonAlpha)
+			b.Hour, b.Acc = hour, welford{}
+		}
+		if gap && b.Open != nil {
+			b.Open.EndedAt = s.Last
+			changed = append(changed, *b.Open)
+			b.Open = nil
+		}
+
+		raw := am.value(m)
+		x := am.transform(raw)
+		z := 0.0
+		mean, variance, ok := b.baseline(hour, cfg.Warmup)
+		if ok {
+			z = math.Max(0, (x-mean)/math.Sqrt(variance+am.floor*am.floor))
+		}
+		anomalous := ok && z >= cfg.Threshold
+		score = math.Max(score, z)
+
+		if anomalous {
+			b.Short.add(x, cfg.Alpha/10)
+			if b.Open == nil {
+				b.Open = &AnomalyEventModel{
+					ID:        fmt.Sprintf("%s:%s:%d", m.ServiceID, am.name, m.Timestamp),
+					ServiceID: m.ServiceID,
+					Metric:    am.name,
+					StartedAt: m.Timestamp,
+					Baseline:  am.inverse(mean),
+				}
+			}
+			b.Open.Samples++
+			b.Open.MaxScore = math.Max(b.Open.MaxScore, z)
+			b.Open.PeakValue = math.Max(b.Open.PeakValue, raw)
+			changed = append(changed, *b.Open)
+			reasons = append(reasons, fmt.Sprintf("%s anomaly: %s vs baseline %s (score %.1f)",
+				am.name, am.format(raw), am.format(am.inverse(mean)), z))
+			continue
+		}
+		b.Short.add(x, cfg.Alpha)
+		b.Acc.add(x)
+		if b.Open != nil {
+			b.Open.EndedAt = m.Timestamp
+			changed = append(changed, *b.Open)
+			b.Open = nil
+		}
+	}
+	s.Last = m.Timestamp
+	return score, reasons, changed
+}
+
+// ReplayAnomalies runs the detector over recorded samples of one service
+// (oldest first) and returns the anomaly events it would have recorded.
+func ReplayAnomalies(cfg AnomalyConfig, samples []HealthMetricModel) []AnomalyEventModel {
+	state := newAnomalyState()
+	var events []AnomalyEventModel
+	index := map[string]int{}
+	for _, m := range samples {
+		_, _, changed := state.observe(cfg, m)
+		for _, ev := range changed {
+			if i, ok := index[ev.ID]; ok {
+				events[i] = ev
+				continue
+			}
+			index[ev.ID] = len(events)
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// AnomalyDetector scores health samples against per-service baselines kept
+// in Redis and stores anomaly events. Without Redis samples are not scored.
+type AnomalyDetector struct {
+	db    *gorm.DB
+	redis *redis.Client
+	cfg   AnomalyConfig
+	mu    sync.Mutex
+}
+
+func NewAnomalyDetector(db *gorm.DB, redisClient *redis.Client, cfg AnomalyConfig) *AnomalyDetector {
+	return &AnomalyDetector{db: db, redis: redisClient, cfg: cfg}
+}
+
+// Observe sets the anomaly score of a sample, adds reasons for anomalous
+// metrics and records the anomaly events it opens, extends or ends.
+func (d *AnomalyDetector) Observe(ctx context.Context, m *HealthMetricModel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := "anomaly:state:" + m.ServiceID
+	state := newAnomalyState()
+	raw, err := d.redis.Get(ctx, key).Bytes()
+	switch {
+	case err == nil:
+		if json.Unmarshal(raw, state) != nil {
+			state = newAnomalyState()
+		}
+	case !errors.Is(err, redis.Nil):
+		return
+	}
+	if m.Timestamp < state.Last {
+		return
+	}
+
+	score, reasons, changed := state.observe(d.cfg, *m)
+	m.AnomalyScore = score
+	m.Reasons = append(m.Reasons, reasons...)
+
+	raw, _ = json.Marshal(state)
+	if err := d.redis.Set(ctx, key, raw, anomalyStateTTL).Err(); err != nil {
+		Logger(ctx).Warn("failed to save anomaly baseline", "service_id", m.ServiceID, "error", err)
+	}
+	for i := range changed {
+		if err := d.db.WithContext(ctx).Save(&changed[i]).Error; err != nil {
+			Logger(ctx).Warn("failed to store anomaly event", "service_id", m.ServiceID, "error", err)
+		}
+	}
+}
+
+// ListAnomalies returns the anomaly events that overlap a time range
+func (h *HealthServerImpl) ListAnomalies(ctx context.Context, req *healthpb.ListAnomaliesRequest) (*healthpb.ListAnomaliesResponse, error) {
+	to := time.Now().UnixMilli()
+	if req.ToMs > 0 {
+		to = req.ToMs
+	}
+	from := to - (24 * time.Hour).Milliseconds()
+	if req.FromMs > 0 {
+		from = req.FromMs
+	}
+	if from >= to {
+		return nil, status.Error(codes.InvalidArgument, "from_ms must be before to_ms")
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultAnomalyLimit
+	}
+	limit = min(limit, maxAnomalyLimit)
+
+	q := h.db.WithContext(ctx).Where("started_at < ? AND (ended_at = 0 OR ended_at >= ?)", to, from)
+	if req.ServiceId != "" {
+		q = q.Where("service_id = ?", req.ServiceId)
+	}
+	if req.Ongoing {
+		q = q.Where("ended_at = 0")
+	}
+	var events []AnomalyEventModel
+	if err := q.Order("started_at desc").Limit(limit).Find(&events).Error; err != nil {
+		return nil, status.Error(codes.Unavailable, "failed to list anomalies")
+	}
+	resp := &healthpb.ListAnomaliesResponse{}
+	for _, ev := range events {
+		resp.Anomalies = append(resp.Anomalies, &healthpb.AnomalyEvent{
+			Id:          ev.ID,
+			ServiceId:   ev.ServiceID,
+			Metric:      ev.Metric,
+			StartedAtMs: ev.StartedAt,
+			EndedAtMs:   ev.EndedAt,
+			Samples:     ev.Samples,
+			MaxScore:    ev.MaxScore,
+			PeakValue:   ev.PeakValue,
+			Baseline:    ev.Baseline,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadSamples reads recorded samples of one service from
+// testdata/anomaly/<name>, one "timestamp_ms,latency_ms,error_rate" per line.
+func loadSamples(t *testing.T, name string) []HealthMetricModel {
+	t.Helper()
+	f, err := os.Open("testdata/anomaly/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var samples []HealthMetricModel
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		ts, err1 := strconv.ParseInt(fields[0], 10, 64)
+		latency, err2 := strconv.ParseInt(fields[1], 10, 32)
+		errRate, err3 := strconv.ParseFloat(fields[2], 32)
+		if err1 != nil || err2 != nil || err3 != nil {
+			t.Fatalf("%s: invalid line %q", name, line)
+		}
+		samples = append(samples, HealthMetricModel{ServiceID: "svc", Timestamp: ts, LatencyMs: int32(latency), ErrorRate: float32(errRate)})
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
+
+func TestReplayAnomalies(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []AnomalyEventModel // ID, Metric, StartedAt, EndedAt and Samples are compared
+	}{
+		{"steady.csv", nil},
+		{"latency_spike.csv", []AnomalyEventModel{
+			{ID: "svc:latency_ms:1760003615000", Metric: "latency_ms", StartedAt: 1760003615000, EndedAt: 1760003735000, Samples: 8},
+		}},
+		// The service stops reporting during the burst: the event ends at
+		// the last sample before the gap
+		{"error_burst_gap.csv", []AnomalyEventModel{
+			{ID: "svc:error_rate:1760003615000", Metric: "error_rate", StartedAt: 1760003615000, EndedAt: 1760003675000, Samples: 5},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := ReplayAnomalies(DefaultAnomalyConfig, loadSamples(t, tt.fixture))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d events, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, ev := range got {
+				w := tt.want[i]
+				if ev.ID != w.ID || ev.Metric != w.Metric || ev.StartedAt != w.StartedAt || ev.EndedAt != w.EndedAt || ev.Samples != w.Samples {
+					t.Errorf("event %d = %+v, want %+v", i, ev, w)
+				}
+				if ev.MaxScore < DefaultAnomalyConfig.Threshold || ev.PeakValue <= ev.Baseline {
+					t.Errorf("event %d scored %v with peak %v over baseline %v", i, ev.MaxScore, ev.PeakValue, ev.Baseline)
+				}
+			}
+		})
+	}
+}
+
+func TestAnomalyWarmup(t *testing.T) {
+	samples := loadSamples(t, "latency_spike.csv")
+	state := newAnomalyState()
+	for i, m := range samples[:DefaultAnomalyConfig.Warmup] {
+		if i > 0 {
+			m.LatencyMs *= 20 // would be anomalous after warmup
+		}
+		if score, _, _ := state.observe(DefaultAnomalyConfig, m); score != 0 {
+			t.Fatalf("sample %d scored %v during warmup", i, score)
+		}
+	}
+}
+
+// TestAnomalySeasonal feeds a week of a daily peak: once the peak hour has
+// a seasonal baseline it is no longer anomalous, while the same latency at
+// a quiet hour still is.
+func TestAnomalySeasonal(t *testing.T) {
+	const peakHour = 12
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC) // a Monday
+	latency := func(at time.Time, i int) int32 {
+		jitter := int32(i%5) - 2
+		if at.Hour() == peakHour {
+			return 400 + 10*jitter
+		}
+		return 40 + jitter
+	}
+
+	state := newAnomalyState()
+	i := 0
+	var firstPeak, lastPeak float64
+	for at := start; at.Before(start.Add(8 * 24 * time.Hour)); at = at.Add(time.Minute) {
+		m := HealthMetricModel{ServiceID: "svc", Timestamp: at.UnixMilli(), LatencyMs: latency(at, i)}
+		score, _, _ := state.observe(DefaultAnomalyConfig, m)
+		if at.Hour() == peakHour && at.Minute() == 0 {
+			if at.Before(start.Add(24 * time.Hour)) {
+				firstPeak = score
+			}
+			lastPeak = score
+		}
+		i++
+	}
+	if firstPeak < DefaultAnomalyConfig.Threshold {
+		t.Errorf("first peak scored %v, want an anomaly", firstPeak)
+	}
+	if lastPeak >= DefaultAnomalyConfig.Threshold {
+		t.Errorf("peak after a week scored %v, want it within the seasonal baseline", lastPeak)
+	}
+
+	quiet := start.Add(8*24*time.Hour + 3*time.Hour)
+	if score, _, _ := state.observe(DefaultAnomalyConfig, HealthMetricModel{ServiceID: "svc", Timestamp: quiet.UnixMilli(), LatencyMs: 400}); score < DefaultAnomalyConfig.Threshold {
+		t.Errorf("peak latency at a quiet hour scored %v, want an anomaly", score)
+	}
+}
+
+// TestAnomalyDetectorObserve checks that the detector keeping its state in
+// Redis records the same events as a replay.
+func TestAnomalyDetectorObserve(t *testing.T) {
+	client, _ := newTestRedis(t)
+	db := newTestDB(t, &AnomalyEventModel{})
+	d := NewAnomalyDetector(db, client, DefaultAnomalyConfig)
+
+	samples := loadSamples(t, "latency_spike.csv")
+	var maxScore float64
+	for _, m := range samples {
+		d.Observe(context.Background(), &m)
+		maxScore = math.Max(maxScore, m.AnomalyScore)
+	}
+	if maxScore < DefaultAnomalyConfig.Threshold {
+		t.Errorf("max score %v, want an anomaly", maxScore)
+	}
+
+	var stored []AnomalyEventModel
+	db.Order("started_at").Find(&stored)
+	want := ReplayAnomalies(DefaultAnomalyConfig, samples)
+	if len(stored) != len(want) {
+		t.Fatalf("stored %d events, want %d", len(stored), len(want))
+	}
+	for i := range want {
+		if stored[i].ID != want[i].ID || stored[i].EndedAt != want[i].EndedAt || stored[i].Samples != want[i].Samples {
+			t.Errorf("stored %+v, want %+v", stored[i], want[i])
+		}
+	}
+
+	// Samples older than the state are ignored
+	late := samples[0]
+	d.Observe(context.Background(), &late)
+	if late.AnomalyScore != 0 || len(late.Reasons) != 0 {
+		t.Errorf("late sample scored %v: %q", late.AnomalyScore, late.Reasons)
+	}
+}
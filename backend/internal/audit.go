@@ -0,0 +1,469 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	auditpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/audit/v1"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gorm.io/gorm"
+)
+
+const (
+	// auditLockKey serializes appends across instances so the chain never forks.
+	auditLockKey = 0x61756474 // "audt"
+
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+
+	// anonymousAuditInterval is how often a call rejected for lack of
+	// credentials is recorded per client IP and method; the rest are only
+	// counted, so anonymous clients cannot fill the log or hold its lock.
+	anonymousAuditInterval = time.Minute
+	// maxAnonymousAuditKeys bounds the memory the sampling takes.
+	maxAnonymousAuditKeys = 10000
+)
+
+var (
+	auditFailures = NewCounter("team15_audit_write_failures_total",
+		"Audit events that could not be written.", nil)
+	auditAnonymousSkipped = NewCounter("team15_audit_anonymous_skipped_total",
+		"Calls rejected for lack of credentials that were counted instead of written to the audit log.", nil)
+)
+
+// auditRedacted columns are recorded as changed without their values.
+var auditRedacted = map[string]bool{"secret": true, "token_hash": true, "password_hash": true}
+
+// auditSpec says which row a mutating RPC changes. The row's ID is read
+// from the request at reqID, or from the response at respID when the
+// server assigns it.
+type auditSpec struct {
+	resource string
+	model    func() interface{}
+	reqID    string
+	respID   string
+}
+
+var auditedMethods = map[string]auditSpec{
+	"/catalog.v1.CatalogService/CreateService":               {resource: "service", model: func() interface{} { return &ServiceModel{} }, reqID: "service.id"},
+	"/catalog.v1.CatalogService/UpdateService":               {resource: "service", model: func() interface{} { return &ServiceModel{} }, reqID: "service.id"},
+	"/catalog.v1.CatalogService/DeleteService":               {resource: "service", model: func() interface{} { return &ServiceModel{} }, reqID: "id"},
+	"/catalog.v1.CatalogService/CreateServiceToken":          {resource: "service_token", model: func() interface{} { return &ServiceTokenModel{} }, respID: "token.id"},
+	"/catalog.v1.CatalogService/RevokeServiceToken":          {resource: "service_token", model: func() interface{} { return &ServiceTokenModel{} }, reqID: "id"},
+	"/alerting.v1.AlertingService/CreateRule":                {resource: "alert_rule", model: func() interface{} { return &AlertRuleModel{} }, respID: "rule.id"},
+	"/alerting.v1.AlertingService/UpdateRule":                {resource: "alert_rule", model: func() interface{} { return &AlertRuleModel{} }, reqID: "rule.id"},
+	"/alerting.v1.AlertingService/DeleteRule":                {resource: "alert_rule", model: func() interface{} { return &AlertRuleModel{} }, reqID: "id"},
+	"/notification.v1.NotificationService/CreateChannel":     {resource: "notification_channel", model: func() interface{} { return &NotificationChannelModel{} }, respID: "channel.id"},
+	"/notification.v1.NotificationService/DeleteChannel":     {resource: "notification_channel", model: func() interface{} { return &NotificationChannelModel{} }, reqID: "id"},
+	"/slo.v1.SLOService/CreateSLO":                           {resource: "slo", model: func() interface{} { return &SLOModel{} }, respID: "slo.id"},
+	"/incident.v1.IncidentService/CreateIncident":            {resource: "incident", model: func() interface{} { return &IncidentModel{} }, respID: "incident.id"},
+	"/incident.v1.IncidentService/UpdateIncident":            {resource: "incident", model: func() interface{} { return &IncidentModel{} }, reqID: "id"},
+	"/maintenance.v1.MaintenanceService/ScheduleMaintenance": {resource: "maintenance_window", model: func() interface{} { return &MaintenanceWindowModel{} }, respID: "window.id"},
+	"/maintenance.v1.MaintenanceService/CancelMaintenance":   {resource: "maintenance_window", model: func() interface{} { return &MaintenanceWindowModel{} }, reqID: "id"},
+}
+
+// migrateAuditEvents makes audit_events append-only for every client of
+// the database, not just this backend.
+func migrateAuditEvents(db *gorm.DB) error {
+	stmts := []string{
+		`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END
+$$ LANGUAGE plpgsql`,
+		`CREATE OR REPLACE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only()`,
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AuditLog appends hash-chained events to audit_events.
+type AuditLog struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	anonymous map[string]time.Time // client IP and method to when last recorded
+}
+
+func NewAuditLog(db *gorm.DB) *AuditLog {
+	return &AuditLog{db: db, anonymous: map[string]time.Time{}}
+}
+
+// sampleAnonymous reports whether a call rejected for lack of credentials
+// should be recorded: the first per key in anonymousAuditInterval is.
+func (l *AuditLog) sampleAnonymous(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.anonymous[key]; ok && now.Sub(last) < anonymousAuditInterval {
+		return false
+	}
+	if len(l.anonymous) >= maxAnonymousAuditKeys {
+		for k, last := range l.anonymous {
+			if now.Sub(last) >= anonymousAuditInterval {
+				delete(l.anonymous, k)
+			}
+		}
+		if len(l.anonymous) >= maxAnonymousAuditKeys {
+			return false
+		}
+	}
+	l.anonymous[key] = now
+	return true
+}
+
+// auditHash covers every field of an event except seq and its own hash.
+func auditHash(ev *AuditEventModel) string {
+	b, _ := json.Marshal(struct {
+		PrevHash   string             `json:"prev_hash"`
+		At         int64              `json:"at"`
+		Actor      string             `json:"actor"`
+		Action     string             `json:"action"`
+		Resource   string             `json:"resource"`
+		ResourceID string             `json:"resource_id"`
+		Outcome    string             `json:"outcome"`
+		SourceIP   string             `json:"source_ip"`
+		RequestID  string             `json:"request_id"`
+		Changes    []AuditFieldChange `json:"changes"`
+	}{ev.PrevHash, ev.At, ev.Actor, ev.Action, ev.Resource, ev.ResourceID, ev.Outcome, ev.SourceIP, ev.RequestID, ev.Changes})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Append chains ev to the latest event and stores it.
+func (l *AuditLog) Append(ctx context.Context, ev *AuditEventModel) error {
+	if ev.At == 0 {
+		ev.At = time.Now().UnixMilli()
+	}
+	return l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditLockKey).Error; err != nil {
+			return err
+		}
+		return appendAuditLocked(tx, ev)
+	})
+}
+
+// appendAuditLocked chains ev to the latest event and stores it. tx must
+// hold the audit lock, so that no other event takes the same place.
+func appendAuditLocked(tx *gorm.DB, ev *AuditEventModel) error {
+	var prev []string
+	if err := tx.Model(&AuditEventModel{}).Order("seq desc").Limit(1).Pluck("hash", &prev).Error; err != nil {
+		return err
+	}
+	ev.PrevHash = ""
+	if len(prev) > 0 {
+		ev.PrevHash = prev[0]
+	}
+	ev.Hash = auditHash(ev)
+	return tx.Create(ev).Error
+}
+
+// record appends an event built from the request context. The audited
+// action has already happened, so a failure is only logged and counted.
+func (l *AuditLog) record(ctx context.Context, ev AuditEventModel) {
+	if ev.Actor = PrincipalFromContext(ctx); ev.Actor == "" {
+		ev.Actor = "anonymous"
+	}
+	ev.RequestID = RequestIDFromContext(ctx)
+	if err := l.Append(context.WithoutCancel(ctx), &ev); err != nil {
+		auditFailures.Inc()
+		Logger(ctx).Error("failed to write audit event", "action", ev.Action, "resource_id", ev.ResourceID, "error", err)
+	}
+}
+
+// snapshot loads a row as column values, or nil when it does not exist.
+func (l *AuditLog) snapshot(ctx context.Context, model interface{}, id string) map[string]interface{} {
+	var rows []map[string]interface{}
+	if err := l.db.WithContext(ctx).Model(model).Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil || len(rows) == 0 {
+		return nil
+	}
+	return rows[0]
+}
+
+// diffSnapshots lists the columns that differ, sorted by name.
+func diffSnapshots(before, after map[string]interface{}) []AuditFieldChange {
+	fields := map[string]bool{}
+	for k := range before {
+		fields[k] = true
+	}
+	for k := range after {
+		fields[k] = true
+	}
+	var changes []AuditFieldChange
+	for field := range fields {
+		b, inBefore := before[field]
+		a, inAfter := after[field]
+		if inBefore && inAfter && reflect.DeepEqual(b, a) {
+			continue
+		}
+		c := AuditFieldChange{Field: field}
+		if inBefore {
+			c.Before = auditValue(field, b)
+		}
+		if inAfter {
+			c.After = auditValue(field, a)
+		}
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func auditValue(field string, v interface{}) string {
+	if auditRedacted[field] {
+		return `"[redacted]"`
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+// protoString reads a string field such as "service.id" from a message.
+func protoString(m interface{}, path string) string {
+	pm, ok := m.(proto.Message)
+	if !ok || path == "" {
+		return ""
+	}
+	r := pm.ProtoReflect()
+	parts := strings.Split(path, ".")
+	for i, name := range parts {
+		fd := r.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return ""
+		}
+		if i == len(parts)-1 {
+			return r.Get(fd).String()
+		}
+		if fd.Message() == nil || !r.Has(fd) {
+			return ""
+		}
+		r = r.Get(fd).Message()
+	}
+	return ""
+}
+
+// AuditInterceptor records every call of a mutating RPC, including
+// rejected ones, with the changed columns of the affected row. It must run
+// before the JWT interceptor so unauthenticated attempts are recorded too,
+// and after the per-IP rate limit. Calls rejected for lack of credentials
+// are sampled per client IP and method rather than each written.
+func AuditInterceptor(log *AuditLog) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		spec, ok := auditedMethods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		id := protoString(req, spec.reqID)
+		var before map[string]interface{}
+		if id != "" {
+			before = log.snapshot(ctx, spec.model(), id)
+		}
+		resp, err := handler(ctx, req)
+
+		ip := grpcClientIP(ctx)
+		if status.Code(err) == codes.Unauthenticated && PrincipalFromContext(ctx) == "" &&
+			!log.sampleAnonymous(ip+" "+info.FullMethod, time.Now()) {
+			auditAnonymousSkipped.Inc()
+			return resp, err
+		}
+		ev := AuditEventModel{
+			Action:   info.FullMethod,
+			Resource: spec.resource,
+			Outcome:  status.Code(err).String(),
+			SourceIP: ip,
+		}
+		if err == nil {
+			if id == "" {
+				id = protoString(resp, spec.respID)
+			}
+			ev.Changes = diffSnapshots(before, log.snapshot(ctx, spec.model(), id))
+		}
+		ev.ResourceID = id
+		log.record(ctx, ev)
+		return resp, err
+	}
+}
+
+// auditTarget lets HTTP handlers describe what a request acted on.
+type auditTarget struct {
+	resource string
+	id       string
+	changes  []AuditFieldChange
+}
+
+type auditTargetKey struct{}
+
+// SetAuditResource names the resource an audited HTTP request acts on,
+// e.g. the user a login is for.
+func SetAuditResource(ctx context.Context, resource, id string) {
+	if t, ok := ctx.Value(auditTargetKey{}).(*auditTarget); ok {
+		t.resource, t.id = resource, id
+	}
+}
+
+// AuditChange records one changed value of an audited HTTP request.
+func AuditChange(ctx context.Context, field string, before, after interface{}) {
+	if t, ok := ctx.Value(auditTargetKey{}).(*auditTarget); ok {
+		t.changes = append(t.changes, AuditFieldChange{Field: field, Before: auditValue(field, before), After: auditValue(field, after)})
+	}
+}
+
+// AuditMiddleware is chi middleware that records every HTTP request that
+// is not a read (logins and admin changes). It must run inside
+// RequestLogger, which provides the request ID and principal. Gateway
+// requests are recorded by AuditInterceptor as the RPCs they call.
+func AuditMiddleware(log *AuditLog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if isGatewayPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			target := &auditTarget{}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), auditTargetKey{}, target)))
+
+			route := r.URL.Path
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			log.record(r.Context(), AuditEventModel{
+				Action:     r.Method + " " + route,
+				Resource:   target.resource,
+				ResourceID: target.id,
+				Outcome:    strconv.Itoa(ww.Status()),
+				SourceIP:   HTTPClientIP(r),
+				Changes:    target.changes,
+			})
+		})
+	}
+}
+
+// VerifyAuditChain recomputes every hash in seq order. It returns the
+// number of events checked and the latest hash, which can be kept outside
+// the database to also detect removal of the newest events.
+func VerifyAuditChain(ctx context.Context, db *gorm.DB) (int64, string, error) {
+	var (
+		checked int64
+		prev    string
+		lastSeq int64
+	)
+	for {
+		var batch []AuditEventModel
+		if err := db.WithContext(ctx).Where("seq > ?", lastSeq).Order("seq").Limit(1000).Find(&batch).Error; err != nil {
+			return checked, prev, err
+		}
+		for i := range batch {
+			ev := &batch[i]
+			if ev.PrevHash != prev {
+				return checked, prev, fmt.Errorf("event %d: prev_hash does not match the event before it", ev.Seq)
+			}
+			if auditHash(ev) != ev.Hash {
+				return checked, prev, fmt.Errorf("event %d: hash does not match its contents", ev.Seq)
+			}
+			prev, lastSeq = ev.Hash, ev.Seq
+			checked++
+		}
+		if len(batch) < 1000 {
+			return checked, prev, nil
+		}
+	}
+}
+
+type AuditServerImpl struct {
+	auditpb.UnimplementedAuditServiceServer
+	db *gorm.DB
+}
+
+func NewAuditServer(db *gorm.DB) *AuditServerImpl {
+	return &AuditServerImpl{db: db}
+}
+
+// ListAuditEvents pages through audit events, newest first
+func (s *AuditServerImpl) ListAuditEvents(ctx context.Context, req *auditpb.ListAuditEventsRequest) (*auditpb.ListAuditEventsResponse, error) {
+	size := int(req.PageSize)
+	if size <= 0 {
+		size = defaultAuditPageSize
+	}
+	size = min(size, maxAuditPageSize)
+
+	q := s.db.WithContext(ctx).Model(&AuditEventModel{})
+	for column, v := range map[string]string{"actor": req.Actor, "action": req.Action, "resource": req.Resource, "resource_id": req.ResourceId} {
+		if v != "" {
+			q = q.Where(column+" = ?", v)
+		}
+	}
+	if req.FromMs > 0 {
+		q = q.Where("at >= ?", req.FromMs)
+	}
+	if req.ToMs > 0 {
+		q = q.Where("at < ?", req.ToMs)
+	}
+	if req.PageToken != "" {
+		before, err := strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		q = q.Where("seq < ?", before)
+	}
+
+	var events []AuditEventModel
+	if err := q.Order("seq desc").Limit(size + 1).Find(&events).Error; err != nil {
+		return nil, status.Error(codes.Unavailable, "failed to list audit events")
+	}
+	resp := &auditpb.ListAuditEventsResponse{}
+	if len(events) > size {
+		events = events[:size]
+		resp.NextPageToken = strconv.FormatInt(events[size-1].Seq, 10)
+	}
+	for _, ev := range events {
+		p := &auditpb.AuditEvent{
+			Seq:        ev.Seq,
+			AtMs:       ev.At,
+			Actor:      ev.Actor,
+			Action:     ev.Action,
+			Resource:   ev.Resource,
+			ResourceId: ev.ResourceID,
+			Outcome:    ev.Outcome,
+			SourceIp:   ev.SourceIP,
+			RequestId:  ev.RequestID,
+			PrevHash:   ev.PrevHash,
+			Hash:       ev.Hash,
+		}
+		for _, c := range ev.Changes {
+			p.Changes = append(p.Changes, &auditpb.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+		}
+		resp.Events = append(resp.Events, p)
+	}
+	return resp, nil
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func TestSampleAnonymous(t *testing.T) {
+	l := NewAuditLog(nil)
+	start := time.Now()
+	steps := []struct {
+		key   string
+		after time.Duration
+		want  bool
+	}{
+		{"1.1.1.1 /m", 0, true},
+		{"1.1.1.1 /m", time.Second, false},
+		{"1.1.1.1 /other", time.Second, true},
+		{"2.2.2.2 /m", time.Second, true},
+		{"1.1.1.1 /m", anonymousAuditInterval - time.Millisecond, false},
+		{"1.1.1.1 /m", anonymousAuditInterval, true},
+		{"1.1.1.1 /m", anonymousAuditInterval + time.Second, false},
+	}
+	for i, s := range steps {
+		if got := l.sampleAnonymous(s.key, start.Add(s.after)); got != s.want {
+			t.Errorf("step %d: sampleAnonymous(%q) after %v = %v, want %v", i, s.key, s.after, got, s.want)
+		}
+	}
+
+	// Once full, expired keys make room and fresh ones are not evicted
+	full := NewAuditLog(nil)
+	for i := range maxAnonymousAuditKeys {
+		full.anonymous[string(rune(i))] = start
+	}
+	if full.sampleAnonymous("new", start.Add(time.Second)) {
+		t.Error("sampled a new key with every key fresh")
+	}
+	if !full.sampleAnonymous("new", start.Add(anonymousAuditInterval)) || len(full.anonymous) != 1 {
+		t.Errorf("expired keys were not pruned: %d left", len(full.anonymous))
+	}
+}
+
+// TestAuditInterceptorAnonymous checks that repeated calls without
+// credentials are counted instead of each being written.
+func TestAuditInterceptorAnonymous(t *testing.T) {
+	client, _ := newTestRedis(t)
+	l := NewAuditLog(newTestDB(t, &AuditEventModel{}))
+	jwt := JWTInterceptor(testKey, NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client)))
+	chain := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return AuditInterceptor(l)(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return jwt(ctx, req, info, handler)
+		})
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/catalog.v1.CatalogService/DeleteService"}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+
+	skipped := auditAnonymousSkipped.v.Load()
+	for i := range 5 {
+		ctx := contextWithRequestInfo(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "1.1.1.1")), &requestInfo{})
+		if _, err := chain(ctx, nil, info, ok); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if n := auditAnonymousSkipped.v.Load() - skipped; n != 4 {
+		t.Errorf("%d anonymous calls counted instead of written, want 4", n)
+	}
+
+	// Calls with a token are always written
+	skipped = auditAnonymousSkipped.v.Load()
+	for range 3 {
+		md := metadata.Pairs("x-forwarded-for", "1.1.1.1", "authorization", "Bearer "+testAccessToken(t))
+		chain(contextWithRequestInfo(metadata.NewIncomingContext(context.Background(), md), &requestInfo{}), nil, info, ok)
+	}
+	if n := auditAnonymousSkipped.v.Load() - skipped; n != 0 {
+		t.Errorf("%d authenticated calls were not written", n)
+	}
+}
+
+// TestVerifyAuditChain writes a short chain, edits it the way someone with
+// database access might, and checks that the edit is found at the right row.
+func TestVerifyAuditChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(db *gorm.DB) error
+		checked int64
+		wantErr string
+	}{
+		{name: "untouched", checked: 5},
+		{name: "edited row", tamper: func(db *gorm.DB) error {
+			return db.Model(&AuditEventModel{}).Where("seq = ?", 3).Update("actor", "someone-else").Error
+		}, checked: 2, wantErr: "event 3: hash does not match"},
+		{name: "edited row with its hash recomputed", tamper: func(db *gorm.DB) error {
+			var ev AuditEventModel
+			if err := db.First(&ev, "seq = ?", 3).Error; err != nil {
+				return err
+			}
+			ev.Actor = "someone-else"
+			return db.Model(&ev).Updates(map[string]interface{}{"actor": ev.Actor, "hash": auditHash(&ev)}).Error
+		}, checked: 3, wantErr: "event 4: prev_hash does not match"},
+		{name: "deleted row", tamper: func(db *gorm.DB) error {
+			return db.Delete(&AuditEventModel{}, "seq = ?", 3).Error
+		}, checked: 2, wantErr: "event 4: prev_hash does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &AuditEventModel{})
+			hashes := []string{""}
+			for i := range 5 {
+				ev := &AuditEventModel{At: int64(i + 1), Actor: "alice", Action: "UpdateService", Resource: "service", ResourceID: "api", Outcome: "OK"}
+				if err := appendAuditLocked(db, ev); err != nil {
+					t.Fatal(err)
+				}
+				hashes = append(hashes, ev.Hash)
+			}
+			if tt.tamper != nil {
+				if err := tt.tamper(db); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			checked, last, err := VerifyAuditChain(context.Background(), db)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error %v, want %q", err, tt.wantErr)
+			}
+			if checked != tt.checked {
+				t.Errorf("checked %d events, want %d", checked, tt.checked)
+			}
+			if tt.wantErr == "" && last != hashes[5] {
+				t.Errorf("last hash %q, want the fifth event's", last)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package internal
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc"
@@ -15,74 +19,292 @@ import (
 // It must be the same key used in the loginHandler.
 var jwtKey = []byte("my-super-secret-key")
 
-func JWTInterceptor(secretKey []byte) grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "no metadata provided")
-		}
+// serviceTokenMethods are called by instrumented services rather than users.
+// Calls with a service token (see authenticateServiceToken) skip the JWT
+// check; otherwise only service account tokens, with health:write, may
+// call them.
+var serviceTokenMethods = map[string]bool{
+	"/health.v1.HealthService/ReportHealth":      true,
+	"/health.v1.HealthService/ReportHealthBatch": true,
+}
 
-		authHeaders := md.Get("authorization")
-		if len(authHeaders) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "authorization token not provided")
-		}
+const (
+	roleAdmin    = "admin"
+	roleOperator = "operator"
+)
 
-		tokenString := authHeaders[0]
-		if !strings.HasPrefix(tokenString, "Bearer ") {
-			return nil, status.Error(codes.Unauthenticated, "authorization token must be Bearer token")
+// methodRoles are the roles needed for a gRPC method, or for every method
+// of a service ("/pkg.v1.Service/"). Other mutating methods need operator
+// and reads need no role, so users without roles can only look. Admins may
+// call everything.
+var methodRoles = map[string]string{
+	"/audit.v1.AuditService/":                       roleAdmin,
+	"/catalog.v1.CatalogService/DeleteService":      roleAdmin,
+	"/catalog.v1.CatalogService/CreateServiceToken": roleAdmin,
+	"/catalog.v1.CatalogService/ListServiceTokens":  roleAdmin,
+	"/catalog.v1.CatalogService/RevokeServiceToken": roleAdmin,
+}
+
+// requiredRole is the role a user needs to call fullMethod, or "".
+func requiredRole(fullMethod string) string {
+	if role, ok := methodRoles[fullMethod]; ok {
+		return role
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i > 0 {
+		if role, ok := methodRoles[fullMethod[:i+1]]; ok {
+			return role
 		}
+	}
+	if mutatingMethod(fullMethod) {
+		return roleOperator
+	}
+	return ""
+}
 
-		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+// checkRole lets users call only the methods their roles allow. Service
+// account tokens carry no roles; checkScope limits them instead.
+func checkRole(claims *AccessClaims, fullMethod string) error {
+	if isServiceAccountToken(claims) {
+		return checkScope(claims, fullMethod)
+	}
+	if serviceTokenMethods[fullMethod] {
+		return status.Error(codes.PermissionDenied, "requires a service token or a service account token")
+	}
+	role := requiredRole(fullMethod)
+	if role == "" || slices.Contains(claims.Roles, role) || slices.Contains(claims.Roles, roleAdmin) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "requires the %s role", role)
+}
 
-		token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return secretKey, nil
-		})
+// checkAdminSession checks the session of a call to an admin method with
+// Sessions.CheckStrict, so a revocation Redis has lost cannot be used for it.
+func checkAdminSession(ctx context.Context, sessions *Sessions, claims *AccessClaims, fullMethod string) error {
+	if requiredRole(fullMethod) != roleAdmin {
+		return nil
+	}
+	if err := sessions.CheckStrict(ctx, claims.ID); err != nil {
+		return status.Error(codes.Unauthenticated, "invalid token: "+err.Error())
+	}
+	return nil
+}
 
+func JWTInterceptor(secretKey []byte, sessions *Sessions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if serviceTokenMethods[info.FullMethod] && hasServiceToken(ctx) {
+			return handler(ctx, req)
+		}
+
+		tokenString, err := grpcAccessToken(ctx)
 		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, "invalid token: "+err.Error())
+			return nil, err
 		}
 
-		if !token.Valid {
-			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		claims, err := verifyAccessToken(ctx, secretKey, sessions, tokenString)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token: "+err.Error())
+		}
+		if err := checkRole(claims, info.FullMethod); err != nil {
+			return nil, err
+		}
+		if err := checkAdminSession(ctx, sessions, claims, info.FullMethod); err != nil {
+			return nil, err
 		}
 
-		// Token is valid, proceed with the original handler
+		// Token is valid, record who is calling and proceed with the original handler
+		ctx = withClaims(ctx, claims)
 		return handler(ctx, req)
 	}
 }
 
-func JWTStreamInterceptor(secretKey []byte) grpc.StreamServerInterceptor {
+// JWTStreamInterceptor also ends a stream when its session is revoked.
+func JWTStreamInterceptor(secretKey []byte, sessions *Sessions) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		md, ok := metadata.FromIncomingContext(ss.Context())
-		if !ok {
-			return status.Error(codes.Unauthenticated, "no metadata provided")
+		if serviceTokenMethods[info.FullMethod] && hasServiceToken(ss.Context()) {
+			return handler(srv, ss)
 		}
 
-		authHeaders := md.Get("authorization")
-		if len(authHeaders) == 0 {
-			return status.Error(codes.Unauthenticated, "authorization token not provided")
+		tokenString, err := grpcAccessToken(ss.Context())
+		if err != nil {
+			return err
 		}
 
-		tokenString := authHeaders[0]
-		if !strings.HasPrefix(tokenString, "Bearer ") {
-			return status.Error(codes.Unauthenticated, "authorization token must be Bearer token")
+		claims, err := verifyAccessToken(ss.Context(), secretKey, sessions, tokenString)
+		if err != nil {
+			return status.Error(codes.Unauthenticated, "invalid token: "+err.Error())
+		}
+		if err := checkRole(claims, info.FullMethod); err != nil {
+			return err
+		}
+		if err := checkAdminSession(ss.Context(), sessions, claims, info.FullMethod); err != nil {
+			return err
 		}
 
-		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+		// Token is valid, record who is calling and proceed with the original handler
+		ctx, stop := sessions.Watch(withClaims(ss.Context(), claims), claims.ID)
+		defer stop()
+		err = handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		if errors.Is(context.Cause(ctx), errSessionRevoked) {
+			return status.Error(codes.Unauthenticated, "session revoked")
+		}
+		return err
+	}
+}
 
-		token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return secretKey, nil
-		})
+// grpcAccessToken returns the Bearer token of the authorization metadata,
+// or else the access_token cookie, which Envoy forwards from gRPC-Web calls
+// as cookie metadata. Calls made with the cookie, reads included, must
+// repeat the csrf_token cookie in x-csrf-token, so that another site cannot
+// use it even where CORS would let that site read the response.
+func grpcAccessToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "no metadata provided")
+	}
 
-		if err != nil {
-			return status.Error(codes.Unauthenticated, "invalid token: "+err.Error())
+	if authHeaders := md.Get("authorization"); len(authHeaders) > 0 {
+		if !strings.HasPrefix(authHeaders[0], "Bearer ") {
+			return "", status.Error(codes.Unauthenticated, "authorization token must be Bearer token")
 		}
+		return strings.TrimPrefix(authHeaders[0], "Bearer "), nil
+	}
 
-		if !token.Valid {
-			return status.Error(codes.Unauthenticated, "invalid token")
-		}
+	cookies := md.Get("cookie")
+	tokenString := cookieValue(cookies, "access_token")
+	if tokenString == "" {
+		return "", status.Error(codes.Unauthenticated, "authorization token not provided")
+	}
+	var header string
+	if h := md.Get(csrfHeader); len(h) > 0 {
+		header = h[0]
+	}
+	if !csrfValid(cookieValue(cookies, csrfCookie), header) {
+		return "", status.Error(codes.PermissionDenied, "CSRF token missing or invalid")
+	}
+	return tokenString, nil
+}
+
+// RequireJWT is chi middleware that accepts a Bearer token or the
+// access_token cookie set by loginHandler; with the cookie, every request
+// needs the CSRF token too.
+func RequireJWT(secretKey []byte, sessions *Sessions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString, fromCookie := httpAccessToken(r)
+			if tokenString == "" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if fromCookie && !httpCSRFValid(r) {
+				http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+				return
+			}
+
+			claims, err := verifyAccessToken(r.Context(), secretKey, sessions, tokenString)
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
 
-		// Token is valid, proceed with the original handler
-		return handler(srv, ss)
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// RequireRole is chi middleware, behind RequireJWT, that only lets
+// principals with role through.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasRole(r.Context(), role) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// httpAccessToken returns the Bearer token of r, or its access_token
+// cookie, and whether it came from the cookie.
+func httpAccessToken(r *http.Request) (string, bool) {
+	if tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); tokenString != "" {
+		return tokenString, false
+	}
+	if cookie, err := r.Cookie("access_token"); err == nil {
+		return cookie.Value, true
+	}
+	return "", false
+}
+
+// AccessClaims are the claims of the tokens issued at login, and of the
+// service account tokens issued by /oauth/token, which carry scopes
+// instead of roles.
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+	Scope string   `json:"scope,omitempty"`
+}
+
+// parseToken validates the signature and expiry of any token we issued.
+func parseToken(secretKey []byte, tokenString string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return secretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("token is invalid")
+	}
+	return claims, nil
+}
+
+// parseAccessToken is parseToken for access tokens. Tokens for one purpose,
+// such as the MFA challenges of an unfinished login, are signed with the
+// same key but carry an audience, and are never accepted. The one audience
+// accepted is that of service account tokens.
+func parseAccessToken(secretKey []byte, tokenString string) (*AccessClaims, error) {
+	claims, err := parseToken(secretKey, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims.Audience) > 0 && !isServiceAccountToken(claims) {
+		return nil, errors.New("token is not an access token")
+	}
+	return claims, nil
+}
+
+// verifyAccessToken is parseAccessToken that also rejects tokens of
+// revoked sessions.
+func verifyAccessToken(ctx context.Context, secretKey []byte, sessions *Sessions, tokenString string) (*AccessClaims, error) {
+	claims, err := parseAccessToken(secretKey, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessions.Check(ctx, claims.ID); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// issueToken signs claims for subject that expire after ttl; id is the
+// session of an access token.
+func issueToken(secretKey []byte, id, subject string, roles []string, audience string, ttl time.Duration) (string, time.Time, error) {
+	expires := time.Now().Add(ttl)
+	claims := &AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+		Roles: roles,
+	}
+	if audience != "" {
+		claims.Audience = jwt.ClaimStrings{audience}
 	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secretKey)
+	return tokenString, expires, err
 }
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"/catalog.v1.CatalogService/ListServices", ""},
+		{"/health.v1.HealthService/GetHealthHistory", ""},
+		{"/incident.v1.IncidentService/WatchIncidents", ""},
+		{"/catalog.v1.CatalogService/CreateService", roleOperator},
+		{"/alerting.v1.AlertingService/DeleteRule", roleOperator},
+		{"/slo.v1.SLOService/CreateSLO", roleOperator},
+		{"/maintenance.v1.MaintenanceService/CancelMaintenance", roleOperator},
+		{"/catalog.v1.CatalogService/DeleteService", roleAdmin},
+		{"/catalog.v1.CatalogService/CreateServiceToken", roleAdmin},
+		{"/catalog.v1.CatalogService/ListServiceTokens", roleAdmin},
+		{"/audit.v1.AuditService/ListAuditEvents", roleAdmin},
+	}
+	for _, tt := range tests {
+		if got := requiredRole(tt.method); got != tt.want {
+			t.Errorf("requiredRole(%q) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestJWTInterceptorRoles(t *testing.T) {
+	client, _ := newTestRedis(t)
+	sessions := NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client))
+	token := func(roles ...string) string {
+		s, _, err := issueToken(testKey, "", "alice", roles, "", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	serviceAccount := func(scope string) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &AccessClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   serviceAccountSubjectPrefix + "deploy",
+				Audience:  jwt.ClaimStrings{serviceAccountAudience},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			Scope: scope,
+		}).SignedString(testKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		want   codes.Code
+	}{
+		{"no role reads", token(), "/catalog.v1.CatalogService/ListServices", codes.OK},
+		{"no role cannot write", token(), "/catalog.v1.CatalogService/CreateService", codes.PermissionDenied},
+		{"unmapped role cannot write", token("viewer"), "/alerting.v1.AlertingService/CreateRule", codes.PermissionDenied},
+		{"operator writes", token("operator"), "/alerting.v1.AlertingService/CreateRule", codes.OK},
+		{"operator cannot delete services", token("operator"), "/catalog.v1.CatalogService/DeleteService", codes.PermissionDenied},
+		{"operator cannot mint tokens", token("operator"), "/catalog.v1.CatalogService/CreateServiceToken", codes.PermissionDenied},
+		{"operator cannot read audit", token("operator"), "/audit.v1.AuditService/ListAuditEvents", codes.PermissionDenied},
+		{"admin reads audit", token("admin"), "/audit.v1.AuditService/ListAuditEvents", codes.OK},
+		{"admin deletes services", token("viewer", "admin"), "/catalog.v1.CatalogService/DeleteService", codes.OK},
+		{"service account within scope", serviceAccount("catalog:write"), "/catalog.v1.CatalogService/CreateService", codes.OK},
+		{"write scope reads", serviceAccount("catalog:write"), "/catalog.v1.CatalogService/ListServices", codes.OK},
+		{"service account outside scope", serviceAccount("catalog:write"), "/slo.v1.SLOService/CreateSLO", codes.PermissionDenied},
+		{"write scope cannot delete services", serviceAccount("catalog:write"), "/catalog.v1.CatalogService/DeleteService", codes.PermissionDenied},
+		{"write scope cannot mint tokens", serviceAccount("catalog:write"), "/catalog.v1.CatalogService/CreateServiceToken", codes.PermissionDenied},
+		{"admin scope deletes services", serviceAccount("catalog:admin"), "/catalog.v1.CatalogService/DeleteService", codes.OK},
+		{"read scope cannot read audit", serviceAccount("audit:read"), "/audit.v1.AuditService/ListAuditEvents", codes.PermissionDenied},
+		{"admin scope reads audit", serviceAccount("audit:admin"), "/audit.v1.AuditService/ListAuditEvents", codes.OK},
+		{"unknown package", serviceAccount("catalog:admin"), "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", codes.PermissionDenied},
+		{"service account reports health", serviceAccount("health:write"), "/health.v1.HealthService/ReportHealthBatch", codes.OK},
+		{"report without health scope", serviceAccount("catalog:admin"), "/health.v1.HealthService/ReportHealth", codes.PermissionDenied},
+		{"user cannot report health", token("admin"), "/health.v1.HealthService/ReportHealthBatch", codes.PermissionDenied},
+		{"no token", "", "/catalog.v1.CatalogService/ListServices", codes.Unauthenticated},
+		{"report without token", "", "/health.v1.HealthService/ReportHealth", codes.Unauthenticated},
+	}
+	unary := JWTInterceptor(testKey, sessions)
+	stream := JWTStreamInterceptor(testKey, sessions)
+	for _, tt := range tests {
+		md := metadata.MD{}
+		if tt.token != "" {
+			md.Set("authorization", "Bearer "+tt.token)
+		}
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: unary call = %v, want %v", tt.name, err, tt.want)
+		}
+		err = stream(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, func(interface{}, grpc.ServerStream) error {
+			return nil
+		})
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: stream = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+// TestServiceTokenMethods checks that calls with a service token are left
+// to the handler, which checks the token.
+func TestServiceTokenMethods(t *testing.T) {
+	client, _ := newTestRedis(t)
+	unary := JWTInterceptor(testKey, NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client)))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ServiceTokenHeader, "t15s_anything"))
+	for method, want := range map[string]codes.Code{
+		"/health.v1.HealthService/ReportHealthBatch": codes.OK,
+		"/catalog.v1.CatalogService/ListServices":    codes.Unauthenticated,
+	} {
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		if status.Code(err) != want {
+			t.Errorf("%s with a service token = %v, want %v", method, err, want)
+		}
+	}
+}
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"/catalog.v1.CatalogService/ListServices", "catalog:read"},
+		{"/catalog.v1.CatalogService/CreateService", "catalog:write"},
+		{"/catalog.v1.CatalogService/DeleteService", "catalog:admin"},
+		{"/catalog.v1.CatalogService/ListServiceTokens", "catalog:admin"},
+		{"/health.v1.HealthService/WatchHealth", "health:read"},
+		{"/health.v1.HealthService/ReportHealth", "health:write"},
+		{"/audit.v1.AuditService/ListAuditEvents", "audit:admin"},
+		{"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", ""},
+	}
+	for _, tt := range tests {
+		if got := requiredScope(tt.method); got != tt.want {
+			t.Errorf("requiredScope(%q) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+	// Every method kept to admins needs an admin scope
+	for method, role := range methodRoles {
+		if role == roleAdmin && !strings.HasSuffix(requiredScope(method), ":admin") {
+			t.Errorf("%s is kept to admins but needs %q", method, requiredScope(method))
+		}
+	}
+}
+
+func TestScopeGranted(t *testing.T) {
+	tests := []struct {
+		granted []string
+		scope   string
+		want    bool
+	}{
+		{[]string{"catalog:read"}, "catalog:read", true},
+		{[]string{"catalog:write"}, "catalog:read", true},
+		{[]string{"catalog:admin"}, "catalog:write", true},
+		{[]string{"catalog:read"}, "catalog:write", false},
+		{[]string{"catalog:write"}, "catalog:admin", false},
+		{[]string{"catalog:admin"}, "health:read", false},
+		{[]string{"catalog:admin"}, "catalog:owner", false},
+		{nil, "", false},
+	}
+	for _, tt := range tests {
+		if got := scopeGranted(tt.granted, tt.scope); got != tt.want {
+			t.Errorf("scopeGranted(%q, %q) = %v, want %v", tt.granted, tt.scope, got, tt.want)
+		}
+	}
+	if _, err := parseScopes([]string{"audit:admin", "health:write"}); err != nil {
+		t.Errorf("parseScopes: %v", err)
+	}
+	if _, err := parseScopes([]string{"audit:owner"}); err == nil {
+		t.Error("parseScopes accepted an unknown access")
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream that only has a context.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context    { return s.ctx }
+func (s *fakeServerStream) SetHeader(metadata.MD) error { return nil }
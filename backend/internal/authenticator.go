@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+// Authenticator checks a username and password and returns the user they
+// belong to. A wrong username or password is ErrInvalidCredentials; any
+// other error means the authenticator could not decide.
+type Authenticator interface {
+	Authenticate(ctx context.Context, username, password string) (*UserModel, error)
+}
+
+// Authenticators tries each authenticator in turn, e.g. a directory and
+// then the local user store, and accepts the first that knows the user.
+// When none accepts and one of them failed, that error is returned rather
+// than ErrInvalidCredentials, so an outage does not count as a failed login.
+type Authenticators []Authenticator
+
+func (as Authenticators) Authenticate(ctx context.Context, username, password string) (*UserModel, error) {
+	var failure error
+	for _, a := range as {
+		u, err := a.Authenticate(ctx, username, password)
+		if err == nil {
+			return u, nil
+		}
+		if !errors.Is(err, ErrInvalidCredentials) {
+			Logger(ctx).Warn("authenticator failed, trying the next", "error", err)
+			failure = err
+		}
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	return nil, ErrInvalidCredentials
+}
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCircuitOpen is returned instead of calling a dependency whose breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops calls to a dependency after threshold consecutive
+// failures, then lets a single probe through every cooldown until one
+// succeeds.
+type CircuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	cb := &CircuitBreaker{name: name, threshold: threshold, cooldown: cooldown}
+	RegisterGaugeFunc("team15_circuit_breaker_open", "Whether the circuit breaker of a dependency is open (1) or not (0).",
+		map[string]string{"dependency": name}, func() float64 {
+			if cb.State() == BreakerClosed {
+				return 0
+			}
+			return 1
+		})
+	return cb
+}
+
+// Allow reports whether a call may proceed.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case BreakerOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = BreakerHalfOpen
+		cb.probing = true
+		return true
+	case BreakerHalfOpen:
+		// Only one probe at a time
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record reports the outcome of a call that Allow let through.
+func (cb *CircuitBreaker) Record(failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	prev := cb.state
+	cb.probing = false
+	if !failed {
+		cb.failures = 0
+		cb.state = BreakerClosed
+	} else {
+		cb.failures++
+		if cb.state == BreakerHalfOpen || cb.failures >= cb.threshold {
+			cb.state = BreakerOpen
+			cb.openedAt = time.Now()
+		}
+	}
+	if cb.state != prev {
+		Logger(context.Background()).Warn("circuit breaker state changed",
+			"dependency", cb.name, "from", prev.String(), "to", cb.state.String())
+	}
+}
+
+func (cb *CircuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// RedisBreakerHook is a go-redis hook that routes every command through a
+// CircuitBreaker, so callers fail fast (and fall back to Postgres) while
+// Redis is down.
+type RedisBreakerHook struct {
+	Breaker *CircuitBreaker
+}
+
+func (h RedisBreakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h RedisBreakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !h.Breaker.Allow() {
+			cmd.SetErr(ErrCircuitOpen)
+			return ErrCircuitOpen
+		}
+		err := next(ctx, cmd)
+		h.Breaker.Record(isRedisOutage(err))
+		return err
+	}
+}
+
+func (h RedisBreakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !h.Breaker.Allow() {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrCircuitOpen)
+			}
+			return ErrCircuitOpen
+		}
+		err := next(ctx, cmds)
+		h.Breaker.Record(isRedisOutage(err))
+		return err
+	}
+}
+
+// isRedisOutage distinguishes connectivity problems from ordinary replies
+// such as a missing key or a command error.
+func isRedisOutage(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return false
+	}
+	var replyErr redis.Error
+	return !errors.As(err, &replyErr)
+}
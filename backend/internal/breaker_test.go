@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	cb := NewCircuitBreaker("test", 2, time.Minute)
+	// cool moves the breaker's clock past the cooldown
+	cool := func() { cb.openedAt = cb.openedAt.Add(-time.Minute) }
+
+	steps := []struct {
+		name   string
+		before func()
+		failed bool // outcome recorded when the call is allowed
+		allow  bool
+		state  BreakerState
+	}{
+		{name: "closed call fails", failed: true, allow: true, state: BreakerClosed},
+		{name: "success resets the count", allow: true, state: BreakerClosed},
+		{name: "first failure", failed: true, allow: true, state: BreakerClosed},
+		{name: "threshold opens", failed: true, allow: true, state: BreakerOpen},
+		{name: "open refuses", allow: false, state: BreakerOpen},
+		{name: "probe after cooldown fails", before: cool, failed: true, allow: true, state: BreakerOpen},
+		{name: "failed probe restarts cooldown", allow: false, state: BreakerOpen},
+		{name: "probe after cooldown succeeds", before: cool, allow: true, state: BreakerClosed},
+		{name: "closed again", allow: true, state: BreakerClosed},
+	}
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		allowed := cb.Allow()
+		if allowed != step.allow {
+			t.Fatalf("%s: Allow() = %v, want %v", step.name, allowed, step.allow)
+		}
+		if allowed {
+			cb.Record(step.failed)
+		}
+		if got := cb.State(); got != step.state {
+			t.Fatalf("%s: state %v, want %v", step.name, got, step.state)
+		}
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	cb := NewCircuitBreaker("test", 1, time.Minute)
+	cb.Allow()
+	cb.Record(true)
+	cb.openedAt = cb.openedAt.Add(-time.Minute)
+
+	if !cb.Allow() {
+		t.Fatal("no probe after the cooldown")
+	}
+	if cb.State() != BreakerHalfOpen {
+		t.Errorf("state %v while probing, want half-open", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("second call allowed while the probe is out")
+	}
+	cb.Record(false)
+	if !cb.Allow() || cb.State() != BreakerClosed {
+		t.Errorf("state %v after a good probe, want closed", cb.State())
+	}
+}
+
+func TestRedisBreakerHook(t *testing.T) {
+	client, mr := newTestRedis(t)
+	cb := NewCircuitBreaker("test", 2, time.Minute)
+	client.AddHook(RedisBreakerHook{Breaker: cb})
+	ctx := context.Background()
+
+	// Replies, even errors, are not outages
+	if err := client.Get(ctx, "missing").Err(); err != redis.Nil {
+		t.Fatalf("Get = %v", err)
+	}
+	mr.SetError("ERR wrong type")
+	client.Get(ctx, "k")
+	client.Get(ctx, "k")
+	mr.SetError("")
+	if cb.State() != BreakerClosed {
+		t.Fatalf("state %v after reply errors, want closed", cb.State())
+	}
+
+	mr.Close()
+	client.Get(ctx, "k")
+	client.Get(ctx, "k")
+	if cb.State() != BreakerOpen {
+		t.Fatalf("state %v with Redis down, want open", cb.State())
+	}
+	if err := client.Get(ctx, "k").Err(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Get with the breaker open = %v, want ErrCircuitOpen", err)
+	}
+	if _, err := client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Get(ctx, "k")
+		return nil
+	}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("pipeline with the breaker open = %v, want ErrCircuitOpen", err)
+	}
+}
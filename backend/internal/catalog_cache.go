@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+const (
+	// catalogCacheSchema is bumped whenever the cached JSON shape changes so
+	// that instances running different builds never read each other's entries.
+	catalogCacheSchema = 5
+
+	catalogVersionKey        = "catalog:version"
+	catalogInvalidateChannel = "catalog:invalidate"
+	catalogFreshFor          = 5 * time.Minute
+	catalogStaleFor          = 10 * time.Minute
+	catalogRefreshTimeout    = 10 * time.Second
+)
+
+// cachedServices is the value stored in Redis for the service list.
+type cachedServices struct {
+	Services  []ServiceModel `json:"services"`
+	FetchedAt time.Time      `json:"fetched_at"`
+}
+
+// CatalogCache is a read-through Redis cache for the service list.
+//
+// Keys embed a schema and a data version; writers bump the version with
+// Invalidate, which is broadcast over Redis pub/sub so every instance stops
+// reading the old key at once. Entries are served fresh for catalogFreshFor
+// and then stale for catalogStaleFor while a single background refresh runs.
+// Concurrent misses on one instance are coalesced with singleflight.
+type CatalogCache struct {
+	db      *gorm.DB
+	redis   *redis.Client
+	group   singleflight.Group
+	version atomic.Int64 // 0 means not loaded yet
+
+	// lastKnown is the most recent list seen, served when Postgres is down
+	lastKnown atomic.Pointer[[]ServiceModel]
+}
+
+var catalogFallbackServed = NewCounter("team15_catalog_fallback_served_total",
+	"Service lists answered from the in-process copy because Postgres was unavailable.", nil)
+
+func NewCatalogCache(db *gorm.DB, redisClient *redis.Client) *CatalogCache {
+	return &CatalogCache{db: db, redis: redisClient}
+}
+
+func (c *CatalogCache) key(version int64) string {
+	return fmt.Sprintf("catalog:s%d:v%d:services", catalogCacheSchema, version)
+}
+
+// currentVersion returns the data version, reading it from Redis the first
+// time. Afterwards it is kept up to date by Invalidate and Subscribe.
+func (c *CatalogCache) currentVersion(ctx context.Context) int64 {
+	if v := c.version.Load(); v != 0 {
+		return v
+	}
+	v, err := c.redis.Get(ctx, catalogVersionKey).Int64()
+	if err != nil {
+		if err != redis.Nil {
+			// Read it again once Redis is back rather than pin a guess
+			return 1
+		}
+		v = 1
+		c.redis.SetNX(ctx, catalogVersionKey, v, 0)
+	}
+	c.version.CompareAndSwap(0, v)
+	return c.version.Load()
+}
+
+// Services returns every catalog service, from cache when possible. Redis
+// errors (including an open circuit breaker) fall through to Postgres, and
+// Postgres errors fall back to the last list this instance saw.
+func (c *CatalogCache) Services(ctx context.Context) ([]ServiceModel, error) {
+	key := c.key(c.currentVersion(ctx))
+
+	if raw, err := c.redis.Get(ctx, key).Bytes(); err == nil {
+		var entry cachedServices
+		if err := json.Unmarshal(raw, &entry); err == nil {
+			if time.Since(entry.FetchedAt) > catalogFreshFor {
+				// Stale: serve it anyway and refresh in the background
+				go c.refreshInBackground(key)
+			}
+			c.lastKnown.Store(&entry.Services)
+			return entry.Services, nil
+		}
+	}
+
+	// Miss: only one caller per key goes to Postgres, and the others wait
+	// for it, so the load must not end when the first caller goes away
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), catalogRefreshTimeout)
+		defer cancel()
+		return c.load(ctx, key)
+	})
+	var res singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-ch:
+	}
+	if res.Err != nil {
+		if last := c.lastKnown.Load(); last != nil {
+			Logger(ctx).Warn("serving last-known catalog", "error", res.Err)
+			catalogFallbackServed.Inc()
+			return *last, nil
+		}
+		return nil, res.Err
+	}
+	return res.Val.([]ServiceModel), nil
+}
+
+// HasLastKnown reports whether a catalog can be served without Postgres.
+func (c *CatalogCache) HasLastKnown() bool {
+	return c.lastKnown.Load() != nil
+}
+
+func (c *CatalogCache) refreshInBackground(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogRefreshTimeout)
+	defer cancel()
+	_, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return c.load(ctx, key)
+	})
+	if err != nil {
+		Logger(ctx).Warn("background catalog refresh failed", "error", err)
+	}
+}
+
+func (c *CatalogCache) load(ctx context.Context, key string) ([]ServiceModel, error) {
+	var services []ServiceModel
+	if err := c.db.WithContext(ctx).Order("id").Find(&services).Error; err != nil {
+		return nil, err
+	}
+
+	c.lastKnown.Store(&services)
+	entry, _ := json.Marshal(cachedServices{Services: services, FetchedAt: time.Now()})
+	c.redis.Set(ctx, key, entry, catalogFreshFor+catalogStaleFor)
+	return services, nil
+}
+
+// Invalidate makes every instance stop serving the current cached list. It
+// must be called after any write to the services table.
+func (c *CatalogCache) Invalidate(ctx context.Context) error {
+	v, err := c.redis.Incr(ctx, catalogVersionKey).Result()
+	if err != nil {
+		// Keep the version: going back to 0 would re-read it from the same
+		// unavailable Redis and could land on an older key than the current one
+		return err
+	}
+	c.version.Store(v)
+	return c.redis.Publish(ctx, catalogInvalidateChannel, v).Err()
+}
+
+// Subscribe applies invalidations published by other instances until ctx is
+// done.
+func (c *CatalogCache) Subscribe(ctx context.Context) {
+	sub := c.redis.Subscribe(ctx, catalogInvalidateChannel)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+			v, err := strconv.ParseInt(msg.Payload, 10, 64)
+			if err != nil {
+				continue
+			}
+			// Versions only move forward; ignore out-of-order deliveries
+			for {
+				cur := c.version.Load()
+				if v <= cur || c.version.CompareAndSwap(cur, v) {
+					break
+				}
+			}
+		}
+	}
+}
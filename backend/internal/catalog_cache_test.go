@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"gorm.io/gorm"
+)
+
+func newTestCatalogCache(t *testing.T) (*CatalogCache, *gorm.DB, *miniredis.Miniredis) {
+	t.Helper()
+	db := newTestDB(t, &ServiceModel{})
+	client, mr := newTestRedis(t)
+	if err := db.Create(&ServiceModel{ID: "api", Name: "api"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return NewCatalogCache(db, client), db, mr
+}
+
+func serviceIDs(services []ServiceModel) []string {
+	ids := make([]string, len(services))
+	for i, s := range services {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func TestCatalogCacheInvalidate(t *testing.T) {
+	c, db, mr := newTestCatalogCache(t)
+	ctx := context.Background()
+
+	steps := []struct {
+		name string
+		do   func()
+		want []string
+	}{
+		{name: "first read loads", want: []string{"api"}},
+		{name: "write is not seen until invalidated", do: func() { db.Create(&ServiceModel{ID: "db", Name: "db"}) }, want: []string{"api"}},
+		{name: "invalidated", do: func() {
+			if err := c.Invalidate(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}, want: []string{"api", "db"}},
+		{name: "failed invalidation keeps the version", do: func() {
+			db.Create(&ServiceModel{ID: "web", Name: "web"})
+			mr.SetError("READONLY")
+			if err := c.Invalidate(ctx); err == nil {
+				t.Error("Invalidate succeeded with Redis failing")
+			}
+			c.Services(ctx)
+			mr.SetError("")
+		}, want: []string{"api", "db"}}, // not the first version's list
+	}
+	for _, step := range steps {
+		if step.do != nil {
+			step.do()
+		}
+		services, err := c.Services(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := serviceIDs(services); !slices.Equal(got, step.want) {
+			t.Errorf("%s: services %v, want %v", step.name, got, step.want)
+		}
+	}
+	if v := c.version.Load(); v != 2 {
+		t.Errorf("version %d after one successful invalidation, want 2", v)
+	}
+}
+
+// TestCatalogCacheCoalesces checks that concurrent misses share one query,
+// and that the query outlives the caller that started it.
+func TestCatalogCacheCoalesces(t *testing.T) {
+	c, db, _ := newTestCatalogCache(t)
+	var queries atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	var loadErr error
+	db.Callback().Query().Before("gorm:query").Register("test:block", func(tx *gorm.DB) {
+		if queries.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		loadErr = tx.Statement.Context.Err()
+	})
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := c.Services(first)
+		firstErr <- err
+	}()
+	<-started
+
+	var wg sync.WaitGroup
+	results := make([][]ServiceModel, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = c.Services(context.Background())
+		}()
+	}
+	cancel()
+	select {
+	case err := <-firstErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("canceled caller got %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		close(release)
+		t.Fatal("canceled caller still waiting for the load")
+	}
+	time.Sleep(20 * time.Millisecond) // let the waiters join the load
+	close(release)
+	wg.Wait()
+
+	if n := queries.Load(); n != 1 {
+		t.Errorf("%d queries for concurrent misses, want 1", n)
+	}
+	if loadErr != nil {
+		t.Errorf("load ran with a done context: %v", loadErr)
+	}
+	for i, services := range results {
+		if got := serviceIDs(services); !slices.Equal(got, []string{"api"}) {
+			t.Errorf("caller %d got %v", i, got)
+		}
+	}
+}
+
+func TestCatalogCacheRedisFailure(t *testing.T) {
+	c, db, mr := newTestCatalogCache(t)
+	ctx := context.Background()
+	if _, err := c.Services(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Redis down: read through to Postgres
+	mr.SetError("LOADING")
+	db.Create(&ServiceModel{ID: "db", Name: "db"})
+	services, err := c.Services(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := serviceIDs(services); !slices.Equal(got, []string{"api", "db"}) {
+		t.Errorf("with Redis down: %v, want the Postgres list", got)
+	}
+
+	// Postgres down too: the last list seen
+	if err := db.Migrator().DropTable(&ServiceModel{}); err != nil {
+		t.Fatal(err)
+	}
+	served := catalogFallbackServed.v.Load()
+	services, err = c.Services(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := serviceIDs(services); !slices.Equal(got, []string{"api", "db"}) {
+		t.Errorf("with both down: %v, want the last-known list", got)
+	}
+	if catalogFallbackServed.v.Load() != served+1 {
+		t.Error("fallback not counted")
+	}
+}
@@ -1,73 +1,35 @@
 package internal
 
 import (
-	"encoding/json"
-	"time"
+	"context"
+	"errors"
 
 	catalogpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1"
-	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 )
 
 type CatalogServerImpl struct {
 	catalogpb.UnimplementedCatalogServiceServer
 	db    *gorm.DB
-	redis *redis.Client
+	cache *CatalogCache
 }
 
-func NewCatalogServer(db *gorm.DB, redisClient *redis.Client) *CatalogServerImpl {
-	return &CatalogServerImpl{db: db, redis: redisClient}
+func NewCatalogServer(db *gorm.DB, cache *CatalogCache) *CatalogServerImpl {
+	return &CatalogServerImpl{db: db, cache: cache}
 }
 
-// ListServices queries the services table and streams each one with caching
+// ListServices streams every service, read through the catalog cache
 func (s *CatalogServerImpl) ListServices(req *catalogpb.ListServicesRequest, stream catalogpb.CatalogService_ListServicesServer) error {
-	ctx := stream.Context()
-	cacheKey := "services:all"
-
-	// Try to get from cache first
-	cached, err := s.redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		// Cache hit - unmarshal and stream
-		var services []ServiceModel
-		if err := json.Unmarshal([]byte(cached), &services); err == nil {
-			for _, m := range services {
-				resp := &catalogpb.ListServicesResponse{
-					Services: []*catalogpb.Service{{
-						Id:       m.ID,
-						Name:     m.Name,
-						Owner:    m.Owner,
-						Version:  m.Version,
-						ProtoUrl: m.ProtoURL,
-					}},
-				}
-				if err := stream.Send(resp); err != nil {
-					return err
-				}
-			}
-			return nil
-		}
-	}
-
-	// Cache miss - query database
-	var services []ServiceModel
-	if err := s.db.Find(&services).Error; err != nil {
+	services, err := s.cache.Services(stream.Context())
+	if err != nil {
 		return err
 	}
 
-	// Cache the result for 5 minutes
-	servicesJSON, _ := json.Marshal(services)
-	s.redis.Set(ctx, cacheKey, servicesJSON, 5*time.Minute)
-
-	// Stream the results
 	for _, m := range services {
 		resp := &catalogpb.ListServicesResponse{
-			Services: []*catalogpb.Service{{
-				Id:       m.ID,
-				Name:     m.Name,
-				Owner:    m.Owner,
-				Version:  m.Version,
-				ProtoUrl: m.ProtoURL,
-			}},
+			Services: []*catalogpb.Service{serviceToProto(m)},
 		}
 		if err := stream.Send(resp); err != nil {
 			return err
@@ -75,3 +37,114 @@ func (s *CatalogServerImpl) ListServices(req *catalogpb.ListServicesRequest, str
 	}
 	return nil
 }
+
+// CreateService adds a new service to the catalog
+func (s *CatalogServerImpl) CreateService(ctx context.Context, req *catalogpb.CreateServiceRequest) (*catalogpb.CreateServiceResponse, error) {
+	if req.Service == nil || req.Service.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "service.id is required")
+	}
+
+	m := serviceFromProto(req.Service)
+	if m.Scrape != nil {
+		if err := validateScrapeConfig(m.Scrape); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if m.Thresholds != nil {
+		if err := validateThresholds(m.Thresholds); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err := s.db.WithContext(ctx).First(&ServiceModel{}, "id = ?", m.ID).Error; err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "service %q already exists", m.ID)
+	}
+	if err := s.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx)
+	return &catalogpb.CreateServiceResponse{Service: serviceToProto(m)}, nil
+}
+
+// UpdateService replaces the fields of an existing service
+func (s *CatalogServerImpl) UpdateService(ctx context.Context, req *catalogpb.UpdateServiceRequest) (*catalogpb.UpdateServiceResponse, error) {
+	if req.Service == nil || req.Service.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "service.id is required")
+	}
+
+	m := serviceFromProto(req.Service)
+	if m.Scrape != nil {
+		if err := validateScrapeConfig(m.Scrape); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if m.Thresholds != nil {
+		if err := validateThresholds(m.Thresholds); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err := s.db.WithContext(ctx).First(&ServiceModel{}, "id = ?", m.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "service %q not found", m.ID)
+		}
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(&m).Error; err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx)
+	return &catalogpb.UpdateServiceResponse{Service: serviceToProto(m)}, nil
+}
+
+// DeleteService removes a service from the catalog
+func (s *CatalogServerImpl) DeleteService(ctx context.Context, req *catalogpb.DeleteServiceRequest) (*catalogpb.DeleteServiceResponse, error) {
+	result := s.db.WithContext(ctx).Delete(&ServiceModel{}, "id = ?", req.Id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "service %q not found", req.Id)
+	}
+	// A deleted service can no longer push health samples
+	if err := s.db.WithContext(ctx).Model(&ServiceTokenModel{}).Where("service_id = ?", req.Id).Update("revoked", true).Error; err != nil {
+		Logger(ctx).Warn("failed to revoke service tokens", "service_id", req.Id, "error", err)
+	}
+	s.invalidate(ctx)
+	return &catalogpb.DeleteServiceResponse{}, nil
+}
+
+// invalidate drops the cached service list after a write. The write has
+// already succeeded, so a cache failure is only logged; the entry will still
+// expire on its own.
+func (s *CatalogServerImpl) invalidate(ctx context.Context) {
+	if err := s.cache.Invalidate(ctx); err != nil {
+		Logger(ctx).Warn("catalog cache invalidation failed", "error", err)
+	}
+}
+
+func serviceToProto(m ServiceModel) *catalogpb.Service {
+	return &catalogpb.Service{
+		Id:         m.ID,
+		Name:       m.Name,
+		Owner:      m.Owner,
+		Version:    m.Version,
+		ProtoUrl:   m.ProtoURL,
+		Labels:     m.Labels,
+		Public:     m.Public,
+		Scrape:     scrapeConfigToProto(m.Scrape),
+		Thresholds: thresholdsToProto(m.Thresholds),
+	}
+}
+
+func serviceFromProto(p *catalogpb.Service) ServiceModel {
+	return ServiceModel{
+		ID:         p.Id,
+		Name:       p.Name,
+		Owner:      p.Owner,
+		Version:    p.Version,
+		ProtoURL:   p.ProtoUrl,
+		Labels:     p.Labels,
+		Public:     p.Public,
+		Scrape:     scrapeConfigFromProto(p.Scrape),
+		Thresholds: thresholdsFromProto(p.Thresholds),
+	}
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Attributes of the access_token and csrf_token cookies, set from
+// COOKIE_SECURE and COOKIE_SAMESITE. SameSite=None requires Secure.
+var (
+	CookieSecure   = false
+	CookieSameSite = http.SameSiteLaxMode
+)
+
+const (
+	csrfCookie = "csrf_token"
+	// csrfHeader carries the csrf_token cookie's value on requests that
+	// authenticate with the access_token cookie. A page on another site can
+	// make the browser send the cookie, but cannot read it to copy it here.
+	csrfHeader = "x-csrf-token"
+)
+
+var csrfRejected = NewCounter("team15_csrf_rejected_total",
+	"Cookie-authenticated requests rejected for a missing or wrong CSRF token.", nil)
+
+// ParseSameSite parses "lax", "strict" or "none".
+func ParseSameSite(s string) (http.SameSite, error) {
+	switch strings.ToLower(s) {
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+	return 0, fmt.Errorf("invalid SameSite %q, want lax, strict or none", s)
+}
+
+// setCSRFCookie issues a new double-submit token with a login. Unlike
+// access_token it is readable by the frontend's scripts, which copy it into
+// the x-csrf-token header.
+func setCSRFCookie(w http.ResponseWriter, expires time.Time) string {
+	token := randomToken()
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    token,
+		Expires:  expires,
+		Path:     "/",
+		SameSite: CookieSameSite,
+		Secure:   CookieSecure,
+	})
+	return token
+}
+
+// csrfValid compares the csrf_token cookie with the header in constant time.
+func csrfValid(cookie, header string) bool {
+	if cookie == "" || header == "" {
+		csrfRejected.Inc()
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+		csrfRejected.Inc()
+		return false
+	}
+	return true
+}
+
+// cookieValue reads one cookie from raw Cookie header values.
+func cookieValue(headers []string, name string) string {
+	r := http.Request{Header: http.Header{"Cookie": headers}}
+	if c, err := r.Cookie(name); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// mutatingMethod reports whether a gRPC method changes state, and so needs
+// the operator role or a write scope. Reads are named List, Get or Watch
+// throughout our protos.
+func mutatingMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, read := range []string{"List", "Get", "Watch"} {
+		if strings.HasPrefix(name, read) {
+			return false
+		}
+	}
+	return true
+}
+
+// httpCSRFValid checks the CSRF token of a cookie-authenticated HTTP
+// request. Reads need it too: with SameSite=None and credentialed CORS
+// another site could otherwise read what the cookie gives access to.
+func httpCSRFValid(r *http.Request) bool {
+	var cookie string
+	if c, err := r.Cookie(csrfCookie); err == nil {
+		cookie = c.Value
+	}
+	return csrfValid(cookie, r.Header.Get(csrfHeader))
+}
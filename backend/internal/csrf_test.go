@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func testAccessToken(t *testing.T) string {
+	t.Helper()
+	token, _, err := issueToken(testKey, "", "alice", []string{roleAdmin}, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestGRPCCSRF(t *testing.T) {
+	token := testAccessToken(t)
+	const (
+		read  = "/catalog.v1.CatalogService/ListServices"
+		write = "/catalog.v1.CatalogService/CreateService"
+	)
+	cookies := "access_token=" + token + "; csrf_token=csrf-1"
+	tests := []struct {
+		name   string
+		md     metadata.MD
+		method string
+		want   codes.Code
+	}{
+		{"bearer write", metadata.Pairs("authorization", "Bearer "+token), write, codes.OK},
+		{"bearer wins over the cookie", metadata.Pairs("authorization", "Bearer "+token, "cookie", "access_token=other"), write, codes.OK},
+		{"not bearer", metadata.Pairs("authorization", "Basic "+token), read, codes.Unauthenticated},
+		{"cookie read with token", metadata.Pairs("cookie", cookies, csrfHeader, "csrf-1"), read, codes.OK},
+		{"cookie read without token", metadata.Pairs("cookie", "access_token="+token), read, codes.PermissionDenied},
+		{"cookie write with token", metadata.Pairs("cookie", cookies, csrfHeader, "csrf-1"), write, codes.OK},
+		{"cookie write without header", metadata.Pairs("cookie", cookies), write, codes.PermissionDenied},
+		{"cookie write without csrf cookie", metadata.Pairs("cookie", "access_token="+token, csrfHeader, "csrf-1"), write, codes.PermissionDenied},
+		{"cookie write with wrong token", metadata.Pairs("cookie", cookies, csrfHeader, "csrf-2"), write, codes.PermissionDenied},
+		{"cookie write with empty tokens", metadata.Pairs("cookie", "access_token="+token+"; csrf_token=", csrfHeader, ""), write, codes.PermissionDenied},
+		{"no credentials", metadata.MD{}, read, codes.Unauthenticated},
+	}
+	client, _ := newTestRedis(t)
+	interceptor := JWTInterceptor(testKey, NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client)))
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestHTTPCSRF(t *testing.T) {
+	token := testAccessToken(t)
+	tests := []struct {
+		name   string
+		method string
+		bearer bool
+		cookie bool
+		csrf   string // csrf_token cookie
+		header string // x-csrf-token header
+		want   int
+	}{
+		{name: "bearer post", method: http.MethodPost, bearer: true, want: http.StatusOK},
+		{name: "bearer with cookie post", method: http.MethodPost, bearer: true, cookie: true, want: http.StatusOK},
+		{name: "cookie get with token", method: http.MethodGet, cookie: true, csrf: "csrf-1", header: "csrf-1", want: http.StatusOK},
+		{name: "cookie get without token", method: http.MethodGet, cookie: true, want: http.StatusForbidden},
+		{name: "cookie post with token", method: http.MethodPost, cookie: true, csrf: "csrf-1", header: "csrf-1", want: http.StatusOK},
+		{name: "cookie delete with token", method: http.MethodDelete, cookie: true, csrf: "csrf-1", header: "csrf-1", want: http.StatusOK},
+		{name: "cookie post without header", method: http.MethodPost, cookie: true, csrf: "csrf-1", want: http.StatusForbidden},
+		{name: "cookie post without csrf cookie", method: http.MethodPost, cookie: true, header: "csrf-1", want: http.StatusForbidden},
+		{name: "cookie put with wrong token", method: http.MethodPut, cookie: true, csrf: "csrf-1", header: "csrf-2", want: http.StatusForbidden},
+		{name: "no credentials", method: http.MethodGet, want: http.StatusUnauthorized},
+	}
+	client, _ := newTestRedis(t)
+	handler := RequireJWT(testKey, NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client)))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if PrincipalFromContext(r.Context()) != "alice" {
+				t.Errorf("principal %q", PrincipalFromContext(r.Context()))
+			}
+		}))
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/me", nil)
+		if tt.bearer {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if tt.cookie {
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+		}
+		if tt.csrf != "" {
+			req.AddCookie(&http.Cookie{Name: csrfCookie, Value: tt.csrf})
+		}
+		if tt.header != "" {
+			req.Header.Set(csrfHeader, tt.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
+
+// TestLoginCSRFCookie checks that a login hands out a CSRF token that the
+// frontend can read, and that it is what cookie requests must echo.
+func TestLoginCSRFCookie(t *testing.T) {
+	accounts, _ := newTestAccounts(t, nil)
+	rec := httptest.NewRecorder()
+	token, csrf, err := accounts.setAccessToken(rec, httptest.NewRequest(http.MethodPost, "/login", nil), &UserModel{Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookies := map[string]*http.Cookie{}
+	for _, c := range rec.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	if c := cookies["access_token"]; c == nil || c.Value != token || !c.HttpOnly {
+		t.Errorf("access_token cookie = %+v", c)
+	}
+	if c := cookies[csrfCookie]; c == nil || c.Value != csrf || c.HttpOnly || csrf == "" {
+		t.Errorf("csrf_token cookie = %+v", c)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/sessions/revoke", nil)
+	req.AddCookie(cookies["access_token"])
+	req.AddCookie(cookies[csrfCookie])
+	req.Header.Set(csrfHeader, csrf)
+	rec = httptest.NewRecorder()
+	RequireJWT(testKey, accounts.sessions)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("request with the login's CSRF token = %d", rec.Code)
+	}
+}
+
+func TestParseSameSite(t *testing.T) {
+	tests := []struct {
+		in   string
+		want http.SameSite
+		ok   bool
+	}{
+		{"lax", http.SameSiteLaxMode, true},
+		{"Strict", http.SameSiteStrictMode, true},
+		{"none", http.SameSiteNoneMode, true},
+		{"", 0, false},
+		{"off", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseSameSite(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseSameSite(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens a private in-memory SQLite database with the tables of
+// models. Postgres-only statements (advisory locks, partitions) are not
+// available, so tests call the code below them.
+func newTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	dsn := "file:" + strings.ReplaceAll(t.Name(), "/", "_") + "?mode=memory&cache=shared"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// newTestRedis starts an in-process Redis and returns a client for it.
+func newTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, mr
+}
+
+var testKey = []byte("test-secret-key")
+
+// newTestAccounts returns Accounts backed by SQLite and an in-process Redis.
+func newTestAccounts(t *testing.T, mailer Mailer) (*Accounts, *gorm.DB) {
+	t.Helper()
+	db := newTestDB(t, &UserModel{}, &SessionModel{}, &SettingModel{}, &AccountTokenModel{}, &AuditEventModel{})
+	client, _ := newTestRedis(t)
+	policy, err := LoadPasswordPolicy(10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAccounts(db, testKey, NewLoginGuard(client), NewSessions(db, client, NewHub(client)), mailer, policy, "https://app.example.com/"), db
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	catalogpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1"
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	"github.com/Prof-Rosario-UCLA/team15/gen/openapi"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// gatewayPathPrefix starts every gateway route. Requests under it are
+	// rate limited and audited by the gRPC interceptors, not the HTTP
+	// middleware, so they are not counted twice.
+	gatewayPathPrefix = "/v1/"
+	// maxGatewayBody limits request bodies.
+	maxGatewayBody = 1 << 20
+	// inProcessBuffer is the buffer of the in-memory gRPC listener.
+	inProcessBuffer = 1 << 20
+)
+
+// gatewayHeaders are forwarded from HTTP requests as gRPC metadata, besides
+// Authorization, which grpc-gateway always forwards.
+var gatewayHeaders = []string{"cookie", csrfHeader, ServiceTokenHeader}
+
+func isGatewayPath(path string) bool {
+	return strings.HasPrefix(path, gatewayPathPrefix)
+}
+
+// Gateway serves CatalogService and HealthService as HTTP/JSON with the
+// grpc-gateway handlers generated from their google.api.http options. The
+// handlers call the gRPC server, so requests pass through the same
+// interceptors as any other call.
+type Gateway struct {
+	mux *runtime.ServeMux
+}
+
+// NewInProcessConn returns a listener for the gRPC server to also serve on
+// and a client of it, connected in memory, for the gateway. The gateway so
+// reaches the server whatever address and TLS setup it listens with.
+func NewInProcessConn() (*bufconn.Listener, *grpc.ClientConn, error) {
+	lis := bufconn.Listen(inProcessBuffer)
+	conn, err := grpc.NewClient("passthrough:///in-process",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		lis.Close()
+		return nil, nil, err
+	}
+	return lis, conn, nil
+}
+
+// NewGateway registers the generated handlers; conn is a client of the gRPC
+// server implementing the services.
+func NewGateway(conn grpc.ClientConnInterface) (*Gateway, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(gatewayResponseHeader),
+		runtime.WithMetadata(gatewayMetadata),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+		}),
+	)
+	ctx := context.Background()
+	if err := catalogpb.RegisterCatalogServiceHandlerClient(ctx, mux, catalogpb.NewCatalogServiceClient(conn)); err != nil {
+		return nil, err
+	}
+	if err := healthpb.RegisterHealthServiceHandlerClient(ctx, mux, healthpb.NewHealthServiceClient(conn)); err != nil {
+		return nil, err
+	}
+	return &Gateway{mux: mux}, nil
+}
+
+// Routes mounts the gateway under /v1/ and its OpenAPI document at
+// /openapi.json.
+func (g *Gateway) Routes(r chi.Router) {
+	r.Handle(gatewayPathPrefix+"*", g)
+	r.Get("/openapi.json", OpenAPIHandler)
+}
+
+// ServeHTTP passes r to the generated handlers. The client IP is sent as the
+// only X-Forwarded-For entry, so the gRPC server sees the address the HTTP
+// middleware would have used instead of the in-process connection.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ip := HTTPClientIP(r)
+	r = r.Clone(r.Context())
+	r.Header.Del("X-Forwarded-For")
+	r.RemoteAddr = net.JoinHostPort(ip, "0")
+	r.Body = http.MaxBytesReader(w, r.Body, maxGatewayBody)
+	g.mux.ServeHTTP(w, r)
+}
+
+func gatewayHeaderMatcher(key string) (string, bool) {
+	for _, h := range gatewayHeaders {
+		if strings.EqualFold(key, h) {
+			return h, true
+		}
+	}
+	return "", false
+}
+
+// gatewayResponseHeader passes on the retry-after of rate limited calls as
+// is and other response metadata with grpc-gateway's prefix.
+func gatewayResponseHeader(key string) (string, bool) {
+	if key == "retry-after" {
+		return "Retry-After", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// gatewayMetadata forwards the request ID.
+func gatewayMetadata(ctx context.Context, r *http.Request) metadata.MD {
+	if id := RequestIDFromContext(r.Context()); id != "" {
+		return metadata.Pairs(RequestIDHeader, id)
+	}
+	return nil
+}
+
+// OpenAPIHandler serves the OpenAPI document of the gateway.
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapi.Document)
+}
@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	catalogpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1"
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gatewayCatalog and gatewayHealth answer the gateway's calls from fixed
+// data and note who called.
+type gatewayCatalog struct {
+	catalogpb.UnimplementedCatalogServiceServer
+	mu       sync.Mutex
+	callerIP string
+}
+
+func (s *gatewayCatalog) ListServices(_ *catalogpb.ListServicesRequest, stream catalogpb.CatalogService_ListServicesServer) error {
+	for _, name := range []string{"api", "db"} {
+		if err := stream.Send(&catalogpb.ListServicesResponse{Services: []*catalogpb.Service{{Id: name + "-1", Name: name}}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *gatewayCatalog) CreateService(ctx context.Context, req *catalogpb.CreateServiceRequest) (*catalogpb.CreateServiceResponse, error) {
+	s.mu.Lock()
+	s.callerIP = grpcClientIP(ctx)
+	s.mu.Unlock()
+	svc := req.GetService()
+	svc.Id = "new-1"
+	return &catalogpb.CreateServiceResponse{Service: svc}, nil
+}
+
+func (s *gatewayCatalog) UpdateService(_ context.Context, req *catalogpb.UpdateServiceRequest) (*catalogpb.UpdateServiceResponse, error) {
+	return &catalogpb.UpdateServiceResponse{Service: req.GetService()}, nil
+}
+
+func (s *gatewayCatalog) DeleteService(_ context.Context, req *catalogpb.DeleteServiceRequest) (*catalogpb.DeleteServiceResponse, error) {
+	return nil, status.Errorf(codes.NotFound, "service %s not found", req.GetId())
+}
+
+type gatewayHealth struct {
+	healthpb.UnimplementedHealthServiceServer
+}
+
+func (gatewayHealth) WatchHealth(req *healthpb.WatchHealthRequest, stream healthpb.HealthService_WatchHealthServer) error {
+	for _, st := range []healthpb.Status{healthpb.Status_STATUS_UP, healthpb.Status_STATUS_DEGRADED} {
+		if err := stream.Send(&healthpb.WatchHealthResponse{ServiceId: req.GetServiceId(), Status: st}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gatewayHealth) GetHealthHistory(_ context.Context, req *healthpb.GetHealthHistoryRequest) (*healthpb.GetHealthHistoryResponse, error) {
+	return &healthpb.GetHealthHistoryResponse{
+		StepMs: req.GetStepMs(),
+		Points: []*healthpb.HealthHistoryPoint{{StartMs: req.GetFromMs()}},
+	}, nil
+}
+
+// newTestGateway serves the fakes behind the JWT interceptors on an
+// in-process connection and returns the gateway's routes.
+func newTestGateway(t *testing.T) (http.Handler, *gatewayCatalog) {
+	client, _ := newTestRedis(t)
+	sessions := NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client))
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(JWTInterceptor(testKey, sessions)),
+		grpc.ChainStreamInterceptor(JWTStreamInterceptor(testKey, sessions)),
+	)
+	catalog := &gatewayCatalog{}
+	catalogpb.RegisterCatalogServiceServer(srv, catalog)
+	healthpb.RegisterHealthServiceServer(srv, gatewayHealth{})
+
+	lis, conn, err := NewInProcessConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+
+	g, err := NewGateway(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := chi.NewRouter()
+	g.Routes(router)
+	return router, catalog
+}
+
+func TestGateway(t *testing.T) {
+	router, catalog := newTestGateway(t)
+	token := func(roles ...string) string {
+		s, _, err := issueToken(testKey, "", "alice", roles, "", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + s
+	}
+	admin, operator, viewer := token(roleAdmin), token(roleOperator), token()
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		auth        string
+		header      map[string]string
+		body        string
+		want        int
+		contentType string
+		contains    []string
+	}{
+		{name: "stream", method: http.MethodGet, target: "/v1/services", auth: viewer,
+			want: http.StatusOK, contentType: "application/json", contains: []string{
+				`{"result":{"services":[{"id":"api-1"`, `{"result":{"services":[{"id":"db-1"`}},
+		{name: "watch stream", method: http.MethodGet, target: "/v1/services/svc-1/health", auth: viewer,
+			want: http.StatusOK, contains: []string{`"status":"STATUS_UP"`, `"status":"STATUS_DEGRADED"`, `"service_id":"svc-1"`}},
+		{name: "no token", method: http.MethodGet, target: "/v1/services",
+			want: http.StatusUnauthorized, contains: []string{`"code":16`}},
+		{name: "streamed call without token", method: http.MethodGet, target: "/v1/services/svc-1/health",
+			want: http.StatusUnauthorized, contentType: "application/json"},
+		{name: "body field", method: http.MethodPost, target: "/v1/services", auth: operator, body: `{"name": "api", "labels": {"tier": "critical"}}`,
+			want: http.StatusOK, contains: []string{`"id":"new-1"`, `"name":"api"`, `"tier":"critical"`}},
+		{name: "role checked", method: http.MethodPost, target: "/v1/services", auth: viewer, body: `{"name": "api"}`,
+			want: http.StatusForbidden, contains: []string{`"code":7`}},
+		{name: "cookie without CSRF token", method: http.MethodPost, target: "/v1/services", body: `{"name": "api"}`,
+			header: map[string]string{"Cookie": "access_token=" + strings.TrimPrefix(operator, "Bearer ")},
+			want:   http.StatusForbidden},
+		{name: "nested path parameter", method: http.MethodPut, target: "/v1/services/svc%201", auth: operator, body: `{"name": "renamed"}`,
+			want: http.StatusOK, contains: []string{`"id":"svc 1"`, `"name":"renamed"`}},
+		{name: "gRPC error", method: http.MethodDelete, target: "/v1/services/missing", auth: admin,
+			want: http.StatusNotFound, contains: []string{`"message":"service missing not found"`}},
+		{name: "query parameters", method: http.MethodGet, target: "/v1/services/svc-1/health/history?from_ms=5&step_ms=60000", auth: viewer,
+			want: http.StatusOK, contains: []string{`"step_ms":"60000"`, `"start_ms":"5"`}},
+		{name: "invalid query value", method: http.MethodGet, target: "/v1/services/svc-1/health/history?from_ms=yesterday", auth: viewer,
+			want: http.StatusBadRequest, contains: []string{`"code":3`}},
+		{name: "unknown query parameter", method: http.MethodGet, target: "/v1/services/svc-1/health/history?page=2", auth: viewer,
+			want: http.StatusOK},
+		{name: "invalid body", method: http.MethodPost, target: "/v1/services", auth: operator, body: `{"name": 1}`,
+			want: http.StatusBadRequest},
+		{name: "unimplemented", method: http.MethodGet, target: "/v1/anomalies", auth: viewer,
+			want: http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		req.RemoteAddr = "192.0.2.10:4321"
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: %d %s, want %d", tt.name, rec.Code, rec.Body, tt.want)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); tt.contentType != "" && ct != tt.contentType {
+			t.Errorf("%s: content type %q, want %q", tt.name, ct, tt.contentType)
+		}
+		for _, s := range tt.contains {
+			if !strings.Contains(rec.Body.String(), s) {
+				t.Errorf("%s: body %s lacks %s", tt.name, rec.Body, s)
+			}
+		}
+	}
+
+	// The gRPC server sees the HTTP client, not the in-process connection
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+	if catalog.callerIP != "192.0.2.10" {
+		t.Errorf("gRPC call from %q, want the HTTP client's address", catalog.callerIP)
+	}
+}
+
+func TestGatewayOpenAPI(t *testing.T) {
+	router, _ := newTestGateway(t)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("openapi.json = %d", rec.Code)
+	}
+	for _, path := range []string{`"/v1/services"`, `"/v1/services/{service.id}"`, `"/v1/services/{service_id}/health"`, `"/v1/health/reports"`} {
+		if !strings.Contains(rec.Body.String(), path) {
+			t.Errorf("openapi.json lacks %s", path)
+		}
+	}
+}
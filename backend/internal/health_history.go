@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HealthBucket aggregates the health samples of one service over
+// [Start, Start+step).
+type HealthBucket struct {
+	ServiceID    string
+	Start        int64 // unix ms
+	Samples      int64
+	UpSamples    int64
+	LatencySumMs int64
+	LatencyMaxMs int32
+	ErrorRateSum float64
+	ErrorRateMax float32
+}
+
+type bucketKey struct {
+	serviceID string
+	start     int64
+}
+
+// HealthHistory answers range queries over health samples, reading raw
+// samples or a rollup table depending on the requested step and on what the
+// retention policy still keeps.
+type HealthHistory struct {
+	db     *gorm.DB
+	policy RetentionPolicy
+}
+
+func NewHealthHistory(db *gorm.DB, policy RetentionPolicy) *HealthHistory {
+	return &HealthHistory{db: db, policy: policy}
+}
+
+// level picks the rollup level to read for a query starting at from: the
+// coarsest one that step is a multiple of, moved to coarser levels while the
+// data at from has been purged. -1 means raw samples. The step is widened to
+// a multiple of the chosen level's resolution.
+func (h *HealthHistory) level(from, now time.Time, step time.Duration) (int, time.Duration) {
+	level := -1
+	if step > 0 {
+		for i, lvl := range rollupLevels {
+			if step >= lvl.step && step%lvl.step == 0 {
+				level = i
+			}
+		}
+	}
+	covers := func(i int) bool {
+		keep := h.policy.forLevel(i)
+		return keep == 0 || !from.Before(now.Add(-keep))
+	}
+	for level < len(rollupLevels)-1 && !covers(level) {
+		level++
+	}
+	if level >= 0 && step > 0 && step%rollupLevels[level].step != 0 {
+		res := rollupLevels[level].step
+		step = (step + res - 1) / res * res
+	}
+	return level, step
+}
+
+// Query returns per-service buckets of width step covering [from, to), in
+// service then time order; buckets without samples are omitted. A zero step
+// returns a single bucket per service starting at from. With
+// excludeMaintenance, samples taken during maintenance are left out of
+// Samples and UpSamples. The step actually used is returned.
+//
+// Rolled-up data is combined with finer levels and raw samples for the part
+// of the range not rolled up yet, so recent data is never missing. Buckets
+// of a coarse level that straddle from are left out.
+func (h *HealthHistory) Query(ctx context.Context, serviceIDs []string, from, to time.Time, step time.Duration, excludeMaintenance bool) ([]HealthBucket, time.Duration, error) {
+	level, step := h.level(from, time.Now(), step)
+
+	origin, stepMs := int64(0), step.Milliseconds()
+	if step == 0 {
+		origin, stepMs = from.UnixMilli(), to.Sub(from).Milliseconds()+1
+	}
+
+	merged := map[bucketKey]*HealthBucket{}
+	lo, hi := from.UnixMilli(), to.UnixMilli()
+	for i := level; i >= -1 && lo < hi; i-- {
+		end := hi
+		if i >= 0 {
+			wm, ok, err := rollupWatermark(ctx, h.db, rollupLevels[i].table)
+			if err != nil {
+				return nil, step, err
+			}
+			if !ok {
+				continue
+			}
+			end = min(end, wm)
+		}
+		if end <= lo {
+			continue
+		}
+		rows, err := h.scan(ctx, i, serviceIDs, lo, end, origin, stepMs, excludeMaintenance)
+		if err != nil {
+			return nil, step, err
+		}
+		for _, r := range rows {
+			key := bucketKey{r.ServiceID, r.Start}
+			b, ok := merged[key]
+			if !ok {
+				b = &HealthBucket{ServiceID: r.ServiceID, Start: r.Start}
+				merged[key] = b
+			}
+			b.Samples += r.Samples
+			b.UpSamples += r.UpSamples
+			b.LatencySumMs += r.LatencySumMs
+			b.LatencyMaxMs = max(b.LatencyMaxMs, r.LatencyMaxMs)
+			b.ErrorRateSum += r.ErrorRateSum
+			b.ErrorRateMax = max(b.ErrorRateMax, r.ErrorRateMax)
+		}
+		lo = end
+	}
+
+	out := make([]HealthBucket, 0, len(merged))
+	for _, b := range merged {
+		if b.Samples > 0 {
+			out = append(out, *b)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ServiceID != out[j].ServiceID {
+			return out[i].ServiceID < out[j].ServiceID
+		}
+		return out[i].Start < out[j].Start
+	})
+	return out, step, nil
+}
+
+// scan aggregates one source (rollup level i, or raw samples for -1) over
+// [from, to) into buckets.
+func (h *HealthHistory) scan(ctx context.Context, i int, serviceIDs []string, from, to, origin, step int64, excludeMaintenance bool) ([]HealthBucket, error) {
+	table, column := healthMetricsTable, "timestamp"
+	samples, up := "COUNT(*)", "SUM(CASE WHEN status = @up THEN 1 ELSE 0 END)"
+	latencySum, latencyMax := "SUM(latency_ms)", "MAX(latency_ms)"
+	errorSum, errorMax := "SUM(error_rate)", "MAX(error_rate)"
+	if i < 0 && excludeMaintenance {
+		samples = "SUM(CASE WHEN in_maintenance THEN 0 ELSE 1 END)"
+		up = "SUM(CASE WHEN status = @up AND NOT in_maintenance THEN 1 ELSE 0 END)"
+	}
+	if i >= 0 {
+		table, column = rollupLevels[i].table, "bucket"
+		samples, up = "SUM(samples)", "SUM(up_samples)"
+		latencySum, latencyMax = "SUM(latency_sum_ms)", "MAX(latency_max_ms)"
+		errorSum, errorMax = "SUM(error_rate_sum)", "MAX(error_rate_max)"
+		if excludeMaintenance {
+			samples, up = "SUM(samples - maintenance_samples)", "SUM(up_samples - maintenance_up_samples)"
+		}
+	}
+
+	query := fmt.Sprintf(`SELECT service_id, @origin + (%[2]s - @origin) / @step * @step AS start,
+    %[3]s AS samples, %[4]s AS up_samples, %[5]s AS latency_sum_ms, %[6]s AS latency_max_ms,
+    %[7]s AS error_rate_sum, %[8]s AS error_rate_max
+FROM %[1]s WHERE service_id IN @ids AND %[2]s >= @from AND %[2]s < @to
+GROUP BY 1, 2`, table, column, samples, up, latencySum, latencyMax, errorSum, errorMax)
+
+	var rows []HealthBucket
+	err := h.db.WithContext(ctx).Raw(query, map[string]interface{}{
+		"ids": serviceIDs, "from": from, "to": to, "origin": origin, "step": step, "up": int32(1), // STATUS_UP
+	}).Scan(&rows).Error
+	return rows, err
+}
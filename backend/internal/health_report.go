@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxReportAge is how old a pushed sample may be; it matches the
+	// shortest allowed raw retention, so the partition it goes to exists.
+	maxReportAge = time.Hour
+	// maxReportSkew tolerates reporters whose clocks run slightly ahead.
+	maxReportSkew  = time.Minute
+	maxReportBatch = 1000
+)
+
+// ReportHealth stores samples streamed by an instrumented service
+func (h *HealthServerImpl) ReportHealth(stream healthpb.HealthService_ReportHealthServer) error {
+	ctx := stream.Context()
+	rep, err := h.authorizeReporter(ctx)
+	if err != nil {
+		return err
+	}
+
+	var accepted int64
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&healthpb.ReportHealthResponse{Accepted: accepted})
+		}
+		if err != nil {
+			return err
+		}
+		m, err := rep.sample(req.Sample, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := h.record(ctx, &m); err != nil {
+			return status.Errorf(codes.Unavailable, "failed to store sample after %d accepted", accepted)
+		}
+		accepted++
+	}
+}
+
+// ReportHealthBatch stores a batch of samples from an instrumented service.
+// The whole batch is validated before anything is stored.
+func (h *HealthServerImpl) ReportHealthBatch(ctx context.Context, req *healthpb.ReportHealthBatchRequest) (*healthpb.ReportHealthBatchResponse, error) {
+	rep, err := h.authorizeReporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Samples) > maxReportBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d samples per batch", maxReportBatch)
+	}
+
+	now := time.Now()
+	samples := make([]HealthMetricModel, len(req.Samples))
+	for i, p := range req.Samples {
+		if samples[i], err = rep.sample(p, now); err != nil {
+			return nil, err
+		}
+	}
+	for i := range samples {
+		if err := h.record(ctx, &samples[i]); err != nil {
+			return nil, status.Errorf(codes.Unavailable, "failed to store sample after %d accepted", i)
+		}
+	}
+	return &healthpb.ReportHealthBatchResponse{Accepted: int64(len(samples))}, nil
+}
+
+// reporter is the caller of ReportHealth or ReportHealthBatch: a service
+// token, which may only report for its own service, or a service account
+// token with health:write, which may report for any service in the
+// catalog.
+type reporter struct {
+	serviceID string          // the token's service, or "" for a service account
+	services  map[string]bool // IDs of the services in the catalog
+}
+
+// authorizeReporter checks the caller's service token, or that it is a
+// service account with health:write, and loads the catalog.
+func (h *HealthServerImpl) authorizeReporter(ctx context.Context) (*reporter, error) {
+	rep := &reporter{}
+	if hasServiceToken(ctx) {
+		token, err := authenticateServiceToken(ctx, h.db)
+		if err != nil {
+			return nil, err
+		}
+		rep.serviceID = token.ServiceID
+	} else if info := requestInfoFromContext(ctx); info == nil || !strings.HasPrefix(info.Principal, serviceAccountSubjectPrefix) {
+		return nil, status.Error(codes.Unauthenticated, ServiceTokenHeader+" not provided")
+	} else if !scopeGranted(info.Scopes, "health:write") {
+		return nil, status.Error(codes.PermissionDenied, "token lacks scope health:write")
+	}
+
+	services, err := h.catalog.Services(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "catalog unavailable")
+	}
+	rep.services = make(map[string]bool, len(services))
+	for _, svc := range services {
+		rep.services[svc.ID] = true
+	}
+	if rep.serviceID != "" && !rep.services[rep.serviceID] {
+		return nil, status.Errorf(codes.NotFound, "service %q not found", rep.serviceID)
+	}
+	return rep, nil
+}
+
+// sample validates a pushed sample.
+func (rep *reporter) sample(p *healthpb.WatchHealthResponse, now time.Time) (HealthMetricModel, error) {
+	if p == nil {
+		return HealthMetricModel{}, status.Error(codes.InvalidArgument, "sample is required")
+	}
+	serviceID := p.ServiceId
+	if serviceID == "" {
+		serviceID = rep.serviceID
+	}
+	switch {
+	case serviceID == "":
+		return HealthMetricModel{}, status.Error(codes.InvalidArgument, "service_id is required")
+	case rep.serviceID != "" && serviceID != rep.serviceID:
+		return HealthMetricModel{}, status.Errorf(codes.PermissionDenied, "token is not valid for service %q", serviceID)
+	case !rep.services[serviceID]:
+		return HealthMetricModel{}, status.Errorf(codes.NotFound, "service %q not found", serviceID)
+	}
+	if _, ok := healthpb.Status_name[int32(p.Status)]; !ok {
+		return HealthMetricModel{}, status.Errorf(codes.InvalidArgument, "unknown status %d", p.Status)
+	}
+	if p.Status == healthpb.Status_STATUS_MAINTENANCE {
+		return HealthMetricModel{}, status.Error(codes.InvalidArgument, "maintenance is set by the server from maintenance windows")
+	}
+	if p.LatencyMs < 0 {
+		return HealthMetricModel{}, status.Error(codes.InvalidArgument, "latency_ms must not be negative")
+	}
+	if math.IsNaN(float64(p.ErrorRate)) || p.ErrorRate < 0 || p.ErrorRate > 1 {
+		return HealthMetricModel{}, status.Error(codes.InvalidArgument, "error_rate must be between 0 and 1")
+	}
+
+	ts := p.TimestampMs
+	if ts == 0 {
+		ts = now.UnixMilli()
+	}
+	if t := time.UnixMilli(ts); t.After(now.Add(maxReportSkew)) || t.Before(now.Add(-maxReportAge)) {
+		return HealthMetricModel{}, status.Errorf(codes.InvalidArgument,
+			"timestamp_ms must be within %s in the past and %s in the future", maxReportAge, maxReportSkew)
+	}
+
+	return HealthMetricModel{
+		ServiceID: serviceID,
+		Status:    int32(p.Status),
+		LatencyMs: p.LatencyMs,
+		ErrorRate: p.ErrorRate,
+		Timestamp: ts,
+	}, nil
+}
@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// newTestReporting returns a health server whose catalog holds api and db,
+// with a service token for api.
+func newTestReporting(t *testing.T) (*HealthServerImpl, string) {
+	t.Helper()
+	db := newTestDB(t, &ServiceModel{}, &ServiceTokenModel{}, &HealthMetricModel{}, &StatusChangeModel{}, &MaintenanceWindowModel{})
+	client, _ := newTestRedis(t)
+	for _, id := range []string{"api", "db"} {
+		if err := db.Create(&ServiceModel{ID: id, Name: id}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	token := "t15s_" + randomToken()
+	if err := db.Create(&ServiceTokenModel{ID: newID(), ServiceID: "api", TokenHash: hashServiceToken(token)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return NewHealthServer(db, client, NewCatalogCache(db, client), NewMaintenanceSchedule(db), nil, NewHub(client)), token
+}
+
+// TestAuthorizeReporter checks who may push samples, and for which
+// services.
+func TestAuthorizeReporter(t *testing.T) {
+	h, token := newTestReporting(t)
+	serviceAccount := func(scopes ...string) context.Context {
+		return contextWithRequestInfo(context.Background(), &requestInfo{Principal: serviceAccountSubjectPrefix + "agent", Scopes: scopes})
+	}
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(ServiceTokenHeader, token))
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		err     codes.Code
+		samples map[string]codes.Code // service_id to the sample's outcome
+	}{
+		{name: "service token", ctx: withToken(token), samples: map[string]codes.Code{
+			"": codes.OK, "api": codes.OK, "db": codes.PermissionDenied,
+		}},
+		{name: "unknown service token", ctx: withToken("t15s_forged"), err: codes.Unauthenticated},
+		{name: "service account", ctx: serviceAccount("health:write"), samples: map[string]codes.Code{
+			"api": codes.OK, "db": codes.OK, "": codes.InvalidArgument, "gone": codes.NotFound,
+		}},
+		{name: "service account with read scope", ctx: serviceAccount("health:read", "catalog:admin"), err: codes.PermissionDenied},
+		{name: "user", ctx: contextWithRequestInfo(context.Background(), &requestInfo{Principal: "alice", Roles: []string{roleAdmin}}), err: codes.Unauthenticated},
+		{name: "no credentials", ctx: context.Background(), err: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		rep, err := h.authorizeReporter(tt.ctx)
+		if status.Code(err) != tt.err {
+			t.Errorf("%s: authorizeReporter = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		for id, want := range tt.samples {
+			_, err := rep.sample(&healthpb.WatchHealthResponse{ServiceId: id, Status: healthpb.Status_STATUS_UP}, time.Now())
+			if status.Code(err) != want {
+				t.Errorf("%s: sample for %q = %v, want %v", tt.name, id, err, want)
+			}
+		}
+	}
+}
+
+// watchStream is a WatchHealth stream that collects what is sent.
+type watchStream struct {
+	fakeServerStream
+	sent []*healthpb.WatchHealthResponse
+}
+
+func (s *watchStream) Send(m *healthpb.WatchHealthResponse) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// TestWatchHealthUnknownService checks that only catalog services can be
+// watched.
+func TestWatchHealthUnknownService(t *testing.T) {
+	h, _ := newTestReporting(t)
+	for id, want := range map[string]codes.Code{
+		"api":  codes.OK,
+		"gone": codes.NotFound,
+		"":     codes.InvalidArgument,
+	} {
+		// A known service streams until the caller goes away
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		err := h.WatchHealth(&healthpb.WatchHealthRequest{ServiceId: id}, &watchStream{fakeServerStream: fakeServerStream{ctx: ctx}})
+		cancel()
+		if status.Code(err) != want {
+			t.Errorf("WatchHealth(%q) = %v, want %v", id, err, want)
+		}
+	}
+}
+
+func TestReporterSample(t *testing.T) {
+	rep := &reporter{services: map[string]bool{"api": true}}
+	now := time.Now()
+	tests := []struct {
+		name   string
+		sample *healthpb.WatchHealthResponse
+		want   codes.Code
+	}{
+		{"valid", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_DEGRADED, LatencyMs: 120, ErrorRate: 0.02}, codes.OK},
+		{"missing", nil, codes.InvalidArgument},
+		{"unknown service", &healthpb.WatchHealthResponse{ServiceId: "gone", Status: healthpb.Status_STATUS_UP}, codes.NotFound},
+		{"invalid status", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status(99)}, codes.InvalidArgument},
+		{"maintenance status", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_MAINTENANCE}, codes.InvalidArgument},
+		{"negative latency", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_UP, LatencyMs: -1}, codes.InvalidArgument},
+		{"error rate above 1", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_UP, ErrorRate: 1.5}, codes.InvalidArgument},
+		{"error rate NaN", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_UP, ErrorRate: float32(math.NaN())}, codes.InvalidArgument},
+		{"too old", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_UP, TimestampMs: now.Add(-maxReportAge - time.Second).UnixMilli()}, codes.InvalidArgument},
+		{"too far ahead", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_UP, TimestampMs: now.Add(maxReportSkew + time.Second).UnixMilli()}, codes.InvalidArgument},
+		{"slightly ahead", &healthpb.WatchHealthResponse{ServiceId: "api", Status: healthpb.Status_STATUS_UP, TimestampMs: now.Add(maxReportSkew / 2).UnixMilli()}, codes.OK},
+	}
+	for _, tt := range tests {
+		m, err := rep.sample(tt.sample, now)
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		if err == nil && (m.ServiceID != "api" || m.Timestamp == 0) {
+			t.Errorf("%s: sample %+v", tt.name, m)
+		}
+	}
+}
+
+// TestReportHealthBatch checks that a batch is validated as a whole, and
+// that a storage failure part way reports how many samples were kept.
+func TestReportHealthBatch(t *testing.T) {
+	h, token := newTestReporting(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ServiceTokenHeader, token))
+	stored := func() int64 {
+		var n int64
+		h.db.Model(&HealthMetricModel{}).Count(&n)
+		return n
+	}
+	up := func() *healthpb.WatchHealthResponse {
+		return &healthpb.WatchHealthResponse{Status: healthpb.Status_STATUS_UP, LatencyMs: 20}
+	}
+
+	// One bad sample rejects the batch and nothing is stored
+	_, err := h.ReportHealthBatch(ctx, &healthpb.ReportHealthBatchRequest{Samples: []*healthpb.WatchHealthResponse{
+		up(), {Status: healthpb.Status(99)}, up(),
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("batch with an invalid status: %v, want InvalidArgument", err)
+	}
+	_, err = h.ReportHealthBatch(ctx, &healthpb.ReportHealthBatchRequest{Samples: []*healthpb.WatchHealthResponse{
+		up(), {ServiceId: "db", Status: healthpb.Status_STATUS_UP},
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("batch with another service's sample: %v, want PermissionDenied", err)
+	}
+	if n := stored(); n != 0 {
+		t.Errorf("%d samples stored from rejected batches", n)
+	}
+
+	resp, err := h.ReportHealthBatch(ctx, &healthpb.ReportHealthBatchRequest{Samples: []*healthpb.WatchHealthResponse{up(), up()}})
+	if err != nil || resp.Accepted != 2 {
+		t.Fatalf("valid batch: %v, %v", resp, err)
+	}
+	if n := stored(); n != 2 {
+		t.Errorf("%d samples stored, want 2", n)
+	}
+
+	// The second insert fails
+	var inserts atomic.Int32
+	h.db.Callback().Create().Before("gorm:create").Register("test:fail", func(tx *gorm.DB) {
+		if _, ok := tx.Statement.Dest.(*HealthMetricModel); ok && inserts.Add(1) == 2 {
+			tx.AddError(errors.New("disk full"))
+		}
+	})
+	_, err = h.ReportHealthBatch(ctx, &healthpb.ReportHealthBatchRequest{Samples: []*healthpb.WatchHealthResponse{up(), up(), up()}})
+	if status.Code(err) != codes.Unavailable || !strings.Contains(err.Error(), "after 1 accepted") {
+		t.Errorf("failed insert: %v, want Unavailable after 1 accepted", err)
+	}
+	if n := stored(); n != 3 {
+		t.Errorf("%d samples stored, want the 2 before and 1 from the failed batch", n)
+	}
+
+	samples := make([]*healthpb.WatchHealthResponse, maxReportBatch+1)
+	for i := range samples {
+		samples[i] = up()
+	}
+	if _, err := h.ReportHealthBatch(ctx, &healthpb.ReportHealthBatchRequest{Samples: samples}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("oversized batch: %v, want InvalidArgument", err)
+	}
+}
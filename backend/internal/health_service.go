@@ -1,71 +1,280 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"slices"
 	"time"
 
 	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
 	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 )
 
-// Global variable that generates a random value each time WatchHealth is invoked
-var i int
-
 type HealthServerImpl struct {
 	healthpb.UnimplementedHealthServiceServer
-	db    *gorm.DB
-	redis *redis.Client
+	db          *gorm.DB
+	redis       *redis.Client
+	catalog     *CatalogCache
+	maintenance *MaintenanceSchedule
+	history     *HealthHistory
+	hub         *Hub
+	anomalies   *AnomalyDetector
+	simulate    bool
+}
+
+func NewHealthServer(db *gorm.DB, redisClient *redis.Client, catalog *CatalogCache, maintenance *MaintenanceSchedule, history *HealthHistory, hub *Hub) *HealthServerImpl {
+	return &HealthServerImpl{db: db, redis: redisClient, catalog: catalog, maintenance: maintenance, history: history, hub: hub}
 }
 
-func NewHealthServer(db *gorm.DB, redisClient *redis.Client) *HealthServerImpl {
-	return &HealthServerImpl{db: db, redis: redisClient}
+// Simulate makes WatchHealth generate random samples for watched services
+// that do not report their own health.
+func (h *HealthServerImpl) Simulate(enabled bool) {
+	h.simulate = enabled
 }
 
-// WatchHealth streams health metrics, caching recent metrics for performance
+// DetectAnomalies scores every new sample with d; nil disables it.
+func (h *HealthServerImpl) DetectAnomalies(d *AnomalyDetector) {
+	h.anomalies = d
+}
+
+const (
+	defaultHistoryPoints = 300
+	maxHistoryPoints     = 5000
+)
+
+// GetHealthHistory returns bucketed samples of one service, read from raw
+// samples or rollups depending on the range and step
+func (h *HealthServerImpl) GetHealthHistory(ctx context.Context, req *healthpb.GetHealthHistoryRequest) (*healthpb.GetHealthHistoryResponse, error) {
+	if req.ServiceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id is required")
+	}
+	to := time.Now()
+	if req.ToMs > 0 {
+		to = time.UnixMilli(req.ToMs)
+	}
+	from := to.Add(-time.Hour)
+	if req.FromMs > 0 {
+		from = time.UnixMilli(req.FromMs)
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from_ms must be before to_ms")
+	}
+	step := time.Duration(req.StepMs) * time.Millisecond
+	if step <= 0 {
+		step = max(to.Sub(from)/defaultHistoryPoints, time.Second).Truncate(time.Second)
+	}
+	if to.Sub(from)/step > maxHistoryPoints {
+		return nil, status.Errorf(codes.InvalidArgument, "step_ms too small: more than %d points", maxHistoryPoints)
+	}
+
+	buckets, step, err := h.history.Query(ctx, []string{req.ServiceId}, from, to, step, false)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "failed to query health history")
+	}
+	resp := &healthpb.GetHealthHistoryResponse{StepMs: step.Milliseconds()}
+	for _, b := range buckets {
+		resp.Points = append(resp.Points, &healthpb.HealthHistoryPoint{
+			StartMs:      b.Start,
+			Samples:      b.Samples,
+			UpSamples:    b.UpSamples,
+			AvgLatencyMs: float64(b.LatencySumMs) / float64(b.Samples),
+			MaxLatencyMs: b.LatencyMaxMs,
+			AvgErrorRate: b.ErrorRateSum / float64(b.Samples),
+			MaxErrorRate: b.ErrorRateMax,
+		})
+	}
+	return resp, nil
+}
+
+// healthSimulateEvery is how often the simulator produces a sample for a
+// watched service that is not pushing its own.
+const healthSimulateEvery = 5 * time.Second
+
+func healthTopic(serviceID string) string {
+	return "health:" + serviceID
+}
+
+// WatchHealth streams the latest sample of a service and then every new one,
+// whether pushed through ReportHealth (on any instance) or simulated
 func (h *HealthServerImpl) WatchHealth(req *healthpb.WatchHealthRequest, stream healthpb.HealthService_WatchHealthServer) error {
 	ctx := stream.Context()
+	if req.ServiceId == "" {
+		return status.Error(codes.InvalidArgument, "service_id is required")
+	}
+	// Only catalog services, so the simulator cannot be made to create a
+	// series for any id a caller makes up
+	services, err := h.catalog.Services(ctx)
+	if err != nil {
+		return status.Error(codes.Unavailable, "catalog unavailable")
+	}
+	if !slices.ContainsFunc(services, func(s ServiceModel) bool { return s.ID == req.ServiceId }) {
+		return status.Errorf(codes.NotFound, "service %q not found", req.ServiceId)
+	}
 
-	// Set i to a random value each time the function is invoked
-	i = rand.Intn(10)
+	updates, unsubscribe := h.hub.Subscribe(healthTopic(req.ServiceId))
+	defer unsubscribe()
+
+	last, ok := h.latest(ctx, req.ServiceId)
+	if ok {
+		if err := stream.Send(metricToProto(last)); err != nil {
+			return err
+		}
+	}
+
+	var simulate <-chan time.Time
+	if h.simulate {
+		ticker := time.NewTicker(healthSimulateEvery)
+		defer ticker.Stop()
+		simulate = ticker.C
+		if !ok || time.Since(time.UnixMilli(last.Timestamp)) > 2*healthSimulateEvery {
+			h.simulateSample(ctx, req.ServiceId)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case payload := <-updates:
+			var m HealthMetricModel
+			if err := json.Unmarshal(payload, &m); err != nil {
+				continue
+			}
+			if err := stream.Send(metricToProto(m)); err != nil {
+				return err
+			}
+		case <-simulate:
+			// Only stand in for services that are not reporting themselves
+			if m, ok := h.latest(ctx, req.ServiceId); !ok || time.Since(time.UnixMilli(m.Timestamp)) > 2*healthSimulateEvery {
+				h.simulateSample(ctx, req.ServiceId)
+			}
+		}
+	}
+}
+
+// simulateSample records a made-up sample, for demos without instrumented
+// services
+func (h *HealthServerImpl) simulateSample(ctx context.Context, serviceID string) {
+	i := rand.Intn(10)
 
-	// For illustration, send 5 updates spaced 1 second apart
 	metric := HealthMetricModel{
-		ServiceID: req.ServiceId,
+		ServiceID: serviceID,
 		Status:    int32(healthpb.Status_STATUS_UP),
 		LatencyMs: int32(1 + i*10),
 		ErrorRate: float32(i) * 0.01,
 		Timestamp: time.Now().UnixMilli(),
 	}
+	h.record(ctx, &metric)
+}
+
+// metricToProto reports samples taken during maintenance as MAINTENANCE;
+// the stored status stays the measured one so uptime is unaffected.
+func metricToProto(m HealthMetricModel) *healthpb.WatchHealthResponse {
+	st := healthpb.Status(m.Status)
+	if m.InMaintenance {
+		st = healthpb.Status_STATUS_MAINTENANCE
+	}
+	return &healthpb.WatchHealthResponse{
+		ServiceId:     m.ServiceID,
+		Status:        st,
+		LatencyMs:     m.LatencyMs,
+		ErrorRate:     m.ErrorRate,
+		TimestampMs:   m.Timestamp,
+		InMaintenance: m.InMaintenance,
+		Reasons:       m.Reasons,
+		AnomalyScore:  m.AnomalyScore,
+	}
+}
+
+// record derives the status and anomaly score of one health sample, flags,
+// stores, caches and publishes it, and logs a status change when it differs from the previous
+// sample of the service.
+// Samples older than the latest one are only stored. The storage error is
+// returned, but the sample is still cached and published so watchers keep
+// getting live data in degraded mode.
+func (h *HealthServerImpl) record(ctx context.Context, metric *HealthMetricModel) error {
+	svc := ServiceModel{ID: metric.ServiceID}
+	h.db.WithContext(ctx).Limit(1).Find(&svc, "id = ?", metric.ServiceID)
+
+	// Derive the status from the service's thresholds, holding the previous
+	// one until enough samples agree
+	prev, hasPrev := h.latest(ctx, metric.ServiceID)
+	late := hasPrev && metric.Timestamp < prev.Timestamp
+	observed, reasons := classifyHealth(svc.Thresholds, *metric)
+	if hasPrev && !late && metric.Timestamp-prev.Timestamp <= healthStateStale.Milliseconds() {
+		observed, reasons = h.applyHysteresis(ctx, svc.Thresholds, prev, observed, reasons)
+	}
+	metric.Status, metric.Reasons = observed, reasons
+	if h.anomalies != nil && !late {
+		h.anomalies.Observe(ctx, metric)
+	}
+
+	// Flag samples taken during planned maintenance
+	if w := h.maintenance.Active(ctx, svc, time.UnixMilli(metric.Timestamp)); w != nil {
+		metric.InMaintenance = true
+		metric.Reasons = append(metric.Reasons, "maintenance: "+w.Title)
+	}
 
 	// Save to database
-	if err := h.db.Create(&metric).Error; err != nil {
+	err := h.db.WithContext(ctx).Create(metric).Error
+	if err != nil {
+		Logger(ctx).Warn("failed to store health metric", "service_id", metric.ServiceID, "error", err)
+	}
+	if time.Since(time.UnixMilli(metric.Timestamp)) > rollupLateness {
+		// Already rolled up: have the retention job recompute its buckets
+		if err := rewindRollups(ctx, h.db, metric.Timestamp); err != nil {
+			Logger(ctx).Warn("failed to rewind rollups", "service_id", metric.ServiceID, "error", err)
+		}
+	}
+	if late {
 		return err
 	}
 
+	if hasPrev && prev.Status != metric.Status {
+		change := StatusChangeModel{
+			ServiceID:  metric.ServiceID,
+			FromStatus: prev.Status,
+			ToStatus:   metric.Status,
+			At:         metric.Timestamp,
+		}
+		if err := h.db.WithContext(ctx).Create(&change).Error; err != nil {
+			Logger(ctx).Warn("failed to store status change", "service_id", metric.ServiceID, "error", err)
+		}
+	}
+
 	// Cache the latest metric for this service
-	cacheKey := fmt.Sprintf("health:latest:%s", req.ServiceId)
+	cacheKey := fmt.Sprintf("health:latest:%s", metric.ServiceID)
 	metricJSON, _ := json.Marshal(metric)
 	h.redis.Set(ctx, cacheKey, metricJSON, 1*time.Minute)
 
 	// Also add to a time-series cache (last 10 metrics)
-	timeSeriesKey := fmt.Sprintf("health:timeseries:%s", req.ServiceId)
+	timeSeriesKey := fmt.Sprintf("health:timeseries:%s", metric.ServiceID)
 	h.redis.LPush(ctx, timeSeriesKey, metricJSON)
 	h.redis.LTrim(ctx, timeSeriesKey, 0, 9) // Keep only last 10
 	h.redis.Expire(ctx, timeSeriesKey, 10*time.Minute)
 
-	resp := &healthpb.WatchHealthResponse{
-		ServiceId:   metric.ServiceID,
-		Status:      healthpb.Status(metric.Status),
-		LatencyMs:   metric.LatencyMs,
-		ErrorRate:   metric.ErrorRate,
-		TimestampMs: metric.Timestamp,
-	}
-	if err := stream.Send(resp); err != nil {
-		return err
+	// Fan out to watchers on every instance
+	h.hub.Publish(ctx, healthTopic(metric.ServiceID), metricJSON)
+	return err
+}
+
+// latest returns the most recent sample of a service, from Redis when cached.
+func (h *HealthServerImpl) latest(ctx context.Context, serviceID string) (HealthMetricModel, bool) {
+	return latestHealthMetric(ctx, h.db, h.redis, serviceID)
+}
+
+func latestHealthMetric(ctx context.Context, db *gorm.DB, redisClient *redis.Client, serviceID string) (HealthMetricModel, bool) {
+	var m HealthMetricModel
+	if raw, err := redisClient.Get(ctx, fmt.Sprintf("health:latest:%s", serviceID)).Bytes(); err == nil {
+		if json.Unmarshal(raw, &m) == nil {
+			return m, true
+		}
 	}
-	time.Sleep(time.Second)
-	return nil
+	err := db.WithContext(ctx).Where("service_id = ?", serviceID).Order("timestamp desc").Limit(1).Find(&m).Error
+	return m, err == nil && m.ID != 0
 }
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	catalogpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1"
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultRaiseAfter = 3
+	defaultClearAfter = 3
+	// healthStateStale is how old the previous sample may be for hysteresis
+	// to apply; after a gap the first sample sets the status directly.
+	healthStateStale = 10 * time.Minute
+)
+
+// statusSeverity orders statuses from healthy to unhealthy.
+var statusSeverity = map[int32]int{
+	int32(healthpb.Status_STATUS_UP):                  0,
+	int32(healthpb.Status_STATUS_UNKNOWN_UNSPECIFIED): 1,
+	int32(healthpb.Status_STATUS_DEGRADED):            2,
+	int32(healthpb.Status_STATUS_DOWN):                3,
+}
+
+// healthStreakScript counts consecutive samples that disagree with the
+// current status, in one step so that samples recorded at the same time on
+// several instances all count. The streak is kept as {"status","count"} and
+// dropped once it reaches the number needed; the script returns its length.
+var healthStreakScript = redis.NewScript(`
+local status = tonumber(ARGV[1])
+local count = 0
+local raw = redis.call('GET', KEYS[1])
+if raw then
+  local streak = cjson.decode(raw)
+  if streak.status == status then
+    count = streak.count
+  end
+end
+count = count + 1
+if count >= tonumber(ARGV[2]) then
+  redis.call('DEL', KEYS[1])
+else
+  redis.call('SET', KEYS[1], cjson.encode({status = status, count = count}), 'PX', ARGV[3])
+end
+return count
+`)
+
+// validateThresholds checks thresholds before they are saved.
+func validateThresholds(t *HealthThresholds) error {
+	if t.DegradedLatencyMs < 0 || t.DownLatencyMs < 0 {
+		return fmt.Errorf("thresholds latencies must not be negative")
+	}
+	if t.DegradedErrorRate < 0 || t.DegradedErrorRate > 1 || t.DownErrorRate < 0 || t.DownErrorRate > 1 {
+		return fmt.Errorf("thresholds error rates must be between 0 and 1")
+	}
+	if t.DegradedLatencyMs > 0 && t.DownLatencyMs > 0 && t.DegradedLatencyMs > t.DownLatencyMs {
+		return fmt.Errorf("thresholds.degraded_latency_ms must not exceed down_latency_ms")
+	}
+	if t.DegradedErrorRate > 0 && t.DownErrorRate > 0 && t.DegradedErrorRate > t.DownErrorRate {
+		return fmt.Errorf("thresholds.degraded_error_rate must not exceed down_error_rate")
+	}
+	if t.RaiseAfter < 0 || t.ClearAfter < 0 {
+		return fmt.Errorf("thresholds.raise_after and clear_after must not be negative")
+	}
+	return nil
+}
+
+// classifyHealth derives the status of one sample from its reported status
+// and the service's thresholds, with the reasons for it.
+func classifyHealth(t *HealthThresholds, m HealthMetricModel) (int32, []string) {
+	status, reasons := m.Status, m.Reasons
+	if len(reasons) == 0 {
+		switch healthpb.Status(status) {
+		case healthpb.Status_STATUS_DOWN:
+			reasons = append(reasons, "reported down")
+		case healthpb.Status_STATUS_DEGRADED:
+			reasons = append(reasons, "reported degraded")
+		}
+	}
+	if t == nil {
+		return status, reasons
+	}
+
+	worsen := func(to healthpb.Status, reason string) {
+		if statusSeverity[int32(to)] > statusSeverity[status] {
+			status = int32(to)
+		}
+		reasons = append(reasons, reason)
+	}
+	switch {
+	case t.DownLatencyMs > 0 && m.LatencyMs >= t.DownLatencyMs:
+		worsen(healthpb.Status_STATUS_DOWN, fmt.Sprintf("latency %dms >= %dms", m.LatencyMs, t.DownLatencyMs))
+	case t.DegradedLatencyMs > 0 && m.LatencyMs >= t.DegradedLatencyMs:
+		worsen(healthpb.Status_STATUS_DEGRADED, fmt.Sprintf("latency %dms >= %dms", m.LatencyMs, t.DegradedLatencyMs))
+	}
+	switch {
+	case t.DownErrorRate > 0 && m.ErrorRate >= t.DownErrorRate:
+		worsen(healthpb.Status_STATUS_DOWN, fmt.Sprintf("error rate %.1f%% >= %.1f%%", 100*m.ErrorRate, 100*t.DownErrorRate))
+	case t.DegradedErrorRate > 0 && m.ErrorRate >= t.DegradedErrorRate:
+		worsen(healthpb.Status_STATUS_DEGRADED, fmt.Sprintf("error rate %.1f%% >= %.1f%%", 100*m.ErrorRate, 100*t.DegradedErrorRate))
+	}
+	return status, reasons
+}
+
+// applyHysteresis keeps the previous status until enough consecutive
+// samples agree on a new one. Without Redis the streak cannot be counted
+// and the observed status applies at once.
+func (h *HealthServerImpl) applyHysteresis(ctx context.Context, t *HealthThresholds, prev HealthMetricModel, observed int32, reasons []string) (int32, []string) {
+	key := "health:streak:" + prev.ServiceID
+	if observed == prev.Status {
+		h.redis.Del(ctx, key)
+		return observed, reasons
+	}
+
+	need := int32(defaultRaiseAfter)
+	if t != nil && t.RaiseAfter > 0 {
+		need = t.RaiseAfter
+	}
+	if statusSeverity[observed] < statusSeverity[prev.Status] {
+		need = defaultClearAfter
+		if t != nil && t.ClearAfter > 0 {
+			need = t.ClearAfter
+		}
+	}
+
+	count, err := healthStreakScript.Run(ctx, h.redis, []string{key}, observed, need, healthStateStale.Milliseconds()).Int()
+	if err != nil || int32(count) >= need {
+		return observed, reasons
+	}
+	return prev.Status, append(reasons, fmt.Sprintf("%s for %d/%d samples", statusName(observed), count, need))
+}
+
+func thresholdsToProto(t *HealthThresholds) *catalogpb.HealthThresholds {
+	if t == nil {
+		return nil
+	}
+	return &catalogpb.HealthThresholds{
+		DegradedLatencyMs: t.DegradedLatencyMs,
+		DownLatencyMs:     t.DownLatencyMs,
+		DegradedErrorRate: t.DegradedErrorRate,
+		DownErrorRate:     t.DownErrorRate,
+		RaiseAfter:        t.RaiseAfter,
+		ClearAfter:        t.ClearAfter,
+	}
+}
+
+func thresholdsFromProto(p *catalogpb.HealthThresholds) *HealthThresholds {
+	if p == nil {
+		return nil
+	}
+	return &HealthThresholds{
+		DegradedLatencyMs: p.DegradedLatencyMs,
+		DownLatencyMs:     p.DownLatencyMs,
+		DegradedErrorRate: p.DegradedErrorRate,
+		DownErrorRate:     p.DownErrorRate,
+		RaiseAfter:        p.RaiseAfter,
+		ClearAfter:        p.ClearAfter,
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+)
+
+func TestClassifyHealth(t *testing.T) {
+	up := int32(healthpb.Status_STATUS_UP)
+	degraded := int32(healthpb.Status_STATUS_DEGRADED)
+	down := int32(healthpb.Status_STATUS_DOWN)
+	th := &HealthThresholds{DegradedLatencyMs: 200, DownLatencyMs: 1000, DegradedErrorRate: 0.05, DownErrorRate: 0.5}
+	tests := []struct {
+		name    string
+		t       *HealthThresholds
+		m       HealthMetricModel
+		want    int32
+		reasons int
+	}{
+		{"no thresholds", nil, HealthMetricModel{Status: up, LatencyMs: 5000}, up, 0},
+		{"reported down", nil, HealthMetricModel{Status: down}, down, 1},
+		{"healthy", th, HealthMetricModel{Status: up, LatencyMs: 100, ErrorRate: 0.01}, up, 0},
+		{"slow", th, HealthMetricModel{Status: up, LatencyMs: 200}, degraded, 1},
+		{"very slow", th, HealthMetricModel{Status: up, LatencyMs: 1000}, down, 1},
+		{"slow and failing", th, HealthMetricModel{Status: up, LatencyMs: 300, ErrorRate: 0.6}, down, 2},
+		{"reported worse than thresholds", th, HealthMetricModel{Status: down, LatencyMs: 300}, down, 2},
+	}
+	for _, tt := range tests {
+		got, reasons := classifyHealth(tt.t, tt.m)
+		if got != tt.want || len(reasons) != tt.reasons {
+			t.Errorf("%s: classifyHealth = %v, %q; want %v with %d reasons", tt.name, got, reasons, tt.want, tt.reasons)
+		}
+	}
+}
+
+func TestApplyHysteresis(t *testing.T) {
+	client, _ := newTestRedis(t)
+	h := &HealthServerImpl{redis: client}
+	ctx := context.Background()
+	up := int32(healthpb.Status_STATUS_UP)
+	degraded := int32(healthpb.Status_STATUS_DEGRADED)
+	down := int32(healthpb.Status_STATUS_DOWN)
+	th := &HealthThresholds{RaiseAfter: 3, ClearAfter: 2}
+
+	// Each step is one sample; prev is the status the previous one left.
+	steps := []struct {
+		prev, observed, want int32
+	}{
+		{up, degraded, up},
+		{up, degraded, up},
+		{up, degraded, degraded}, // third in a row raises
+		{degraded, up, degraded},
+		{degraded, degraded, degraded}, // agreeing sample ends the streak
+		{degraded, up, degraded},
+		{degraded, down, degraded}, // a different status starts over
+		{degraded, up, degraded},
+		{degraded, up, up}, // clear_after is 2
+	}
+	for i, s := range steps {
+		got, reasons := h.applyHysteresis(ctx, th, HealthMetricModel{ServiceID: "svc", Status: s.prev}, s.observed, nil)
+		if got != s.want {
+			t.Fatalf("step %d: applyHysteresis(%v -> %v) = %v, want %v", i, s.prev, s.observed, got, s.want)
+		}
+		if held := got != s.observed; held != (len(reasons) == 1 && strings.Contains(reasons[0], "samples")) {
+			t.Errorf("step %d: reasons %q", i, reasons)
+		}
+	}
+}
+
+func TestApplyHysteresisConcurrent(t *testing.T) {
+	client, mr := newTestRedis(t)
+	h := &HealthServerImpl{redis: client}
+	up := int32(healthpb.Status_STATUS_UP)
+	down := int32(healthpb.Status_STATUS_DOWN)
+
+	// Samples recorded at once on several instances must all count
+	const samples = 20
+	var wg sync.WaitGroup
+	for i := 0; i < samples; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.applyHysteresis(context.Background(), &HealthThresholds{RaiseAfter: 100}, HealthMetricModel{ServiceID: "svc", Status: up}, down, nil)
+		}()
+	}
+	wg.Wait()
+	raw, err := mr.Get("health:streak:svc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"count":20`; !strings.Contains(raw, want) {
+		t.Errorf("streak = %s, want %s", raw, want)
+	}
+}
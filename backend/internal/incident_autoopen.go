@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	incidentpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/incident/v1"
+	"gorm.io/gorm"
+)
+
+// IncidentAutoOpener opens an incident for any service whose samples have
+// all been DOWN for at least `after`, unless it already has an unresolved
+// incident that was opened automatically.
+type IncidentAutoOpener struct {
+	db        *gorm.DB
+	incidents *IncidentServerImpl
+	after     time.Duration
+}
+
+func NewIncidentAutoOpener(db *gorm.DB, incidents *IncidentServerImpl, after time.Duration) *IncidentAutoOpener {
+	return &IncidentAutoOpener{db: db, incidents: incidents, after: after}
+}
+
+// Run checks every service every interval until ctx is done.
+func (o *IncidentAutoOpener) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := o.check(ctx, now); err != nil {
+				Logger(ctx).Warn("incident auto-open check failed", "error", err)
+			}
+		}
+	}
+}
+
+func (o *IncidentAutoOpener) check(ctx context.Context, now time.Time) error {
+	db := o.db.WithContext(ctx)
+
+	var services []ServiceModel
+	if err := db.Find(&services).Error; err != nil {
+		return err
+	}
+	for _, svc := range services {
+		downSince, err := o.downSince(ctx, svc.ID, now)
+		if err != nil {
+			return err
+		}
+		if downSince.IsZero() || now.Sub(downSince) < o.after {
+			continue
+		}
+
+		var open int64
+		err = db.Model(&IncidentModel{}).
+			Joins("JOIN incident_service_models ON incident_service_models.incident_id = incident_models.id").
+			Where("incident_service_models.service_id = ? AND incident_models.auto_opened = ? AND incident_models.state <> ?",
+				svc.ID, true, int32(incidentpb.IncidentState_INCIDENT_STATE_RESOLVED)).
+			Count(&open).Error
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			continue
+		}
+
+		title := fmt.Sprintf("%s is down", svc.Name)
+		summary := fmt.Sprintf("Every health check of %s has reported DOWN since %s.", svc.ID, downSince.UTC().Format(time.RFC3339))
+		if _, err := o.incidents.create(ctx, "system", title, summary, []string{svc.ID}, incidentpb.Severity_SEVERITY_SEV2, true); err != nil {
+			return err
+		}
+		Logger(ctx).Warn("incident opened automatically", "service_id", svc.ID, "down_since", downSince)
+	}
+	return nil
+}
+
+// downSince returns the timestamp of the first DOWN sample after the last
+// sample that was not DOWN, or zero when the latest sample is not DOWN.
+func (o *IncidentAutoOpener) downSince(ctx context.Context, serviceID string, now time.Time) (time.Time, error) {
+	db := o.db.WithContext(ctx)
+	down := int32(healthpb.Status_STATUS_DOWN)
+
+	var latest HealthMetricModel
+	if err := db.Where("service_id = ?", serviceID).Order("timestamp desc").Limit(1).Find(&latest).Error; err != nil {
+		return time.Time{}, err
+	}
+	if latest.ID == 0 || latest.Status != down {
+		return time.Time{}, nil
+	}
+
+	var lastOK HealthMetricModel
+	if err := db.Where("service_id = ? AND status <> ?", serviceID, down).Order("timestamp desc").Limit(1).Find(&lastOK).Error; err != nil {
+		return time.Time{}, err
+	}
+	var firstDown HealthMetricModel
+	if err := db.Where("service_id = ? AND status = ? AND timestamp > ?", serviceID, down, lastOK.Timestamp).Order("timestamp").Limit(1).Find(&firstDown).Error; err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(firstDown.Timestamp), nil
+}
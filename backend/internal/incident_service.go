@@ -0,0 +1,295 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	incidentpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/incident/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// incidentsTopic is the Hub topic carrying marshaled incidentpb.Incident
+// messages whenever an incident changes.
+const incidentsTopic = "incidents"
+
+type IncidentServerImpl struct {
+	incidentpb.UnimplementedIncidentServiceServer
+	db  *gorm.DB
+	hub *Hub
+}
+
+func NewIncidentServer(db *gorm.DB, hub *Hub) *IncidentServerImpl {
+	return &IncidentServerImpl{db: db, hub: hub}
+}
+
+// CreateIncident opens an incident on behalf of the calling principal
+func (s *IncidentServerImpl) CreateIncident(ctx context.Context, req *incidentpb.CreateIncidentRequest) (*incidentpb.CreateIncidentResponse, error) {
+	if req.Title == "" {
+		return nil, status.Error(codes.InvalidArgument, "title is required")
+	}
+	if len(req.ServiceIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one service_id is required")
+	}
+	var known int64
+	if err := s.db.WithContext(ctx).Model(&ServiceModel{}).Where("id IN ?", req.ServiceIds).Count(&known).Error; err != nil {
+		return nil, err
+	}
+	if int(known) != len(req.ServiceIds) {
+		return nil, status.Error(codes.NotFound, "unknown service_id")
+	}
+	severity := req.Severity
+	if severity == incidentpb.Severity_SEVERITY_UNSPECIFIED {
+		severity = incidentpb.Severity_SEVERITY_SEV3
+	}
+
+	inc, err := s.create(ctx, actorFromContext(ctx), req.Title, req.Summary, req.ServiceIds, severity, false)
+	if err != nil {
+		return nil, err
+	}
+	return &incidentpb.CreateIncidentResponse{Incident: inc}, nil
+}
+
+func (s *IncidentServerImpl) create(ctx context.Context, actor, title, summary string, serviceIDs []string, severity incidentpb.Severity, autoOpened bool) (*incidentpb.Incident, error) {
+	m := IncidentModel{
+		ID:         newID(),
+		Title:      title,
+		Summary:    summary,
+		Severity:   int32(severity),
+		State:      int32(incidentpb.IncidentState_INCIDENT_STATE_OPEN),
+		CreatedBy:  actor,
+		AutoOpened: autoOpened,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&m).Error; err != nil {
+			return err
+		}
+		links := make([]IncidentServiceModel, len(serviceIDs))
+		for i, id := range serviceIDs {
+			links[i] = IncidentServiceModel{IncidentID: m.ID, ServiceID: id}
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+		return tx.Create(&IncidentEventModel{
+			ID:         newID(),
+			IncidentID: m.ID,
+			At:         time.Now().UnixMilli(),
+			Actor:      actor,
+			Kind:       "created",
+			ToState:    m.State,
+			Note:       summary,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.publish(ctx, m.ID)
+}
+
+// UpdateIncident applies a state transition, severity change and/or note
+func (s *IncidentServerImpl) UpdateIncident(ctx context.Context, req *incidentpb.UpdateIncidentRequest) (*incidentpb.UpdateIncidentResponse, error) {
+	actor := actorFromContext(ctx)
+	now := time.Now().UnixMilli()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m IncidentModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&m, "id = ?", req.Id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return status.Errorf(codes.NotFound, "incident %q not found", req.Id)
+			}
+			return err
+		}
+
+		var events []IncidentEventModel
+		from := incidentpb.IncidentState(m.State)
+		if req.State != incidentpb.IncidentState_INCIDENT_STATE_UNSPECIFIED && req.State != from {
+			if !validIncidentTransition(from, req.State) {
+				return status.Errorf(codes.FailedPrecondition, "cannot move incident from %s to %s", from, req.State)
+			}
+			m.State = int32(req.State)
+			m.ResolvedAt = 0
+			if req.State == incidentpb.IncidentState_INCIDENT_STATE_RESOLVED {
+				m.ResolvedAt = now
+			}
+			events = append(events, IncidentEventModel{Kind: "state_changed", FromState: int32(from), ToState: m.State})
+		}
+		if req.Severity != incidentpb.Severity_SEVERITY_UNSPECIFIED && int32(req.Severity) != m.Severity {
+			events = append(events, IncidentEventModel{Kind: "severity_changed", Note: incidentpb.Severity(m.Severity).String() + " -> " + req.Severity.String()})
+			m.Severity = int32(req.Severity)
+		}
+		if req.Note != "" {
+			events = append(events, IncidentEventModel{Kind: "note", Note: req.Note})
+		}
+		if len(events) == 0 {
+			return status.Error(codes.InvalidArgument, "nothing to update")
+		}
+
+		for i := range events {
+			events[i].ID = newID()
+			events[i].IncidentID = m.ID
+			events[i].At = now
+			events[i].Actor = actor
+		}
+		if err := tx.Save(&m).Error; err != nil {
+			return err
+		}
+		return tx.Create(&events).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inc, err := s.publish(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &incidentpb.UpdateIncidentResponse{Incident: inc}, nil
+}
+
+// validIncidentTransition allows moving forward through the lifecycle, and
+// reopening a resolved incident.
+func validIncidentTransition(from, to incidentpb.IncidentState) bool {
+	if from == incidentpb.IncidentState_INCIDENT_STATE_RESOLVED {
+		return to == incidentpb.IncidentState_INCIDENT_STATE_OPEN
+	}
+	return to > from && to <= incidentpb.IncidentState_INCIDENT_STATE_RESOLVED
+}
+
+// GetIncident returns one incident with its timeline
+func (s *IncidentServerImpl) GetIncident(ctx context.Context, req *incidentpb.GetIncidentRequest) (*incidentpb.GetIncidentResponse, error) {
+	inc, err := s.load(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &incidentpb.GetIncidentResponse{Incident: inc}, nil
+}
+
+// ListIncidents returns incidents, newest first
+func (s *IncidentServerImpl) ListIncidents(ctx context.Context, req *incidentpb.ListIncidentsRequest) (*incidentpb.ListIncidentsResponse, error) {
+	q := s.db.WithContext(ctx).Model(&IncidentModel{}).Order("created_at desc")
+	if req.ServiceId != "" {
+		q = q.Where("id IN (?)", s.db.Model(&IncidentServiceModel{}).Select("incident_id").Where("service_id = ?", req.ServiceId))
+	}
+	if !req.IncludeResolved {
+		q = q.Where("state <> ?", int32(incidentpb.IncidentState_INCIDENT_STATE_RESOLVED))
+	}
+	var ids []string
+	if err := q.Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &incidentpb.ListIncidentsResponse{}
+	for _, id := range ids {
+		inc, err := s.load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		resp.Incidents = append(resp.Incidents, inc)
+	}
+	return resp, nil
+}
+
+// WatchIncidents streams every unresolved incident, then every change
+func (s *IncidentServerImpl) WatchIncidents(req *incidentpb.WatchIncidentsRequest, stream incidentpb.IncidentService_WatchIncidentsServer) error {
+	ctx := stream.Context()
+
+	// Subscribe before taking the snapshot so no change is missed in between
+	updates, unsubscribe := s.hub.Subscribe(incidentsTopic)
+	defer unsubscribe()
+
+	current, err := s.ListIncidents(ctx, &incidentpb.ListIncidentsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, inc := range current.Incidents {
+		if err := stream.Send(&incidentpb.WatchIncidentsResponse{Incident: inc}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case payload := <-updates:
+			var inc incidentpb.Incident
+			if err := proto.Unmarshal(payload, &inc); err != nil {
+				continue
+			}
+			if err := stream.Send(&incidentpb.WatchIncidentsResponse{Incident: &inc}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish loads the current state of an incident and broadcasts it to watchers.
+func (s *IncidentServerImpl) publish(ctx context.Context, id string) (*incidentpb.Incident, error) {
+	inc, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payload, err := proto.Marshal(inc); err == nil {
+		s.hub.Publish(ctx, incidentsTopic, payload)
+	}
+	return inc, nil
+}
+
+func (s *IncidentServerImpl) load(ctx context.Context, id string) (*incidentpb.Incident, error) {
+	db := s.db.WithContext(ctx)
+
+	var m IncidentModel
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "incident %q not found", id)
+		}
+		return nil, err
+	}
+	var serviceIDs []string
+	if err := db.Model(&IncidentServiceModel{}).Where("incident_id = ?", id).Order("service_id").Pluck("service_id", &serviceIDs).Error; err != nil {
+		return nil, err
+	}
+	var events []IncidentEventModel
+	if err := db.Where("incident_id = ?", id).Order("at, id").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	inc := &incidentpb.Incident{
+		Id:           m.ID,
+		Title:        m.Title,
+		Summary:      m.Summary,
+		ServiceIds:   serviceIDs,
+		Severity:     incidentpb.Severity(m.Severity),
+		State:        incidentpb.IncidentState(m.State),
+		CreatedBy:    m.CreatedBy,
+		CreatedAtMs:  m.CreatedAt,
+		UpdatedAtMs:  m.UpdatedAt,
+		ResolvedAtMs: m.ResolvedAt,
+		AutoOpened:   m.AutoOpened,
+	}
+	for _, e := range events {
+		inc.Timeline = append(inc.Timeline, &incidentpb.TimelineEntry{
+			Id:        e.ID,
+			AtMs:      e.At,
+			Actor:     e.Actor,
+			Kind:      e.Kind,
+			FromState: incidentpb.IncidentState(e.FromState),
+			ToState:   incidentpb.IncidentState(e.ToState),
+			Note:      e.Note,
+		})
+	}
+	return inc, nil
+}
+
+// actorFromContext is the principal recorded on timeline entries.
+func actorFromContext(ctx context.Context) string {
+	if p := PrincipalFromContext(ctx); p != "" {
+		return p
+	}
+	return "system"
+}
@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// LDAPConfig configures authentication against an LDAP directory.
+type LDAPConfig struct {
+	URL string // ldap://host:389, or ldaps://host:636
+	// StartTLS upgrades an ldap:// connection before anything is sent.
+	StartTLS  bool
+	TLSConfig *tls.Config
+	// BindDN and BindPassword are the service account used to find users
+	// and their groups; empty for an anonymous search.
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	// UserFilter finds the user; %s is the escaped username.
+	UserFilter string
+	// GroupBaseDN and GroupFilter find the user's groups, %s being the
+	// escaped user DN, and GroupAttribute names them. Without a group
+	// filter the user's memberOf attribute is read instead.
+	GroupBaseDN    string
+	GroupFilter    string
+	GroupAttribute string
+	EmailAttribute string
+	// GroupRoles maps directory groups to local roles.
+	GroupRoles map[string][]string
+	Timeout    time.Duration
+}
+
+// LDAPAuthenticator checks passwords by binding as the user. Users are
+// provisioned locally on their first login, keyed by their DN, and their
+// roles follow their groups on every login. A user whose groups map to no
+// role gets none, and checkRole then only lets them read.
+type LDAPAuthenticator struct {
+	cfg      LDAPConfig
+	accounts *Accounts
+}
+
+func NewLDAPAuthenticator(cfg LDAPConfig, accounts *Accounts) *LDAPAuthenticator {
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = "(&(objectClass=person)(uid=%s))"
+	}
+	if cfg.GroupBaseDN == "" {
+		cfg.GroupBaseDN = cfg.BaseDN
+	}
+	if cfg.GroupAttribute == "" {
+		cfg.GroupAttribute = "cn"
+	}
+	if cfg.EmailAttribute == "" {
+		cfg.EmailAttribute = "mail"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.TLSConfig == nil {
+		cfg.TLSConfig = &tls.Config{}
+	}
+	return &LDAPAuthenticator{cfg: cfg, accounts: accounts}
+}
+
+func (l *LDAPAuthenticator) dial() (*ldap.Conn, error) {
+	tlsConfig := l.cfg.TLSConfig.Clone()
+	if u, err := url.Parse(l.cfg.URL); err == nil && tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+	conn, err := ldap.DialURL(l.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: l.cfg.Timeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(l.cfg.Timeout)
+	if l.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("StartTLS: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// bindService binds as the service account, or stays anonymous without one.
+func (l *LDAPAuthenticator) bindService(conn *ldap.Conn) error {
+	if l.cfg.BindDN == "" {
+		return nil
+	}
+	return conn.Bind(l.cfg.BindDN, l.cfg.BindPassword)
+}
+
+func (l *LDAPAuthenticator) search(conn *ldap.Conn, base, filter string, attrs []string, limit int) ([]*ldap.Entry, error) {
+	res, err := conn.Search(ldap.NewSearchRequest(base, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		limit, int(l.cfg.Timeout/time.Second), false, filter, attrs, nil))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, err
+	}
+	if res == nil {
+		return nil, err
+	}
+	return res.Entries, nil
+}
+
+// Authenticate finds the user's DN, binds as it with the password, reads
+// the user's groups and returns the local user.
+func (l *LDAPAuthenticator) Authenticate(ctx context.Context, username, password string) (*UserModel, error) {
+	// An empty password would be an unauthenticated bind, which succeeds.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	conn, err := l.dial()
+	if err != nil {
+		return nil, fmt.Errorf("ldap: %w", err)
+	}
+	defer conn.Close()
+
+	if err := l.bindService(conn); err != nil {
+		return nil, fmt.Errorf("ldap: service bind: %w", err)
+	}
+	entries, err := l.search(conn, l.cfg.BaseDN, fmt.Sprintf(l.cfg.UserFilter, ldap.EscapeFilter(username)),
+		[]string{l.cfg.EmailAttribute, "memberOf"}, 2)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: user search: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+	if len(entries) > 1 {
+		return nil, fmt.Errorf("ldap: %q matches more than one entry", username)
+	}
+	user := entries[0]
+
+	if err := conn.Bind(user.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldap: user bind: %w", err)
+	}
+
+	groups, err := l.groups(conn, user)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: group search: %w", err)
+	}
+	roles := mapGroupRoles(l.cfg.GroupRoles, groups)
+	u, err := l.accounts.ProvisionExternal(ctx, "ldap", user.DN, username, user.GetAttributeValue(l.cfg.EmailAttribute), roles)
+	if errors.Is(err, ErrUsernameTaken) {
+		// A local account of the same name is left to the local store.
+		Logger(ctx).Warn("LDAP user shadowed by a local account", "username", username, "dn", user.DN)
+		return nil, ErrInvalidCredentials
+	}
+	return u, err
+}
+
+// groups lists the user's group names, by GroupFilter or from memberOf.
+func (l *LDAPAuthenticator) groups(conn *ldap.Conn, user *ldap.Entry) ([]string, error) {
+	if l.cfg.GroupFilter == "" {
+		var groups []string
+		for _, dn := range user.GetAttributeValues("memberOf") {
+			if name := firstRDNValue(dn, l.cfg.GroupAttribute); name != "" {
+				groups = append(groups, name)
+			}
+		}
+		return groups, nil
+	}
+	// Some directories only let the service account read groups.
+	if err := l.bindService(conn); err != nil {
+		return nil, err
+	}
+	entries, err := l.search(conn, l.cfg.GroupBaseDN, fmt.Sprintf(l.cfg.GroupFilter, ldap.EscapeFilter(user.DN)),
+		[]string{l.cfg.GroupAttribute}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var groups []string
+	for _, e := range entries {
+		groups = append(groups, e.GetAttributeValues(l.cfg.GroupAttribute)...)
+	}
+	return groups, nil
+}
+
+// firstRDNValue returns the attr value of the first RDN of dn, e.g. "ops"
+// for cn=ops,ou=groups,dc=example,dc=com.
+func firstRDNValue(dn, attr string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ""
+	}
+	for _, a := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(a.Type, attr) {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package internal
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLDAP is an in-process LDAP server that answers simple binds against
+// passwords and searches from a table of filters, as the directory would.
+// Searches need the service account's bind.
+type fakeLDAP struct {
+	addr      string
+	serviceDN string
+	passwords map[string]string        // DN to password
+	searches  map[string][]*ldap.Entry // filter to result
+
+	mu      sync.Mutex
+	filters []string
+}
+
+func newFakeLDAP(t *testing.T) *fakeLDAP {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	f := &fakeLDAP{
+		addr:      ln.Addr().String(),
+		serviceDN: "cn=svc,dc=example,dc=com",
+		passwords: map[string]string{
+			"cn=svc,dc=example,dc=com":              "svc-secret",
+			"uid=alice,ou=people,dc=example,dc=com": "alice-secret",
+			"uid=carol,ou=people,dc=example,dc=com": "carol-secret",
+			"uid=bob,ou=people,dc=example,dc=com":   "bob-secret",
+		},
+		searches: map[string][]*ldap.Entry{
+			"(&(objectClass=person)(uid=alice))": {ldap.NewEntry("uid=alice,ou=people,dc=example,dc=com", map[string][]string{
+				"mail":     {"alice@example.com"},
+				"memberOf": {"cn=sre,ou=groups,dc=example,dc=com", "cn=everyone,ou=groups,dc=example,dc=com"},
+			})},
+			"(&(objectClass=person)(uid=carol))": {ldap.NewEntry("uid=carol,ou=people,dc=example,dc=com", map[string][]string{
+				"memberOf": {"cn=everyone,ou=groups,dc=example,dc=com"},
+			})},
+			"(&(objectClass=person)(uid=bob))": {ldap.NewEntry("uid=bob,ou=people,dc=example,dc=com", nil)},
+			"(&(objectClass=person)(uid=dup))": {ldap.NewEntry("uid=dup,ou=a", nil), ldap.NewEntry("uid=dup,ou=b", nil)},
+			"(member=uid=alice,ou=people,dc=example,dc=com)": {
+				ldap.NewEntry("cn=platform,ou=groups,dc=example,dc=com", map[string][]string{"cn": {"platform"}}),
+				ldap.NewEntry("cn=sre,ou=groups,dc=example,dc=com", map[string][]string{"cn": {"sre"}}),
+			},
+		},
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeLDAP) serve(conn net.Conn) {
+	defer conn.Close()
+	var bound string
+	for {
+		req, err := ber.ReadPacket(conn)
+		if err != nil || len(req.Children) < 2 {
+			return
+		}
+		id := req.Children[0].Value.(int64)
+		op := req.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := op.Children[1].Value.(string)
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			if want, ok := f.passwords[dn]; ok && want == op.Children[2].Data.String() {
+				code, bound = ldap.LDAPResultSuccess, dn
+			}
+			f.reply(conn, id, ldap.ApplicationBindResponse, code)
+		case ldap.ApplicationSearchRequest:
+			filter, _ := ldap.DecompileFilter(op.Children[6])
+			f.mu.Lock()
+			f.filters = append(f.filters, filter)
+			f.mu.Unlock()
+			if bound != f.serviceDN {
+				f.reply(conn, id, ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights)
+				continue
+			}
+			for _, e := range f.searches[filter] {
+				entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+				entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, ""))
+				attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+				for _, a := range e.Attributes {
+					attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+					attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, a.Name, ""))
+					values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+					for _, v := range a.Values {
+						values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, ""))
+					}
+					attr.AppendChild(values)
+					attrs.AppendChild(attr)
+				}
+				entry.AppendChild(attrs)
+				f.send(conn, id, entry)
+			}
+			f.reply(conn, id, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+// reply sends an LDAPResult, the body of bind and search done responses.
+func (f *fakeLDAP) reply(conn net.Conn, id int64, tag ber.Tag, code uint16) {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), ""))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	f.send(conn, id, res)
+}
+
+func (f *fakeLDAP) send(conn net.Conn, id int64, op *ber.Packet) {
+	msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+	msg.AppendChild(op)
+	conn.Write(msg.Bytes())
+}
+
+func TestLDAPAuthenticate(t *testing.T) {
+	f := newFakeLDAP(t)
+	groupRoles := map[string][]string{"sre": {roleOperator}, "platform": {roleAdmin}}
+	tests := []struct {
+		name        string
+		groupFilter string
+		bindPass    string
+		username    string
+		password    string
+		roles       []string
+		err         error // nil for a login, errAny for any other error
+	}{
+		{name: "memberOf groups", username: "alice", password: "alice-secret", roles: []string{roleOperator}},
+		{name: "group search", groupFilter: "(member=%s)", username: "alice", password: "alice-secret", roles: []string{roleAdmin, roleOperator}},
+		{name: "no mapped group", username: "carol", password: "carol-secret"},
+		{name: "wrong password", username: "alice", password: "guess", err: ErrInvalidCredentials},
+		{name: "empty password", username: "alice", password: "", err: ErrInvalidCredentials},
+		{name: "unknown user", username: "mallory", password: "x", err: ErrInvalidCredentials},
+		{name: "filter injection", username: "*", password: "x", err: ErrInvalidCredentials},
+		{name: "ambiguous user", username: "dup", password: "x", err: errAny},
+		{name: "shadowed by a local user", username: "bob", password: "bob-secret", err: ErrInvalidCredentials},
+		{name: "service bind fails", bindPass: "wrong", username: "alice", password: "alice-secret", err: errAny},
+	}
+	accounts, db := newTestAccounts(t, nil)
+	if err := db.Create(&UserModel{ID: "local-bob", Username: "bob"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bindPass := tt.bindPass
+			if bindPass == "" {
+				bindPass = "svc-secret"
+			}
+			l := NewLDAPAuthenticator(LDAPConfig{
+				URL:          "ldap://" + f.addr,
+				BindDN:       f.serviceDN,
+				BindPassword: bindPass,
+				BaseDN:       "dc=example,dc=com",
+				GroupFilter:  tt.groupFilter,
+				GroupRoles:   groupRoles,
+			}, accounts)
+			u, err := l.Authenticate(t.Context(), tt.username, tt.password)
+			switch {
+			case tt.err == errAny:
+				if err == nil || errors.Is(err, ErrInvalidCredentials) {
+					t.Fatalf("Authenticate = %v, want a directory error", err)
+				}
+				return
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Authenticate = %v, want %v", err, tt.err)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+			if u.Username != tt.username || u.Provider != "ldap" || !slices.Equal(u.Roles, tt.roles) {
+				t.Errorf("user %s from %s with roles %q, want %s with %q", u.Username, u.Provider, u.Roles, tt.username, tt.roles)
+			}
+			// Users the group mapping gives no role can only read
+			claims := &AccessClaims{Roles: u.Roles}
+			err = checkRole(claims, "/catalog.v1.CatalogService/CreateService")
+			if len(tt.roles) == 0 && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("user without roles may write: %v", err)
+			}
+			if err := checkRole(claims, "/catalog.v1.CatalogService/ListServices"); err != nil {
+				t.Errorf("user may not read: %v", err)
+			}
+		})
+	}
+
+	// Usernames are escaped in filters
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, filter := range f.filters {
+		if filter == "(&(objectClass=person)(uid=*))" {
+			t.Errorf("username was not escaped: %s", filter)
+		}
+	}
+}
+
+// errAny stands for any error that is not ErrInvalidCredentials.
+var errAny = errors.New("any error")
+
+func TestLDAPUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	accounts, _ := newTestAccounts(t, nil)
+	l := NewLDAPAuthenticator(LDAPConfig{URL: "ldap://" + addr}, accounts)
+	if _, err := l.Authenticate(t.Context(), "alice", "alice-secret"); err == nil || errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate = %v, want a directory error", err)
+	}
+}
+
+func TestFirstRDNValue(t *testing.T) {
+	tests := []struct {
+		dn, attr, want string
+	}{
+		{"cn=ops,ou=groups,dc=example,dc=com", "cn", "ops"},
+		{"CN=Site Reliability,OU=Groups", "cn", "Site Reliability"},
+		{"ou=groups,dc=example", "cn", ""},
+		{"not a dn", "cn", ""},
+	}
+	for _, tt := range tests {
+		if got := firstRDNValue(tt.dn, tt.attr); got != tt.want {
+			t.Errorf("firstRDNValue(%q, %q) = %q, want %q", tt.dn, tt.attr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the header Envoy uses to correlate a request across hops.
+const RequestIDHeader = "x-request-id"
+
+// LogLevel is the minimum level of loggers built by NewLogger. It can be
+// changed at runtime through LogLevelHandler.
+var LogLevel = new(slog.LevelVar)
+
+// NewLogger returns a JSON logger whose level follows LogLevel.
+func NewLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: LogLevel}))
+}
+
+// requestInfo is attached to the context of every request. It is a pointer so
+// that inner interceptors (e.g. JWT) can fill in the principal and the outer
+// logging interceptor still sees it once the handler returns.
+type requestInfo struct {
+	RequestID string
+	Principal string
+	Roles     []string
+	Scopes    []string
+	SessionID string
+}
+
+type requestInfoKey struct{}
+
+func contextWithRequestInfo(ctx context.Context, info *requestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*requestInfo)
+	return info
+}
+
+// RequestIDFromContext returns the request ID of the current call, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	if info := requestInfoFromContext(ctx); info != nil {
+		return info.RequestID
+	}
+	return ""
+}
+
+// PrincipalFromContext returns the authenticated subject of the current call, if any.
+func PrincipalFromContext(ctx context.Context) string {
+	if info := requestInfoFromContext(ctx); info != nil {
+		return info.Principal
+	}
+	return ""
+}
+
+// SetPrincipal records the authenticated subject on a context that already
+// carries request info, e.g. one prepared by RequestLogger.
+func SetPrincipal(ctx context.Context, principal string) {
+	if info := requestInfoFromContext(ctx); info != nil {
+		info.Principal = principal
+	}
+}
+
+// withClaims records the authenticated subject, its roles or scopes and its
+// session on the request info, attaching a new one when the call did not
+// pass through a logging interceptor.
+func withClaims(ctx context.Context, claims *AccessClaims) context.Context {
+	if info := requestInfoFromContext(ctx); info != nil {
+		info.Principal, info.Roles, info.Scopes, info.SessionID = claims.Subject, claims.Roles, strings.Fields(claims.Scope), claims.ID
+		return ctx
+	}
+	return contextWithRequestInfo(ctx, &requestInfo{
+		Principal: claims.Subject,
+		Roles:     claims.Roles,
+		Scopes:    strings.Fields(claims.Scope),
+		SessionID: claims.ID,
+	})
+}
+
+// sessionIDFromContext returns the session of the current call, if any.
+func sessionIDFromContext(ctx context.Context) string {
+	if info := requestInfoFromContext(ctx); info != nil {
+		return info.SessionID
+	}
+	return ""
+}
+
+// HasRole reports whether the authenticated principal of ctx has role.
+func HasRole(ctx context.Context, role string) bool {
+	if info := requestInfoFromContext(ctx); info != nil {
+		return slices.Contains(info.Roles, role)
+	}
+	return false
+}
+
+// Logger returns the default logger annotated with the request ID and
+// principal of ctx.
+func Logger(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if info := requestInfoFromContext(ctx); info != nil {
+		if info.RequestID != "" {
+			logger = logger.With("request_id", info.RequestID)
+		}
+		if info.Principal != "" {
+			logger = logger.With("principal", info.Principal)
+		}
+	}
+	return logger
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDHeader); len(ids) > 0 && ids[0] != "" {
+			return ids[0]
+		}
+	}
+	return newRequestID()
+}
+
+// wrappedStream overrides the context of a grpc.ServerStream.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+// LoggingInterceptor logs every unary call with its method, principal,
+// duration, status code and peer, and propagates the request ID.
+func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		reqInfo := &requestInfo{RequestID: incomingRequestID(ctx)}
+		ctx = contextWithRequestInfo(ctx, reqInfo)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, reqInfo.RequestID))
+
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, reqInfo, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor is the streaming counterpart of LoggingInterceptor.
+func LoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		reqInfo := &requestInfo{RequestID: incomingRequestID(ss.Context())}
+		ctx := contextWithRequestInfo(ss.Context(), reqInfo)
+		ss.SetHeader(metadata.Pairs(RequestIDHeader, reqInfo.RequestID))
+
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, reqInfo, start, err)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method string, info *requestInfo, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, "grpc call",
+		slog.String("request_id", info.RequestID),
+		slog.String("method", method),
+		slog.String("principal", info.Principal),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("code", code.String()),
+		slog.String("peer", grpcClientIP(ctx)),
+	)
+}
+
+// RequestLogger is chi middleware that logs every HTTP request with its
+// route, principal, duration, status code and peer, and propagates the
+// request ID.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			reqInfo := &requestInfo{RequestID: r.Header.Get(RequestIDHeader)}
+			if reqInfo.RequestID == "" {
+				reqInfo.RequestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, reqInfo.RequestID)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			r = r.WithContext(contextWithRequestInfo(r.Context(), reqInfo))
+			next.ServeHTTP(ww, r)
+
+			route := r.URL.Path
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			level := slog.LevelInfo
+			if ww.Status() >= http.StatusInternalServerError {
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(r.Context(), level, "http request",
+				slog.String("request_id", reqInfo.RequestID),
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("principal", reqInfo.Principal),
+				slog.Duration("duration", time.Since(start)),
+				slog.Int("status", ww.Status()),
+				slog.String("peer", HTTPClientIP(r)),
+			)
+		})
+	}
+}
+
+// LogLevelHandler reports the current log level on GET and changes it on
+// PUT, e.g. `{"level":"debug"}`.
+func LogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		var body struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(body.Level)); err != nil {
+			http.Error(w, "Invalid log level", http.StatusBadRequest)
+			return
+		}
+		AuditChange(r.Context(), "level", LogLevel.Level().String(), level.String())
+		LogLevel.Set(level)
+		Logger(r.Context()).Info("log level changed", "level", level.String())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"level": LogLevel.Level().String()})
+}
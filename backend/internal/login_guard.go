@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// loginFailureWindow is how long failed attempts are remembered.
+	loginFailureWindow = 24 * time.Hour
+	loginLockoutBase   = 30 * time.Second
+	loginLockoutMax    = time.Hour
+)
+
+// loginLockAfter is how many failures from one username or one IP are
+// tolerated before lockouts start. An IP gets more, since it may be shared.
+var loginLockAfter = map[string]int64{"user": 5, "ip": 20}
+
+// LoginGuard locks out usernames and client IPs after repeated failed
+// logins. Each failure past the threshold doubles the lockout, from 30s up
+// to an hour; a successful login clears the username's failures. While
+// Redis is unavailable nobody is locked out.
+type LoginGuard struct {
+	redis *redis.Client
+}
+
+func NewLoginGuard(redisClient *redis.Client) *LoginGuard {
+	return &LoginGuard{redis: redisClient}
+}
+
+func loginGuardKeys(ip, username string) map[string]string {
+	return map[string]string{"user": strings.ToLower(username), "ip": ip}
+}
+
+// Locked returns how long the username or IP is still locked out for.
+func (g *LoginGuard) Locked(ctx context.Context, ip, username string) time.Duration {
+	var wait time.Duration
+	for kind, subject := range loginGuardKeys(ip, username) {
+		if ttl, err := g.redis.PTTL(ctx, "login:lock:"+kind+":"+subject).Result(); err == nil && ttl > wait {
+			wait = ttl
+		}
+	}
+	return wait
+}
+
+// Failed records a failed login and returns the lockout it started, if any.
+func (g *LoginGuard) Failed(ctx context.Context, ip, username string) time.Duration {
+	var lockout time.Duration
+	for kind, subject := range loginGuardKeys(ip, username) {
+		key := "login:failures:" + kind + ":" + subject
+		n, err := g.redis.Incr(ctx, key).Result()
+		if err != nil {
+			return 0
+		}
+		g.redis.Expire(ctx, key, loginFailureWindow)
+		if over := n - loginLockAfter[kind]; over >= 0 {
+			d := min(loginLockoutBase<<min(over, 10), loginLockoutMax)
+			g.redis.Set(ctx, "login:lock:"+kind+":"+subject, 1, d)
+			lockout = max(lockout, d)
+		}
+	}
+	if lockout > 0 {
+		Logger(ctx).Warn("login locked out", "username", username, "ip", ip, "lockout", lockout)
+	}
+	return lockout
+}
+
+// Succeeded clears the failures of a username.
+func (g *LoginGuard) Succeeded(ctx context.Context, username string) {
+	g.redis.Del(ctx, "login:failures:user:"+strings.ToLower(username))
+}
@@ -0,0 +1,9 @@
+package internal
+
+import "context"
+
+// Mailer sends the emails of account self-service, such as password reset
+// links. SMTPSender is the production Mailer.
+type Mailer interface {
+	SendMail(ctx context.Context, to, subject, body string) error
+}
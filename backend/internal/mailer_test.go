@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"context"
+	"sync"
+)
+
+// sentMail is one email kept by memoryMailer.
+type sentMail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// memoryMailer keeps emails in memory instead of sending them.
+type memoryMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+}
+
+func (m *memoryMailer) SendMail(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentMail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Sent returns the emails sent so far, oldest first.
+func (m *memoryMailer) Sent() []sentMail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]sentMail(nil), m.sent...)
+}
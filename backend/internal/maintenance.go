@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maintenanceRefresh is how long MaintenanceSchedule trusts its copy of the
+// windows table; writes through MaintenanceServerImpl refresh it immediately.
+const maintenanceRefresh = 30 * time.Second
+
+// Covers reports whether the window applies to svc.
+func (w MaintenanceWindowModel) Covers(svc ServiceModel) bool {
+	for _, id := range w.ServiceIDs {
+		if id == svc.ID {
+			return true
+		}
+	}
+	return len(w.Selector) > 0 && labelsMatch(w.Selector, svc.Labels)
+}
+
+// Occurrence returns the start and end of the occurrence in progress at t,
+// or else of the next one. ok is false when the window has no occurrence
+// ending after t.
+func (w MaintenanceWindowModel) Occurrence(t time.Time) (start, end time.Time, ok bool) {
+	start, end = time.UnixMilli(w.StartsAt).UTC(), time.UnixMilli(w.EndsAt).UTC()
+	if w.RRule == "" {
+		return start, end, end.After(t)
+	}
+
+	rule, err := ParseRRule(w.RRule)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	length := end.Sub(start)
+	rule.Each(start, func(s time.Time) bool {
+		if s.Add(length).After(t) {
+			start, end, ok = s, s.Add(length), true
+			return false
+		}
+		return true
+	})
+	return start, end, ok
+}
+
+// ActiveAt reports whether an occurrence of the window is in progress at t.
+func (w MaintenanceWindowModel) ActiveAt(t time.Time) bool {
+	if w.Cancelled {
+		return false
+	}
+	start, _, ok := w.Occurrence(t)
+	return ok && !start.After(t)
+}
+
+// MaintenanceSchedule answers "is this service in maintenance right now"
+// from a periodically refreshed in-memory copy of the windows table, so it
+// can be asked on every health sample and alert evaluation.
+type MaintenanceSchedule struct {
+	db *gorm.DB
+
+	mu       sync.Mutex
+	windows  []MaintenanceWindowModel
+	loadedAt time.Time
+}
+
+func NewMaintenanceSchedule(db *gorm.DB) *MaintenanceSchedule {
+	return &MaintenanceSchedule{db: db}
+}
+
+// Active returns the window covering svc at t, or nil.
+func (m *MaintenanceSchedule) Active(ctx context.Context, svc ServiceModel, t time.Time) *MaintenanceWindowModel {
+	for _, w := range m.current(ctx) {
+		if w.Covers(svc) && w.ActiveAt(t) {
+			return &w
+		}
+	}
+	return nil
+}
+
+// Invalidate forces the next lookup to reload the windows.
+func (m *MaintenanceSchedule) Invalidate() {
+	m.mu.Lock()
+	m.loadedAt = time.Time{}
+	m.mu.Unlock()
+}
+
+func (m *MaintenanceSchedule) current(ctx context.Context) []MaintenanceWindowModel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if time.Since(m.loadedAt) < maintenanceRefresh {
+		return m.windows
+	}
+	var windows []MaintenanceWindowModel
+	if err := m.db.WithContext(ctx).Where("cancelled = ?", false).Find(&windows).Error; err != nil {
+		// Keep using the last copy while Postgres is unavailable
+		Logger(ctx).Warn("failed to load maintenance windows", "error", err)
+		return m.windows
+	}
+	m.windows = windows
+	m.loadedAt = time.Now()
+	return m.windows
+}
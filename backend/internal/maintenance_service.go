@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	maintenancepb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/maintenance/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type MaintenanceServerImpl struct {
+	maintenancepb.UnimplementedMaintenanceServiceServer
+	db       *gorm.DB
+	schedule *MaintenanceSchedule
+}
+
+func NewMaintenanceServer(db *gorm.DB, schedule *MaintenanceSchedule) *MaintenanceServerImpl {
+	return &MaintenanceServerImpl{db: db, schedule: schedule}
+}
+
+// ScheduleMaintenance validates and stores a one-off or recurring window
+func (s *MaintenanceServerImpl) ScheduleMaintenance(ctx context.Context, req *maintenancepb.ScheduleMaintenanceRequest) (*maintenancepb.ScheduleMaintenanceResponse, error) {
+	w := req.Window
+	if w == nil || w.Title == "" {
+		return nil, status.Error(codes.InvalidArgument, "window.title is required")
+	}
+	if len(w.ServiceIds) == 0 && len(w.Selector) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "window needs service_ids or a selector")
+	}
+	if w.EndsAtMs <= w.StartsAtMs {
+		return nil, status.Error(codes.InvalidArgument, "window must end after it starts")
+	}
+	if w.Rrule != "" {
+		if _, err := ParseRRule(w.Rrule); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid rrule: %v", err)
+		}
+	}
+
+	m := MaintenanceWindowModel{
+		ID:         newID(),
+		Title:      w.Title,
+		ServiceIDs: w.ServiceIds,
+		Selector:   w.Selector,
+		StartsAt:   w.StartsAtMs,
+		EndsAt:     w.EndsAtMs,
+		RRule:      w.Rrule,
+		CreatedBy:  actorFromContext(ctx),
+	}
+	if err := s.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, err
+	}
+	s.schedule.Invalidate()
+	return &maintenancepb.ScheduleMaintenanceResponse{Window: windowToProto(m, time.Now())}, nil
+}
+
+// ListMaintenance returns current and upcoming windows, soonest first
+func (s *MaintenanceServerImpl) ListMaintenance(ctx context.Context, req *maintenancepb.ListMaintenanceRequest) (*maintenancepb.ListMaintenanceResponse, error) {
+	q := s.db.WithContext(ctx).Order("starts_at")
+	if !req.IncludeCancelled {
+		q = q.Where("cancelled = ?", false)
+	}
+	var windows []MaintenanceWindowModel
+	if err := q.Find(&windows).Error; err != nil {
+		return nil, err
+	}
+
+	var svc ServiceModel
+	if req.ServiceId != "" {
+		if err := s.db.WithContext(ctx).First(&svc, "id = ?", req.ServiceId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "service %q not found", req.ServiceId)
+			}
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	resp := &maintenancepb.ListMaintenanceResponse{}
+	for _, m := range windows {
+		if req.ServiceId != "" && !m.Covers(svc) {
+			continue
+		}
+		p := windowToProto(m, now)
+		if !req.IncludePast && p.NextStartMs == 0 {
+			continue
+		}
+		resp.Windows = append(resp.Windows, p)
+	}
+	return resp, nil
+}
+
+// CancelMaintenance ends a window early, or removes an upcoming one
+func (s *MaintenanceServerImpl) CancelMaintenance(ctx context.Context, req *maintenancepb.CancelMaintenanceRequest) (*maintenancepb.CancelMaintenanceResponse, error) {
+	var m MaintenanceWindowModel
+	if err := s.db.WithContext(ctx).First(&m, "id = ?", req.Id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "maintenance window %q not found", req.Id)
+		}
+		return nil, err
+	}
+	m.Cancelled = true
+	if err := s.db.WithContext(ctx).Save(&m).Error; err != nil {
+		return nil, err
+	}
+	s.schedule.Invalidate()
+	return &maintenancepb.CancelMaintenanceResponse{Window: windowToProto(m, time.Now())}, nil
+}
+
+func windowToProto(m MaintenanceWindowModel, now time.Time) *maintenancepb.MaintenanceWindow {
+	p := &maintenancepb.MaintenanceWindow{
+		Id:         m.ID,
+		Title:      m.Title,
+		ServiceIds: m.ServiceIDs,
+		Selector:   m.Selector,
+		StartsAtMs: m.StartsAt,
+		EndsAtMs:   m.EndsAt,
+		Rrule:      m.RRule,
+		Cancelled:  m.Cancelled,
+		CreatedBy:  m.CreatedBy,
+	}
+	if !m.Cancelled {
+		if start, _, ok := m.Occurrence(now); ok {
+			p.NextStartMs = start.UnixMilli()
+			p.Active = !start.After(now)
+		}
+	}
+	return p
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// A deliberately small metrics registry rendered in the Prometheus text
+// exposition format at /metrics.
+
+type metricSample struct {
+	labels string
+	value  func() float64
+}
+
+type metricFamily struct {
+	help    string
+	kind    string
+	samples []metricSample
+}
+
+var (
+	metricsMu sync.Mutex
+	families  = map[string]*metricFamily{}
+)
+
+func register(name, help, kind string, labels map[string]string, value func() float64) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	f, ok := families[name]
+	if !ok {
+		f = &metricFamily{help: help, kind: kind}
+		families[name] = f
+	}
+	f.samples = append(f.samples, metricSample{labels: formatLabels(labels), value: value})
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// RegisterGaugeFunc exposes a gauge whose value is computed at scrape time.
+func RegisterGaugeFunc(name, help string, labels map[string]string, fn func() float64) {
+	register(name, help, "gauge", labels, fn)
+}
+
+// Counter is a monotonically increasing metric.
+type Counter struct {
+	v atomic.Uint64
+}
+
+func (c *Counter) Inc() {
+	c.v.Add(1)
+}
+
+// NewCounter registers and returns a counter.
+func NewCounter(name, help string, labels map[string]string) *Counter {
+	c := &Counter{}
+	register(name, help, "counter", labels, func() float64 { return float64(c.v.Load()) })
+	return c
+}
+
+// MetricsHandler renders every registered metric.
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, name := range names {
+		f := families[name]
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.kind)
+		for _, s := range f.samples {
+			fmt.Fprintf(w, "%s%s %g\n", name, s.labels, s.value())
+		}
+	}
+}
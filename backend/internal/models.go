@@ -5,24 +5,364 @@ import "gorm.io/gorm"
 // ServiceModel mirrors the catalog.v1.Service fields.
 type ServiceModel struct {
     // GORM will use “id” as the primary key column by default when tagged `gorm:"primaryKey"`.
-    ID       string `gorm:"primaryKey;column:id"`
-    Name     string `gorm:"column:name"`
-    Owner    string `gorm:"column:owner"`
-    Version  string `gorm:"column:version"`
-    ProtoURL string `gorm:"column:proto_url"`
+    ID         string            `gorm:"primaryKey;column:id"`
+    Name       string            `gorm:"column:name"`
+    Owner      string            `gorm:"column:owner"`
+    Version    string            `gorm:"column:version"`
+    ProtoURL   string            `gorm:"column:proto_url"`
+    Labels     map[string]string `gorm:"column:labels;serializer:json"`
+    Public     bool              `gorm:"column:public"`
+    Scrape     *ScrapeConfig     `gorm:"column:scrape;serializer:json"`
+    Thresholds *HealthThresholds `gorm:"column:thresholds;serializer:json"`
 }
 
-// HealthMetricModel mirrors health.v1.WatchHealthResponse fields.
+// HealthThresholds mirrors catalog.v1.HealthThresholds.
+type HealthThresholds struct {
+    DegradedLatencyMs int32   `json:"degraded_latency_ms,omitempty"`
+    DownLatencyMs     int32   `json:"down_latency_ms,omitempty"`
+    DegradedErrorRate float32 `json:"degraded_error_rate,omitempty"`
+    DownErrorRate     float32 `json:"down_error_rate,omitempty"`
+    RaiseAfter        int32   `json:"raise_after,omitempty"`
+    ClearAfter        int32   `json:"clear_after,omitempty"`
+}
+
+// ScrapeConfig mirrors catalog.v1.ScrapeConfig.
+type ScrapeConfig struct {
+    URL             string  `json:"url"`
+    IntervalSeconds int32   `json:"interval_seconds,omitempty"`
+    LatencyMetric   string  `json:"latency_metric,omitempty"`
+    LatencyQuantile float64 `json:"latency_quantile,omitempty"`
+    LatencyUnit     string  `json:"latency_unit,omitempty"`
+    ErrorsMetric    string  `json:"errors_metric,omitempty"`
+    TotalMetric     string  `json:"total_metric,omitempty"`
+}
+
+// HealthMetricModel mirrors health.v1.WatchHealthResponse fields. Its table
+// is partitioned by day and created by migrateHealthMetrics, not AutoMigrate.
 type HealthMetricModel struct {
-    ID         uint    `gorm:"primaryKey;autoIncrement"`
-    ServiceID  string  `gorm:"column:service_id;index"`
-    Status     int32   `gorm:"column:status"`
-    LatencyMs  int32   `gorm:"column:latency_ms"`
-    ErrorRate  float32 `gorm:"column:error_rate"`
-    Timestamp  int64   `gorm:"column:timestamp"`
+    ID            uint     `gorm:"primaryKey;autoIncrement"`
+    ServiceID     string   `gorm:"column:service_id;index:idx_health_metric_models_service_timestamp,priority:1"`
+    Status        int32    `gorm:"column:status"`
+    LatencyMs     int32    `gorm:"column:latency_ms"`
+    ErrorRate     float32  `gorm:"column:error_rate"`
+    Timestamp     int64    `gorm:"column:timestamp;index:idx_health_metric_models_service_timestamp,priority:2"`
+    InMaintenance bool     `gorm:"column:in_maintenance"`
+    Reasons       []string `gorm:"column:reasons;serializer:json"`
+    AnomalyScore  float64  `gorm:"column:anomaly_score"`
+}
+
+// AnomalyEventModel mirrors health.v1.AnomalyEvent. Its ID is derived from
+// the service, metric and start, so replaying samples yields the same events.
+type AnomalyEventModel struct {
+    ID        string  `gorm:"primaryKey;column:id"`
+    ServiceID string  `gorm:"column:service_id;index"`
+    Metric    string  `gorm:"column:metric"`
+    StartedAt int64   `gorm:"column:started_at;index"`
+    EndedAt   int64   `gorm:"column:ended_at"`
+    Samples   int64   `gorm:"column:samples"`
+    MaxScore  float64 `gorm:"column:max_score"`
+    PeakValue float64 `gorm:"column:peak_value"`
+    Baseline  float64 `gorm:"column:baseline"`
+}
+
+// ServiceTokenModel is a credential a service uses to push its own health
+// samples. Only the SHA-256 of the token is stored.
+type ServiceTokenModel struct {
+    ID         string `gorm:"primaryKey;column:id"`
+    ServiceID  string `gorm:"column:service_id;index"`
+    Name       string `gorm:"column:name"`
+    Prefix     string `gorm:"column:prefix"`
+    TokenHash  string `gorm:"column:token_hash;uniqueIndex"`
+    CreatedBy  string `gorm:"column:created_by"`
+    CreatedAt  int64  `gorm:"column:created_at;autoCreateTime:milli"`
+    LastUsedAt int64  `gorm:"column:last_used_at"`
+    Revoked    bool   `gorm:"column:revoked"`
+}
+
+// HealthRollup aggregates the health samples of one service over one
+// bucket. Averages are derived from the sums, so rollups of rollups stay exact.
+type HealthRollup struct {
+    ServiceID            string  `gorm:"primaryKey;column:service_id"`
+    Bucket               int64   `gorm:"primaryKey;column:bucket;index"` // start, unix ms
+    Samples              int64   `gorm:"column:samples"`
+    UpSamples            int64   `gorm:"column:up_samples"`
+    MaintenanceSamples   int64   `gorm:"column:maintenance_samples"`
+    MaintenanceUpSamples int64   `gorm:"column:maintenance_up_samples"`
+    LatencySumMs         int64   `gorm:"column:latency_sum_ms"`
+    LatencyMaxMs         int32   `gorm:"column:latency_max_ms"`
+    ErrorRateSum         float64 `gorm:"column:error_rate_sum"`
+    ErrorRateMax         float32 `gorm:"column:error_rate_max"`
+}
+
+// HealthRollup1mModel, HealthRollup1hModel and HealthRollup1dModel are the
+// 1-minute, 1-hour and 1-day rollup tables.
+type HealthRollup1mModel struct{ HealthRollup }
+type HealthRollup1hModel struct{ HealthRollup }
+type HealthRollup1dModel struct{ HealthRollup }
+
+func (HealthRollup1mModel) TableName() string { return "health_rollups_1m" }
+func (HealthRollup1hModel) TableName() string { return "health_rollups_1h" }
+func (HealthRollup1dModel) TableName() string { return "health_rollups_1d" }
+
+// RollupWatermarkModel records up to when (exclusive, unix ms) a rollup
+// table is complete.
+type RollupWatermarkModel struct {
+    Table     string `gorm:"primaryKey;column:rollup_table"`
+    Watermark int64  `gorm:"column:watermark"`
+}
+
+// StatusChangeModel records a service moving from one health status to
+// another; it feeds the public status page.
+type StatusChangeModel struct {
+    ID         uint   `gorm:"primaryKey;autoIncrement"`
+    ServiceID  string `gorm:"column:service_id;index"`
+    FromStatus int32  `gorm:"column:from_status"`
+    ToStatus   int32  `gorm:"column:to_status"`
+    At         int64  `gorm:"column:at;index"`
+}
+
+// AlertRuleModel is an alerting rule; see alerting.v1.AlertRule for the expr syntax.
+type AlertRuleModel struct {
+    ID        string            `gorm:"primaryKey;column:id"`
+    Name      string            `gorm:"column:name"`
+    ServiceID string            `gorm:"column:service_id;index"`
+    Selector  map[string]string `gorm:"column:selector;serializer:json"`
+    Expr      string            `gorm:"column:expr"`
+    Severity  string            `gorm:"column:severity"`
+    Enabled   bool              `gorm:"column:enabled"`
+}
+
+// AlertModel is one pending, firing or resolved alert. At most one
+// non-resolved alert exists per fingerprint (rule + service).
+type AlertModel struct {
+    ID          string  `gorm:"primaryKey;column:id"`
+    RuleID      string  `gorm:"column:rule_id;index"`
+    ServiceID   string  `gorm:"column:service_id;index"`
+    Fingerprint string  `gorm:"column:fingerprint;index"`
+    State       int32   `gorm:"column:state;index"`
+    Value       float64 `gorm:"column:value"`
+    StartedAt   int64   `gorm:"column:started_at"`
+    FiredAt     int64   `gorm:"column:fired_at"`
+    ResolvedAt  int64   `gorm:"column:resolved_at"`
+}
+
+// NotificationChannelModel is a destination for a team's alert notifications.
+type NotificationChannelModel struct {
+    ID           string `gorm:"primaryKey;column:id"`
+    Team         string `gorm:"column:team;index"`
+    Kind         int32  `gorm:"column:kind"`
+    Target       string `gorm:"column:target"`
+    Secret       string `gorm:"column:secret"`
+    BodyTemplate string `gorm:"column:body_template"`
+    Enabled      bool   `gorm:"column:enabled"`
+}
+
+// NotificationOutboxModel is one message to deliver to one channel. Rows are
+// written in the same place the alert transition is recorded, so deliveries
+// survive restarts, and are kept afterwards as the delivery log.
+type NotificationOutboxModel struct {
+    ID            string `gorm:"primaryKey;column:id"`
+    ChannelID     string `gorm:"column:channel_id;index"`
+    AlertID       string `gorm:"column:alert_id;index"`
+    Event         string `gorm:"column:event"`
+    Subject       string `gorm:"column:subject"`
+    Body          string `gorm:"column:body"`
+    Payload       string `gorm:"column:payload"`
+    Status        int32  `gorm:"column:status;index"`
+    Attempts      int32  `gorm:"column:attempts"`
+    NextAttemptAt int64  `gorm:"column:next_attempt_at;index"`
+    LastError     string `gorm:"column:last_error"`
+    CreatedAt     int64  `gorm:"column:created_at;autoCreateTime:milli"`
+    DeliveredAt   int64  `gorm:"column:delivered_at"`
+}
+
+// SLOModel is a service level objective; see slo.v1.SLO.
+type SLOModel struct {
+    ID                 string  `gorm:"primaryKey;column:id"`
+    ServiceID          string  `gorm:"column:service_id;index"`
+    Name               string  `gorm:"column:name"`
+    Kind               int32   `gorm:"column:kind"`
+    Target             float64 `gorm:"column:target"`
+    LatencyThresholdMs int32   `gorm:"column:latency_threshold_ms"`
+    WindowDays         int32   `gorm:"column:window_days"`
+    ExcludeMaintenance bool    `gorm:"column:exclude_maintenance"`
+}
+
+// IncidentModel is an outage being handled; see incident.v1.Incident.
+type IncidentModel struct {
+    ID         string `gorm:"primaryKey;column:id"`
+    Title      string `gorm:"column:title"`
+    Summary    string `gorm:"column:summary"`
+    Severity   int32  `gorm:"column:severity"`
+    State      int32  `gorm:"column:state;index"`
+    CreatedBy  string `gorm:"column:created_by"`
+    CreatedAt  int64  `gorm:"column:created_at;autoCreateTime:milli"`
+    UpdatedAt  int64  `gorm:"column:updated_at;autoUpdateTime:milli"`
+    ResolvedAt int64  `gorm:"column:resolved_at"`
+    AutoOpened bool   `gorm:"column:auto_opened"`
 }
 
-// Migrate runs the auto‐migration for our two tables.
+// IncidentServiceModel links an incident to an affected catalog service.
+type IncidentServiceModel struct {
+    IncidentID string `gorm:"primaryKey;column:incident_id"`
+    ServiceID  string `gorm:"primaryKey;column:service_id;index"`
+}
+
+// IncidentEventModel is one entry of an incident's timeline.
+type IncidentEventModel struct {
+    ID         string `gorm:"primaryKey;column:id"`
+    IncidentID string `gorm:"column:incident_id;index"`
+    At         int64  `gorm:"column:at"`
+    Actor      string `gorm:"column:actor"`
+    Kind       string `gorm:"column:kind"`
+    FromState  int32  `gorm:"column:from_state"`
+    ToState    int32  `gorm:"column:to_state"`
+    Note       string `gorm:"column:note"`
+}
+
+// MaintenanceWindowModel is a planned maintenance window; see
+// maintenance.v1.MaintenanceWindow.
+type MaintenanceWindowModel struct {
+    ID         string            `gorm:"primaryKey;column:id"`
+    Title      string            `gorm:"column:title"`
+    ServiceIDs []string          `gorm:"column:service_ids;serializer:json"`
+    Selector   map[string]string `gorm:"column:selector;serializer:json"`
+    StartsAt   int64             `gorm:"column:starts_at"`
+    EndsAt     int64             `gorm:"column:ends_at"`
+    RRule      string            `gorm:"column:rrule"`
+    Cancelled  bool              `gorm:"column:cancelled;index"`
+    CreatedBy  string            `gorm:"column:created_by"`
+}
+
+// AuditEventModel mirrors audit.v1.AuditEvent. Rows are only ever inserted,
+// in seq order, by AuditLog.Append; a trigger rejects updates and deletes.
+type AuditEventModel struct {
+    Seq        int64              `gorm:"primaryKey;autoIncrement;column:seq"`
+    At         int64              `gorm:"column:at;index"`
+    Actor      string             `gorm:"column:actor;index"`
+    Action     string             `gorm:"column:action;index"`
+    Resource   string             `gorm:"column:resource"`
+    ResourceID string             `gorm:"column:resource_id;index"`
+    Outcome    string             `gorm:"column:outcome"`
+    SourceIP   string             `gorm:"column:source_ip"`
+    RequestID  string             `gorm:"column:request_id"`
+    Changes    []AuditFieldChange `gorm:"column:changes;serializer:json"`
+    PrevHash   string             `gorm:"column:prev_hash"`
+    Hash       string             `gorm:"column:hash"`
+}
+
+func (AuditEventModel) TableName() string { return "audit_events" }
+
+// AuditFieldChange mirrors audit.v1.FieldChange.
+type AuditFieldChange struct {
+    Field  string `json:"field"`
+    Before string `json:"before,omitempty"`
+    After  string `json:"after,omitempty"`
+}
+
+// UserModel is an account. Usernames are stored lower case; the password
+// is a bcrypt hash and recovery codes are SHA-256 hashes, each removed once
+// used. TOTPPending holds a secret until its first code is verified;
+// TOTPLastStep stops a code from being used twice. Users provisioned from
+// an identity provider have Provider and ExternalID set and no password.
+type UserModel struct {
+    ID            string   `gorm:"primaryKey;column:id"`
+    Username      string   `gorm:"column:username;uniqueIndex"`
+    Email         string   `gorm:"column:email"`
+    EmailVerified bool     `gorm:"column:email_verified"`
+    PasswordHash  string   `gorm:"column:password_hash"`
+    Roles         []string `gorm:"column:roles;serializer:json"`
+    Provider      string   `gorm:"column:provider;uniqueIndex:idx_users_external,where:provider <> ''"`
+    ExternalID    string   `gorm:"column:external_id;uniqueIndex:idx_users_external"`
+    MFAEnabled    bool     `gorm:"column:mfa_enabled"`
+    TOTPSecret    string   `gorm:"column:totp_secret"`
+    TOTPPending   string   `gorm:"column:totp_pending"`
+    TOTPLastStep  int64    `gorm:"column:totp_last_step"`
+    RecoveryCodes []string `gorm:"column:recovery_codes;serializer:json"`
+    CreatedAt     int64    `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (UserModel) TableName() string { return "users" }
+
+// SessionModel is one login, identified by the jti of its access token.
+// Revoked sessions are kept until they would have expired.
+type SessionModel struct {
+    ID         string `gorm:"primaryKey;column:id"`
+    Username   string `gorm:"column:username;index"`
+    UserAgent  string `gorm:"column:user_agent"`
+    IP         string `gorm:"column:ip"`
+    CreatedAt  int64  `gorm:"column:created_at;autoCreateTime:milli"`
+    LastSeenAt int64  `gorm:"column:last_seen_at"`
+    ExpiresAt  int64  `gorm:"column:expires_at;index"`
+    RevokedAt  int64  `gorm:"column:revoked_at"`
+    RevokedBy  string `gorm:"column:revoked_by"`
+}
+
+func (SessionModel) TableName() string { return "sessions" }
+
+// SettingModel is a runtime setting changed through the admin API, e.g.
+// mfa.require_for_admins.
+type SettingModel struct {
+    Key   string `gorm:"primaryKey;column:key"`
+    Value string `gorm:"column:value"`
+}
+
+// AccountTokenModel is a single-use token mailed to a user, to reset their
+// password or verify their email. Only the SHA-256 of the token is stored.
+type AccountTokenModel struct {
+    ID        string `gorm:"primaryKey;column:id"`
+    UserID    string `gorm:"column:user_id;index"`
+    Purpose   string `gorm:"column:purpose"` // password_reset or verify_email
+    TokenHash string `gorm:"column:token_hash;uniqueIndex"`
+    Email     string `gorm:"column:email"` // the address it was sent to
+    CreatedAt int64  `gorm:"column:created_at;autoCreateTime:milli"`
+    ExpiresAt int64  `gorm:"column:expires_at"`
+    UsedAt    int64  `gorm:"column:used_at"`
+}
+
+func (AccountTokenModel) TableName() string { return "account_tokens" }
+
+// ServiceAccountModel is a non-human identity that gets access tokens with
+// the OAuth2 client-credentials grant. Only the SHA-256 of its secret is stored.
+type ServiceAccountModel struct {
+    ID         string   `gorm:"primaryKey;column:id"`
+    ClientID   string   `gorm:"column:client_id;uniqueIndex"`
+    Name       string   `gorm:"column:name"`
+    SecretHash string   `gorm:"column:secret_hash"`
+    Scopes     []string `gorm:"column:scopes;serializer:json"`
+    CreatedBy  string   `gorm:"column:created_by"`
+    CreatedAt  int64    `gorm:"column:created_at;autoCreateTime:milli"`
+    LastUsedAt int64    `gorm:"column:last_used_at"`
+    Disabled   bool     `gorm:"column:disabled"`
+}
+
+func (ServiceAccountModel) TableName() string { return "service_accounts" }
+
+// Migrate runs the auto‐migration for our tables.
 func Migrate(db *gorm.DB) error {
-    return db.AutoMigrate(&ServiceModel{}, &HealthMetricModel{})
+    if err := migrateHealthMetrics(db); err != nil {
+        return err
+    }
+    if err := db.AutoMigrate(
+        &ServiceModel{}, &ServiceTokenModel{}, &StatusChangeModel{}, &AnomalyEventModel{},
+        &HealthRollup1mModel{}, &HealthRollup1hModel{}, &HealthRollup1dModel{}, &RollupWatermarkModel{},
+        &AlertRuleModel{}, &AlertModel{},
+        &NotificationChannelModel{}, &NotificationOutboxModel{},
+        &SLOModel{},
+        &IncidentModel{}, &IncidentServiceModel{}, &IncidentEventModel{},
+        &MaintenanceWindowModel{},
+        &AuditEventModel{},
+        &UserModel{}, &SessionModel{}, &SettingModel{}, &ServiceAccountModel{}, &AccountTokenModel{},
+    ); err != nil {
+        return err
+    }
+    if err := migrateAlerts(db); err != nil {
+        return err
+    }
+    return migrateAuditEvents(db)
+}
+
+// newID returns a random 32-character hex identifier.
+func newID() string {
+    return newRequestID()
 }
\ No newline at end of file
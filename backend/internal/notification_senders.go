@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NotificationSender delivers one outbox message to one channel.
+type NotificationSender interface {
+	Send(ctx context.Context, ch NotificationChannelModel, msg NotificationOutboxModel) error
+}
+
+// WebhookSender POSTs the JSON payload to the channel target. When the
+// channel has a secret, the request carries
+//
+//	X-Team15-Timestamp: <unix seconds>
+//	X-Team15-Signature: sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+//
+// so receivers can verify origin and reject replays.
+type WebhookSender struct {
+	Client *http.Client
+}
+
+func (s WebhookSender) Send(ctx context.Context, ch NotificationChannelModel, msg NotificationOutboxModel) error {
+	body := []byte(msg.Payload)
+	header := http.Header{"Content-Type": {"application/json"}}
+	if ch.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		header.Set("X-Team15-Timestamp", ts)
+		header.Set("X-Team15-Signature", "sha256="+SignWebhook(ch.Secret, ts, body))
+	}
+	return postJSON(ctx, s.Client, ch.Target, header, body)
+}
+
+// SignWebhook returns the hex HMAC-SHA256 signature WebhookSender sends.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SlackSender posts the rendered message to a Slack-compatible incoming webhook.
+type SlackSender struct {
+	Client *http.Client
+}
+
+func (s SlackSender) Send(ctx context.Context, ch NotificationChannelModel, msg NotificationOutboxModel) error {
+	body, _ := json.Marshal(map[string]string{"text": "*" + msg.Subject + "*\n" + msg.Body})
+	return postJSON(ctx, s.Client, ch.Target, http.Header{"Content-Type": {"application/json"}}, body)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+// SMTPSender emails the rendered message to the comma-separated addresses
+// in the channel target, upgrading to TLS when the server offers STARTTLS.
+type SMTPSender struct {
+	Addr     string // host:port; empty disables email
+	From     string
+	Username string
+	Password string
+}
+
+func (s SMTPSender) Send(ctx context.Context, ch NotificationChannelModel, msg NotificationOutboxModel) error {
+	if s.Addr == "" {
+		return errors.New("SMTP is not configured")
+	}
+	var to []string
+	for _, addr := range strings.Split(ch.Target, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	if len(to) == 0 {
+		return errors.New("channel has no recipients")
+	}
+	return s.send(ctx, to, msg.Subject, msg.Body)
+}
+
+// SendMail emails one recipient, making SMTPSender a Mailer too.
+func (s SMTPSender) SendMail(ctx context.Context, to, subject, body string) error {
+	if s.Addr == "" {
+		return errors.New("SMTP is not configured")
+	}
+	return s.send(ctx, []string{to}, subject, body)
+}
+
+func (s SMTPSender) send(ctx context.Context, to []string, subject, body string) error {
+	// Header values come from rule names and channel targets; a line break
+	// in one would let it add headers or recipients of its own
+	for _, v := range append([]string{s.From, subject}, to...) {
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("header value %q contains a line break", v)
+		}
+	}
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From, strings.Join(to, ", "), mime.QEncoding.Encode("UTF-8", subject), time.Now().Format(time.RFC1123Z),
+		strings.ReplaceAll(body, "\n", "\r\n"))
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+var errUnsupportedChannel = errors.New("unsupported channel kind")
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	notificationpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/notification/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type NotificationServerImpl struct {
+	notificationpb.UnimplementedNotificationServiceServer
+	db *gorm.DB
+}
+
+func NewNotificationServer(db *gorm.DB) *NotificationServerImpl {
+	return &NotificationServerImpl{db: db}
+}
+
+// CreateChannel registers a notification channel for a team
+func (s *NotificationServerImpl) CreateChannel(ctx context.Context, req *notificationpb.CreateChannelRequest) (*notificationpb.CreateChannelResponse, error) {
+	ch := req.Channel
+	if ch == nil || ch.Team == "" || ch.Target == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel.team and channel.target are required")
+	}
+	if _, ok := notificationpb.ChannelKind_name[int32(ch.Kind)]; !ok || ch.Kind == notificationpb.ChannelKind_CHANNEL_KIND_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "channel.kind is required")
+	}
+	if strings.ContainsAny(ch.Target, "\r\n") {
+		return nil, status.Error(codes.InvalidArgument, "channel.target must be on one line")
+	}
+
+	m := NotificationChannelModel{
+		ID:           newID(),
+		Team:         ch.Team,
+		Kind:         int32(ch.Kind),
+		Target:       ch.Target,
+		Secret:       ch.Secret,
+		BodyTemplate: ch.BodyTemplate,
+		Enabled:      ch.Enabled,
+	}
+	if err := s.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, err
+	}
+	return &notificationpb.CreateChannelResponse{Channel: channelToProto(m)}, nil
+}
+
+// DeleteChannel removes a channel; its pending deliveries fail on their next attempt
+func (s *NotificationServerImpl) DeleteChannel(ctx context.Context, req *notificationpb.DeleteChannelRequest) (*notificationpb.DeleteChannelResponse, error) {
+	result := s.db.WithContext(ctx).Delete(&NotificationChannelModel{}, "id = ?", req.Id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "channel %q not found", req.Id)
+	}
+	return &notificationpb.DeleteChannelResponse{}, nil
+}
+
+// ListChannels returns channels, optionally for one team
+func (s *NotificationServerImpl) ListChannels(ctx context.Context, req *notificationpb.ListChannelsRequest) (*notificationpb.ListChannelsResponse, error) {
+	q := s.db.WithContext(ctx).Order("team")
+	if req.Team != "" {
+		q = q.Where("team = ?", req.Team)
+	}
+	var channels []NotificationChannelModel
+	if err := q.Find(&channels).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &notificationpb.ListChannelsResponse{}
+	for _, m := range channels {
+		resp.Channels = append(resp.Channels, channelToProto(m))
+	}
+	return resp, nil
+}
+
+// ListDeliveries returns the most recent deliveries, newest first
+func (s *NotificationServerImpl) ListDeliveries(ctx context.Context, req *notificationpb.ListDeliveriesRequest) (*notificationpb.ListDeliveriesResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	q := s.db.WithContext(ctx).Order("created_at desc").Limit(limit)
+	if req.ChannelId != "" {
+		q = q.Where("channel_id = ?", req.ChannelId)
+	}
+	if req.AlertId != "" {
+		q = q.Where("alert_id = ?", req.AlertId)
+	}
+	var rows []NotificationOutboxModel
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &notificationpb.ListDeliveriesResponse{}
+	for _, m := range rows {
+		resp.Deliveries = append(resp.Deliveries, &notificationpb.Delivery{
+			Id:            m.ID,
+			ChannelId:     m.ChannelID,
+			AlertId:       m.AlertID,
+			Event:         m.Event,
+			Subject:       m.Subject,
+			Status:        notificationpb.DeliveryStatus(m.Status),
+			Attempts:      m.Attempts,
+			LastError:     m.LastError,
+			CreatedAtMs:   m.CreatedAt,
+			DeliveredAtMs: m.DeliveredAt,
+		})
+	}
+	return resp, nil
+}
+
+// channelToProto leaves out the signing secret
+func channelToProto(m NotificationChannelModel) *notificationpb.Channel {
+	return &notificationpb.Channel{
+		Id:           m.ID,
+		Team:         m.Team,
+		Kind:         notificationpb.ChannelKind(m.Kind),
+		Target:       m.Target,
+		BodyTemplate: m.BodyTemplate,
+		Enabled:      m.Enabled,
+	}
+}
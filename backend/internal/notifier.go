@@ -0,0 +1,245 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	alertingpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/alerting/v1"
+	notificationpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/notification/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxNotificationAttempts = 8
+	notificationBatchSize   = 20
+	notificationSendTimeout = 15 * time.Second
+	// notificationLease keeps a claimed row away from other instances while
+	// it is being sent. The batch is sent in order, so the last row may wait
+	// for every send before it to time out.
+	notificationLease = notificationBatchSize*notificationSendTimeout + time.Minute
+)
+
+// NotificationData is what message templates and webhook payloads see.
+type NotificationData struct {
+	Event       string            `json:"event"` // "firing" or "resolved"
+	AlertID     string            `json:"alert_id"`
+	RuleID      string            `json:"rule_id"`
+	RuleName    string            `json:"rule_name"`
+	Expr        string            `json:"expr"`
+	Severity    string            `json:"severity"`
+	ServiceID   string            `json:"service_id"`
+	ServiceName string            `json:"service_name"`
+	Team        string            `json:"team"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Value       float64           `json:"value"`
+	StartedAt   time.Time         `json:"started_at"`
+	FiredAt     time.Time         `json:"fired_at"`
+	ResolvedAt  *time.Time        `json:"resolved_at,omitempty"`
+}
+
+var (
+	defaultSubjectTemplate = template.Must(template.New("subject").Funcs(templateFuncs).Parse(
+		`[{{.Event | upper}}{{if .Severity}} {{.Severity}}{{end}}] {{.RuleName}} on {{.ServiceName}}`))
+	defaultBodyTemplate = `Alert "{{.RuleName}}" is {{.Event}} for service {{.ServiceName}} ({{.ServiceID}}), owned by {{.Team}}.
+
+Condition: {{.Expr}}
+Value:     {{printf "%.4g" .Value}}
+Fired at:  {{.FiredAt.Format "2006-01-02 15:04:05 MST"}}
+{{- if .ResolvedAt}}
+Resolved:  {{.ResolvedAt.Format "2006-01-02 15:04:05 MST"}}{{end}}
+`
+	templateFuncs = template.FuncMap{"upper": strings.ToUpper}
+)
+
+// Notifier turns alert transitions into outbox rows for every channel of the
+// owning team, and delivers them in the background with exponential backoff.
+type Notifier struct {
+	db      *gorm.DB
+	senders map[notificationpb.ChannelKind]NotificationSender
+}
+
+func NewNotifier(db *gorm.DB, smtpSender SMTPSender) *Notifier {
+	client := &http.Client{Timeout: notificationSendTimeout}
+	return &Notifier{
+		db: db,
+		senders: map[notificationpb.ChannelKind]NotificationSender{
+			notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK: WebhookSender{Client: client},
+			notificationpb.ChannelKind_CHANNEL_KIND_SLACK:   SlackSender{Client: client},
+			notificationpb.ChannelKind_CHANNEL_KIND_EMAIL:   smtpSender,
+		},
+	}
+}
+
+// Enqueue is an AlertEvaluator hook that writes one outbox row per channel
+// in the transaction recording the transition, so a notification is never
+// lost once the alert has changed state. The service may have been deleted,
+// in which case only channels of every team ("*") are notified.
+func (n *Notifier) Enqueue(ctx context.Context, tx *gorm.DB, t AlertTransition) error {
+	var svc ServiceModel
+	if err := tx.Limit(1).Find(&svc, "id = ?", t.Alert.ServiceID).Error; err != nil {
+		return err
+	}
+
+	var channels []NotificationChannelModel
+	if err := tx.Where("enabled = ? AND team IN ?", true, []string{svc.Owner, "*"}).Find(&channels).Error; err != nil {
+		return err
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+
+	data := NotificationData{
+		Event:       "firing",
+		AlertID:     t.Alert.ID,
+		RuleID:      t.Rule.ID,
+		RuleName:    t.Rule.Name,
+		Expr:        t.Rule.Expr,
+		Severity:    t.Rule.Severity,
+		ServiceID:   t.Alert.ServiceID,
+		ServiceName: svc.Name,
+		Team:        svc.Owner,
+		Labels:      svc.Labels,
+		Value:       t.Alert.Value,
+		StartedAt:   time.UnixMilli(t.Alert.StartedAt),
+		FiredAt:     time.UnixMilli(t.Alert.FiredAt),
+	}
+	if data.ServiceName == "" {
+		data.ServiceName = t.Alert.ServiceID
+	}
+	if t.Alert.State == int32(alertingpb.AlertState_ALERT_STATE_RESOLVED) {
+		data.Event = "resolved"
+		resolved := time.UnixMilli(t.Alert.ResolvedAt)
+		data.ResolvedAt = &resolved
+	}
+	payload, _ := json.Marshal(data)
+
+	var subject strings.Builder
+	defaultSubjectTemplate.Execute(&subject, data)
+
+	rows := make([]NotificationOutboxModel, 0, len(channels))
+	for _, ch := range channels {
+		rows = append(rows, NotificationOutboxModel{
+			ID:            newID(),
+			ChannelID:     ch.ID,
+			AlertID:       t.Alert.ID,
+			Event:         data.Event,
+			Subject:       subject.String(),
+			Body:          renderBody(ch.BodyTemplate, data),
+			Payload:       string(payload),
+			Status:        int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_PENDING),
+			NextAttemptAt: time.Now().UnixMilli(),
+		})
+	}
+	return tx.Create(&rows).Error
+}
+
+// renderBody executes the channel's template, falling back to the default
+// when it is empty or broken so that a bad template never loses an alert.
+func renderBody(tmpl string, data NotificationData) string {
+	if tmpl == "" {
+		tmpl = defaultBodyTemplate
+	}
+	var out strings.Builder
+	t, err := template.New("body").Funcs(templateFuncs).Parse(tmpl)
+	if err == nil {
+		err = t.Execute(&out, data)
+	}
+	if err != nil {
+		out.Reset()
+		template.Must(template.New("body").Funcs(templateFuncs).Parse(defaultBodyTemplate)).Execute(&out, data)
+	}
+	return out.String()
+}
+
+// Run delivers due outbox rows every interval until ctx is done.
+func (n *Notifier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := n.dispatch(ctx); err != nil {
+				Logger(ctx).Warn("notification dispatch failed", "error", err)
+			}
+		}
+	}
+}
+
+func (n *Notifier) dispatch(ctx context.Context) error {
+	now := time.Now()
+
+	// Claim a batch; SKIP LOCKED lets several instances share the outbox
+	var due []NotificationOutboxModel
+	err := n.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_PENDING), now.UnixMilli()).
+			Order("next_attempt_at").Limit(notificationBatchSize).Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+		ids := make([]string, len(due))
+		for i, m := range due {
+			ids[i] = m.ID
+		}
+		return tx.Model(&NotificationOutboxModel{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(notificationLease).UnixMilli()).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range due {
+		n.deliver(ctx, msg)
+	}
+	return nil
+}
+
+func (n *Notifier) deliver(ctx context.Context, msg NotificationOutboxModel) {
+	db := n.db.WithContext(ctx)
+	msg.Attempts++
+
+	var ch NotificationChannelModel
+	err := db.First(&ch, "id = ?", msg.ChannelID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The channel was deleted; retrying cannot help
+		msg.Attempts = maxNotificationAttempts
+	} else if err == nil {
+		sender, ok := n.senders[notificationpb.ChannelKind(ch.Kind)]
+		if !ok {
+			msg.Attempts = maxNotificationAttempts
+			err = errUnsupportedChannel
+		} else {
+			sendCtx, cancel := context.WithTimeout(ctx, notificationSendTimeout)
+			err = sender.Send(sendCtx, ch, msg)
+			cancel()
+		}
+	}
+
+	switch {
+	case err == nil:
+		msg.Status = int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_DELIVERED)
+		msg.DeliveredAt = time.Now().UnixMilli()
+		msg.LastError = ""
+	case msg.Attempts >= maxNotificationAttempts:
+		msg.Status = int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_FAILED)
+		msg.LastError = err.Error()
+	default:
+		msg.NextAttemptAt = time.Now().Add(Backoff(int(msg.Attempts-1), 30*time.Second, time.Hour)).UnixMilli()
+		msg.LastError = err.Error()
+	}
+	if err != nil {
+		Logger(ctx).Warn("notification delivery failed", "delivery_id", msg.ID, "channel_id", msg.ChannelID,
+			"attempt", msg.Attempts, "error", err)
+	}
+	if err := db.Save(&msg).Error; err != nil {
+		Logger(ctx).Warn("failed to record notification delivery", "delivery_id", msg.ID, "error", err)
+	}
+}
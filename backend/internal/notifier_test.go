@@ -0,0 +1,377 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	alertingpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/alerting/v1"
+	notificationpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/notification/v1"
+	"gorm.io/gorm"
+)
+
+type fakeSender struct {
+	err  error
+	sent []NotificationOutboxModel
+}
+
+func (s *fakeSender) Send(ctx context.Context, ch NotificationChannelModel, msg NotificationOutboxModel) error {
+	s.sent = append(s.sent, msg)
+	return s.err
+}
+
+func newTestNotifier(t *testing.T, sender NotificationSender) (*Notifier, *gorm.DB) {
+	db := newTestDB(t, &ServiceModel{}, &NotificationChannelModel{}, &NotificationOutboxModel{})
+	return &Notifier{
+		db:      db,
+		senders: map[notificationpb.ChannelKind]NotificationSender{notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK: sender},
+	}, db
+}
+
+func TestNotifierEnqueue(t *testing.T) {
+	webhook := int32(notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK)
+	tests := []struct {
+		name      string
+		serviceID string
+		rollback  bool
+		want      []string // channel IDs
+	}{
+		{"owner and every team", "svc-1", false, []string{"ch-all", "ch-owner"}},
+		{"deleted service", "svc-gone", false, []string{"ch-all"}},
+		{"rolled back with the alert", "svc-1", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, db := newTestNotifier(t, &fakeSender{})
+			db.Create(&ServiceModel{ID: "svc-1", Name: "payments", Owner: "team-a"})
+			db.Create([]NotificationChannelModel{
+				{ID: "ch-owner", Team: "team-a", Kind: webhook, Enabled: true},
+				{ID: "ch-all", Team: "*", Kind: webhook, Enabled: true},
+				{ID: "ch-other", Team: "team-b", Kind: webhook, Enabled: true},
+				{ID: "ch-disabled", Team: "team-a", Kind: webhook},
+			})
+			tr := AlertTransition{
+				Alert: AlertModel{ID: "alert-1", ServiceID: tt.serviceID, State: int32(alertingpb.AlertState_ALERT_STATE_FIRING), Value: 0.5},
+				Rule:  AlertRuleModel{ID: "rule-1", Name: "errors", Expr: "error_rate > 0.1"},
+			}
+			errRollback := errors.New("rollback")
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := n.Enqueue(context.Background(), tx, tr); err != nil {
+					return err
+				}
+				if tt.rollback {
+					return errRollback
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, errRollback) {
+				t.Fatal(err)
+			}
+
+			var rows []NotificationOutboxModel
+			db.Order("channel_id").Find(&rows)
+			var got []string
+			for _, row := range rows {
+				got = append(got, row.ChannelID)
+				var data NotificationData
+				if err := json.Unmarshal([]byte(row.Payload), &data); err != nil {
+					t.Fatal(err)
+				}
+				if data.Event != "firing" || data.AlertID != "alert-1" || data.ServiceID != tt.serviceID {
+					t.Errorf("payload = %+v", data)
+				}
+				if row.Status != int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_PENDING) {
+					t.Errorf("status = %d, want pending", row.Status)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("outbox channels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifierDeliver(t *testing.T) {
+	pending := int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_PENDING)
+	delivered := int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_DELIVERED)
+	failed := int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_FAILED)
+	tests := []struct {
+		name      string
+		kind      notificationpb.ChannelKind
+		channelID string
+		attempts  int32 // before this delivery
+		sendErr   error
+		status    int32
+		backoff   time.Duration // expected minimum delay before the next attempt
+	}{
+		{"delivered", notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK, "ch", 0, nil, delivered, 0},
+		{"first failure", notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK, "ch", 0, errors.New("503"), pending, 30 * time.Second},
+		{"fourth failure", notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK, "ch", 3, errors.New("503"), pending, 4 * time.Minute},
+		{"seventh failure", notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK, "ch", 6, errors.New("503"), pending, 32 * time.Minute},
+		{"last attempt", notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK, "ch", maxNotificationAttempts - 1, errors.New("503"), failed, 0},
+		{"unsupported kind", notificationpb.ChannelKind_CHANNEL_KIND_SLACK, "ch", 0, nil, failed, 0},
+		{"deleted channel", notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK, "ch-gone", 0, nil, failed, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{err: tt.sendErr}
+			n, db := newTestNotifier(t, sender)
+			db.Create(&NotificationChannelModel{ID: "ch", Team: "team-a", Kind: int32(tt.kind), Enabled: true})
+			msg := NotificationOutboxModel{ID: "msg-1", ChannelID: tt.channelID, Status: pending, Attempts: tt.attempts}
+			db.Create(&msg)
+
+			before := time.Now()
+			n.deliver(context.Background(), msg)
+
+			var got NotificationOutboxModel
+			db.First(&got, "id = ?", "msg-1")
+			if got.Status != tt.status {
+				t.Errorf("status = %d, want %d", got.Status, tt.status)
+			}
+			if tt.status == delivered && (got.DeliveredAt == 0 || got.LastError != "") {
+				t.Errorf("delivered row = %+v", got)
+			}
+			if tt.status != delivered && got.LastError == "" {
+				t.Error("last_error is empty")
+			}
+			if tt.backoff > 0 {
+				if got.Attempts != tt.attempts+1 {
+					t.Errorf("attempts = %d, want %d", got.Attempts, tt.attempts+1)
+				}
+				delay := time.UnixMilli(got.NextAttemptAt).Sub(before)
+				if delay < tt.backoff-time.Second || delay > tt.backoff*6/5+time.Second {
+					t.Errorf("next attempt in %v, want %v plus up to 20%%", delay, tt.backoff)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifierDispatch(t *testing.T) {
+	sender := &fakeSender{}
+	n, db := newTestNotifier(t, sender)
+	pending := int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_PENDING)
+	now := time.Now()
+	db.Create(&NotificationChannelModel{ID: "ch", Kind: int32(notificationpb.ChannelKind_CHANNEL_KIND_WEBHOOK), Enabled: true})
+	db.Create([]NotificationOutboxModel{
+		{ID: "due", ChannelID: "ch", Status: pending, NextAttemptAt: now.Add(-time.Minute).UnixMilli()},
+		{ID: "later", ChannelID: "ch", Status: pending, NextAttemptAt: now.Add(time.Minute).UnixMilli()},
+		{ID: "done", ChannelID: "ch", Status: int32(notificationpb.DeliveryStatus_DELIVERY_STATUS_DELIVERED)},
+	})
+
+	if err := n.dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].ID != "due" {
+		t.Fatalf("sent %+v, want only the due row", sender.sent)
+	}
+	var later NotificationOutboxModel
+	db.First(&later, "id = ?", "later")
+	if later.Attempts != 0 || later.Status != pending {
+		t.Errorf("row not yet due was touched: %+v", later)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{50, time.Hour},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			got := Backoff(tt.attempt, 30*time.Second, time.Hour)
+			if got < tt.want || got > tt.want*6/5 {
+				t.Errorf("Backoff(%d) = %v, want %v plus up to 20%%", tt.attempt, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestWebhookSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		status  int
+		wantErr bool
+	}{
+		{"signed", "s3cret", http.StatusOK, false},
+		{"unsigned", "", http.StatusNoContent, false},
+		{"rejected", "s3cret", http.StatusBadGateway, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			ch := NotificationChannelModel{Target: srv.URL, Secret: tt.secret}
+			msg := NotificationOutboxModel{Payload: `{"event":"firing"}`}
+			err := WebhookSender{Client: srv.Client()}.Send(context.Background(), ch, msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send: %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(body) != msg.Payload || header.Get("Content-Type") != "application/json" {
+				t.Errorf("request %q with headers %v", body, header)
+			}
+			sig := header.Get("X-Team15-Signature")
+			if tt.secret == "" {
+				if sig != "" {
+					t.Errorf("unsigned request carries signature %q", sig)
+				}
+				return
+			}
+			if want := "sha256=" + SignWebhook(tt.secret, header.Get("X-Team15-Timestamp"), body); sig != want {
+				t.Errorf("signature = %q, want %q", sig, want)
+			}
+		})
+	}
+}
+
+func TestSlackSender(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	msg := NotificationOutboxModel{Subject: "[FIRING] errors on payments", Body: "Value: 0.5"}
+	if err := (SlackSender{Client: srv.Client()}).Send(context.Background(), NotificationChannelModel{Target: srv.URL}, msg); err != nil {
+		t.Fatal(err)
+	}
+	if want := "*[FIRING] errors on payments*\nValue: 0.5"; got["text"] != want {
+		t.Errorf("text = %q, want %q", got["text"], want)
+	}
+}
+
+// fakeSMTPServer accepts one plain SMTP session and records the envelope
+// and message.
+type fakeSMTPServer struct {
+	addr string
+	done chan struct{}
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := &fakeSMTPServer{addr: l.Addr().String(), done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case cmd == "EHLO" || cmd == "HELO":
+				reply("250 localhost")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				s.to = append(s.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				s.data = data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return s
+}
+
+func TestSMTPSender(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	sender := SMTPSender{Addr: srv.addr, From: "alerts@example.com"}
+	ch := NotificationChannelModel{Target: "a@example.com, b@example.com,"}
+	msg := NotificationOutboxModel{Subject: "[FIRING] errors", Body: "line one\nline two"}
+	if err := sender.Send(context.Background(), ch, msg); err != nil {
+		t.Fatal(err)
+	}
+	<-srv.done
+	if srv.from != "alerts@example.com" || strings.Join(srv.to, ",") != "a@example.com,b@example.com" {
+		t.Errorf("envelope from %q to %v", srv.from, srv.to)
+	}
+	if !strings.Contains(srv.data, "Subject: [FIRING] errors\r\n") || !strings.Contains(srv.data, "line one\r\nline two") {
+		t.Errorf("message = %q", srv.data)
+	}
+
+	for _, tt := range []struct {
+		sender  SMTPSender
+		target  string
+		subject string
+	}{
+		{SMTPSender{}, "a@example.com", msg.Subject},
+		{SMTPSender{Addr: srv.addr}, " , ", msg.Subject},
+		{SMTPSender{Addr: srv.addr}, "a@example.com\r\nBcc: b@example.com", msg.Subject},
+		{SMTPSender{Addr: srv.addr}, "a@example.com", "[FIRING] x\r\nBcc: b@example.com"},
+	} {
+		msg := NotificationOutboxModel{Subject: tt.subject}
+		if err := tt.sender.Send(context.Background(), NotificationChannelModel{Target: tt.target}, msg); err == nil {
+			t.Errorf("Send(%+v, %q, %q) succeeded, want an error", tt.sender, tt.target, tt.subject)
+		}
+	}
+}
+
+func TestSMTPSenderEncodesSubject(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	sender := SMTPSender{Addr: srv.addr, From: "alerts@example.com"}
+	msg := NotificationOutboxModel{Subject: "[FIRING] latency on café"}
+	if err := sender.Send(context.Background(), NotificationChannelModel{Target: "a@example.com"}, msg); err != nil {
+		t.Fatal(err)
+	}
+	<-srv.done
+	if !strings.Contains(srv.data, "Subject: =?UTF-8?q?[FIRING]_latency_on_caf=C3=A9?=\r\n") {
+		t.Errorf("message = %q", srv.data)
+	}
+}
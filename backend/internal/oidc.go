@@ -0,0 +1,501 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	oidcStateCookie   = "oidc_state"
+	oidcStateAudience = "team15-oidc-state"
+	oidcStateTTL      = 10 * time.Minute
+
+	// oidcDiscoveryTTL is how long the discovery document and JWKS are
+	// cached. An unknown key ID refreshes the JWKS early, at most once per
+	// oidcJWKSMinRefresh, so key rotation is picked up without letting
+	// forged kids hammer the provider.
+	oidcDiscoveryTTL   = time.Hour
+	oidcJWKSMinRefresh = time.Minute
+)
+
+var (
+	oidcLoginsSucceeded = NewCounter("team15_oidc_logins_total",
+		"OIDC logins by outcome.", map[string]string{"outcome": "succeeded"})
+	oidcLoginsFailed = NewCounter("team15_oidc_logins_total",
+		"OIDC logins by outcome.", map[string]string{"outcome": "failed"})
+)
+
+// OIDCConfig configures single sign-on with an OpenID Connect provider.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // empty for a public client, which relies on PKCE alone
+	RedirectURL  string // this backend's /auth/oidc/callback as the provider sees it
+	Scopes       []string
+	// GroupsClaim names the ID token claim listing the user's groups.
+	GroupsClaim string
+	// GroupRoles maps provider groups to local roles.
+	GroupRoles map[string][]string
+	// PostLoginURL is where the browser goes after login unless the login
+	// asked for another local path.
+	PostLoginURL string
+}
+
+// ParseGroupRoles parses group-to-role mappings written as
+// "group=role,other-group=role".
+func ParseGroupRoles(s string) (map[string][]string, error) {
+	m := map[string][]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(group) == "" || strings.TrimSpace(role) == "" {
+			return nil, fmt.Errorf("invalid group mapping %q, want group=role", pair)
+		}
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !slices.Contains(m[group], role) {
+			m[group] = append(m[group], role)
+		}
+	}
+	return m, nil
+}
+
+// mapGroupRoles returns the sorted local roles of groups.
+func mapGroupRoles(groupRoles map[string][]string, groups []string) []string {
+	var roles []string
+	for _, g := range groups {
+		for _, role := range groupRoles[g] {
+			if !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider runs the authorization code flow with PKCE against one
+// provider. The state, nonce and code verifier travel in a signed,
+// short-lived cookie, so any backend instance can finish a login. Users are
+// provisioned on their first login and their roles follow their groups.
+type OIDCProvider struct {
+	cfg      OIDCConfig
+	accounts *Accounts
+	audit    *AuditLog
+	client   *http.Client
+
+	mu           sync.Mutex
+	discovery    *oidcDiscovery
+	discoveredAt time.Time
+	keys         map[string]interface{}
+	keysAt       time.Time
+}
+
+func NewOIDCProvider(cfg OIDCConfig, accounts *Accounts, audit *AuditLog) *OIDCProvider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if cfg.PostLoginURL == "" {
+		cfg.PostLoginURL = "/"
+	}
+	return &OIDCProvider{
+		cfg:      cfg,
+		accounts: accounts,
+		audit:    audit,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Routes mounts /auth/oidc/login and /auth/oidc/callback.
+func (p *OIDCProvider) Routes(r chi.Router) {
+	r.Get("/auth/oidc/login", p.Login)
+	r.Get("/auth/oidc/callback", p.Callback)
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// discover returns the provider's discovery document, cached for an hour.
+// A stale copy is used while the provider cannot be reached.
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil && time.Since(p.discoveredAt) < oidcDiscoveryTTL {
+		return p.discovery, nil
+	}
+	var d oidcDiscovery
+	err := p.getJSON(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", &d)
+	if err == nil && d.Issuer != p.cfg.Issuer {
+		err = fmt.Errorf("discovery document is for issuer %q", d.Issuer)
+	}
+	if err == nil && (d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "") {
+		err = errors.New("discovery document lacks endpoints")
+	}
+	if err != nil {
+		if p.discovery != nil {
+			Logger(ctx).Warn("OIDC discovery failed, using cached document", "error", err)
+			return p.discovery, nil
+		}
+		return nil, err
+	}
+	p.discovery, p.discoveredAt = &d, time.Now()
+	return p.discovery, nil
+}
+
+// key returns the signing key kid from the JWKS.
+func (p *OIDCProvider) key(ctx context.Context, kid string) (interface{}, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.keys[kid]; ok && time.Since(p.keysAt) < oidcDiscoveryTTL {
+		return k, nil
+	}
+	if time.Since(p.keysAt) >= oidcJWKSMinRefresh {
+		var set struct {
+			Keys []jwk `json:"keys"`
+		}
+		if err := p.getJSON(ctx, d.JWKSURI, &set); err != nil {
+			Logger(ctx).Warn("fetching OIDC JWKS failed", "error", err)
+		} else {
+			keys := map[string]interface{}{}
+			for _, k := range set.Keys {
+				if pub, err := k.publicKey(); err == nil && (k.Use == "" || k.Use == "sig") {
+					keys[k.Kid] = pub
+				}
+			}
+			p.keys, p.keysAt = keys, time.Now()
+		}
+	}
+	if k, ok := p.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// jwk is one JSON Web Key; only public RSA and EC keys are used.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	b64 := base64.RawURLEncoding
+	switch k.Kty {
+	case "RSA":
+		n, err1 := b64.DecodeString(k.N)
+		e, err2 := b64.DecodeString(k.E)
+		if err1 != nil || err2 != nil || len(e) > 4 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Crv]
+		x, err1 := b64.DecodeString(k.X)
+		y, err2 := b64.DecodeString(k.Y)
+		if !ok || err1 != nil || err2 != nil {
+			return nil, errors.New("invalid EC key")
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("EC key is not on its curve")
+		}
+		return pub, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// oidcState is the content of the state cookie.
+type oidcState struct {
+	jwt.RegisteredClaims
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	ReturnTo string `json:"return_to,omitempty"`
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// localPath accepts only paths on this site as post-login redirects.
+func localPath(s string) bool {
+	return strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//") && !strings.HasPrefix(s, "/\\")
+}
+
+// Login redirects to the provider. ?return_to=/path picks where the browser
+// lands after login.
+func (p *OIDCProvider) Login(w http.ResponseWriter, r *http.Request) {
+	d, err := p.discover(r.Context())
+	if err != nil {
+		Logger(r.Context()).Error("OIDC discovery failed", "error", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	st := oidcState{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcStateAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(oidcStateTTL)),
+		},
+		State:    randomToken(),
+		Nonce:    randomToken(),
+		Verifier: randomToken(),
+	}
+	if rt := r.URL.Query().Get("return_to"); localPath(rt) {
+		st.ReturnTo = rt
+	}
+	cookie, err := jwt.NewWithClaims(jwt.SigningMethodHS256, st).SignedString(p.accounts.key)
+	if err != nil {
+		http.Error(w, "Token generation failed", http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    cookie,
+		Path:     "/auth/oidc",
+		MaxAge:   int(oidcStateTTL / time.Second),
+		HttpOnly: true,
+		// Lax, not Strict: the provider's redirect back is a cross-site
+		// top-level navigation, which must carry the cookie.
+		SameSite: http.SameSiteLaxMode,
+		Secure:   CookieSecure,
+	})
+
+	challenge := sha256.Sum256([]byte(st.Verifier))
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", st.State)
+	q.Set("nonce", st.Nonce)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	http.Redirect(w, r, d.AuthorizationEndpoint+sep+q.Encode(), http.StatusFound)
+}
+
+// Callback finishes the login: it checks the state, redeems the code with
+// the PKCE verifier, verifies the ID token, provisions the user and sets
+// the same access_token cookie as /login before redirecting to the app.
+func (p *OIDCProvider) Callback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ev := AuditEventModel{Action: "GET /auth/oidc/callback", Resource: "user", SourceIP: HTTPClientIP(r)}
+	fail := func(code int, msg string, err error) {
+		oidcLoginsFailed.Inc()
+		Logger(ctx).Warn("OIDC login failed", "reason", msg, "error", err)
+		ev.Outcome = fmt.Sprint(code)
+		p.audit.record(ctx, ev)
+		http.Error(w, msg, code)
+	}
+
+	// The state cookie is single use.
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/auth/oidc", MaxAge: -1})
+	c, err := r.Cookie(oidcStateCookie)
+	if err != nil {
+		fail(http.StatusBadRequest, "Login expired, please try again", err)
+		return
+	}
+	var st oidcState
+	_, err = jwt.ParseWithClaims(c.Value, &st, func(*jwt.Token) (interface{}, error) {
+		return p.accounts.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(oidcStateAudience))
+	if err != nil || r.URL.Query().Get("state") != st.State {
+		fail(http.StatusBadRequest, "Login expired, please try again", err)
+		return
+	}
+	if e := r.URL.Query().Get("error"); e != "" {
+		fail(http.StatusUnauthorized, "Identity provider refused the login", errors.New(e+": "+r.URL.Query().Get("error_description")))
+		return
+	}
+
+	idToken, err := p.exchange(ctx, r.URL.Query().Get("code"), st.Verifier)
+	if err != nil {
+		fail(http.StatusBadGateway, "Identity provider unavailable", err)
+		return
+	}
+	claims, err := p.verify(ctx, idToken, st.Nonce)
+	if err != nil {
+		fail(http.StatusUnauthorized, "Invalid ID token", err)
+		return
+	}
+
+	sub, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	username, _ := claims["preferred_username"].(string)
+	if username == "" {
+		username = email
+	}
+	if username == "" {
+		username = sub
+	}
+	ev.ResourceID = username
+	roles := mapGroupRoles(p.cfg.GroupRoles, claimStrings(claims[p.cfg.GroupsClaim]))
+	u, err := p.accounts.ProvisionExternal(ctx, "oidc", sub, username, email, roles)
+	if errors.Is(err, ErrUsernameTaken) {
+		fail(http.StatusConflict, "Username already belongs to another account", err)
+		return
+	}
+	if err != nil {
+		fail(http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+	target := p.cfg.PostLoginURL
+	if st.ReturnTo != "" {
+		target = st.ReturnTo
+	}
+	// The same second factor rules apply as to password logins
+	if err := p.accounts.BeginRedirectLogin(w, r, u, target); err != nil {
+		fail(http.StatusInternalServerError, "Token generation failed", err)
+		return
+	}
+
+	oidcLoginsSucceeded.Inc()
+	ev.Outcome = fmt.Sprint(http.StatusFound)
+	p.audit.record(ctx, ev)
+}
+
+// exchange redeems an authorization code for an ID token.
+func (p *OIDCProvider) exchange(ctx context.Context, code, verifier string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", p.cfg.ClientID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("token endpoint: %s: %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("token endpoint: %s: %s %s", resp.Status, body.Error, body.ErrorDescription)
+	}
+	return body.IDToken, nil
+}
+
+// verify checks the ID token's signature against the JWKS, its issuer,
+// audience, expiry and nonce, and returns its claims.
+func (p *OIDCProvider) verify(ctx context.Context, idToken, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, errors.New("no subject")
+	}
+	return claims, nil
+}
+
+// claimStrings reads a claim that is a list of strings, or a single one.
+func claimStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
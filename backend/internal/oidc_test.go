@@ -0,0 +1,370 @@
+package internal
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockOIDC is an OpenID provider: discovery, a token endpoint checking
+// the PKCE verifier of each code, and a JWKS whose keys can be rotated.
+type mockOIDC struct {
+	*httptest.Server
+	t *testing.T
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PrivateKey // published in the JWKS
+	signKid     string
+	grants      map[string]mockGrant
+	jwksFetches int
+}
+
+// mockGrant is what the user authorized: the ID token of a code and the
+// PKCE challenge it was asked with.
+type mockGrant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockOIDC(t *testing.T) *mockOIDC {
+	m := &mockOIDC{t: t, keys: map[string]*rsa.PrivateKey{}, grants: map[string]mockGrant{}}
+	m.rotate("k1")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                m.URL,
+			AuthorizationEndpoint: m.URL + "/authorize",
+			TokenEndpoint:         m.URL + "/token",
+			JWKSURI:               m.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.jwksFetches++
+		var keys []jwk
+		for kid, k := range m.keys {
+			keys = append(keys, jwk{
+				Kty: "RSA", Kid: kid, Use: "sig",
+				N: base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		m.mu.Lock()
+		g, ok := m.grants[r.PostForm.Get("code")]
+		delete(m.grants, r.PostForm.Get("code"))
+		m.mu.Unlock()
+		challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("client_id") != "team15" ||
+			base64.RawURLEncoding.EncodeToString(challenge[:]) != g.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": m.sign(g.claims)})
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// rotate publishes a new key kid and signs with it from now on; the old
+// keys are withdrawn.
+func (m *mockOIDC) rotate(kid string) {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys = map[string]*rsa.PrivateKey{kid: k}
+	m.signKid = kid
+}
+
+func (m *mockOIDC) sign(claims jwt.MapClaims) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[m.signKid]
+	if !ok {
+		m.t.Fatalf("no key %q", m.signKid)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.signKid
+	s, err := token.SignedString(key)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	return s
+}
+
+// authorize stands in for the user logging in at the provider: it returns
+// a code for an ID token with claims.
+func (m *mockOIDC) authorize(challenge string, claims jwt.MapClaims) string {
+	code := randomToken()
+	m.mu.Lock()
+	m.grants[code] = mockGrant{challenge: challenge, claims: claims}
+	m.mu.Unlock()
+	return code
+}
+
+func (m *mockOIDC) idClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                m.URL,
+		"aud":                "team15",
+		"sub":                "u-1",
+		"exp":                time.Now().Add(time.Minute).Unix(),
+		"nonce":              nonce,
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"groups":             []string{"sre", "everyone"},
+	}
+}
+
+func newTestOIDC(t *testing.T) (*OIDCProvider, *mockOIDC) {
+	m := newMockOIDC(t)
+	accounts, db := newTestAccounts(t, nil)
+	p := NewOIDCProvider(OIDCConfig{
+		Issuer:      m.URL,
+		ClientID:    "team15",
+		RedirectURL: "https://app.example.com/auth/oidc/callback",
+		GroupRoles:  map[string][]string{"sre": {roleOperator}, "platform": {roleAdmin}},
+	}, accounts, NewAuditLog(db))
+	return p, m
+}
+
+func TestOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		noCookie bool
+		query    func(q url.Values)            // changes the callback query
+		claims   func(c jwt.MapClaims)         // changes the ID token
+		grant    func(challenge string) string // the challenge the code is bound to
+		want     int
+		roles    []string
+	}{
+		{name: "succeeds", want: http.StatusFound, roles: []string{roleOperator}},
+		{name: "groups change roles", claims: func(c jwt.MapClaims) { c["groups"] = []string{"platform", "sre"} },
+			want: http.StatusFound, roles: []string{roleAdmin, roleOperator}},
+		{name: "no mapped group", claims: func(c jwt.MapClaims) { c["groups"] = "everyone" }, want: http.StatusFound},
+		{name: "missing state cookie", noCookie: true, want: http.StatusBadRequest},
+		{name: "state mismatch", query: func(q url.Values) { q.Set("state", randomToken()) }, want: http.StatusBadRequest},
+		{name: "provider error", query: func(q url.Values) { q.Set("error", "access_denied") }, want: http.StatusUnauthorized},
+		{name: "PKCE verifier mismatch", grant: func(string) string { return "other-challenge" }, want: http.StatusBadGateway},
+		{name: "unknown code", query: func(q url.Values) { q.Set("code", "forged") }, want: http.StatusBadGateway},
+		{name: "nonce mismatch", claims: func(c jwt.MapClaims) { c["nonce"] = randomToken() }, want: http.StatusUnauthorized},
+		{name: "other audience", claims: func(c jwt.MapClaims) { c["aud"] = "other-client" }, want: http.StatusUnauthorized},
+		{name: "other issuer", claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, want: http.StatusUnauthorized},
+		{name: "expired", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, want: http.StatusUnauthorized},
+		{name: "no subject", claims: func(c jwt.MapClaims) { delete(c, "sub") }, want: http.StatusUnauthorized},
+	}
+	p, m := newTestOIDC(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			p.Login(rec, httptest.NewRequest(http.MethodGet, "/auth/oidc/login?return_to=/services/42", nil))
+			if rec.Code != http.StatusFound {
+				t.Fatalf("login = %d", rec.Code)
+			}
+			authURL, _ := url.Parse(rec.Header().Get("Location"))
+			auth := authURL.Query()
+			if !strings.HasPrefix(authURL.String(), m.URL+"/authorize?") || auth.Get("code_challenge_method") != "S256" ||
+				auth.Get("client_id") != "team15" || auth.Get("redirect_uri") != p.cfg.RedirectURL || auth.Get("state") == "" {
+				t.Fatalf("login redirected to %s", authURL)
+			}
+			stateCookie := rec.Result().Cookies()[0]
+
+			claims := m.idClaims(auth.Get("nonce"))
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			challenge := auth.Get("code_challenge")
+			if tt.grant != nil {
+				challenge = tt.grant(challenge)
+			}
+			q := url.Values{"state": {auth.Get("state")}, "code": {m.authorize(challenge, claims)}}
+			if tt.query != nil {
+				tt.query(q)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?"+q.Encode(), nil)
+			if !tt.noCookie {
+				req.AddCookie(stateCookie)
+			}
+			rec = httptest.NewRecorder()
+			p.Callback(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("callback = %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+			var access *http.Cookie
+			for _, c := range rec.Result().Cookies() {
+				if c.Name == "access_token" {
+					access = c
+				}
+			}
+			if tt.want != http.StatusFound {
+				if access != nil {
+					t.Errorf("failed login set an access token")
+				}
+				return
+			}
+			if got := rec.Header().Get("Location"); got != "/services/42" {
+				t.Errorf("redirected to %q", got)
+			}
+			if access == nil {
+				t.Fatal("no access token cookie")
+			}
+			token, err := parseAccessToken(testKey, access.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.Subject != "alice" || !reflect.DeepEqual(token.Roles, tt.roles) {
+				t.Errorf("access token for %s with roles %q, want alice with %q", token.Subject, token.Roles, tt.roles)
+			}
+		})
+	}
+}
+
+// TestOIDCLoginMFA checks that OIDC logins need the same second factor as
+// password logins, handing the challenge to the frontend in the fragment.
+func TestOIDCLoginMFA(t *testing.T) {
+	p, m := newTestOIDC(t)
+	db := p.accounts.db
+	if err := db.Create(&SettingModel{Key: settingMFARequiredForAdmins, Value: "true"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&UserModel{ID: newID(), Username: "carol", Provider: "oidc", ExternalID: "u-3", MFAEnabled: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, sub, username string
+		groups              []string
+		flag                string // "" for a completed login
+		audience            string
+	}{
+		{name: "admin without TOTP", sub: "u-1", username: "alice", groups: []string{"platform"},
+			flag: "mfa_enrollment_required", audience: mfaEnrollAudience},
+		{name: "operator", sub: "u-2", username: "bob", groups: []string{"sre"}},
+		{name: "TOTP enrolled", sub: "u-3", username: "carol", groups: []string{"sre"},
+			flag: "mfa_required", audience: mfaChallengeAudience},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		p.Login(rec, httptest.NewRequest(http.MethodGet, "/auth/oidc/login?return_to=/services/42", nil))
+		auth, _ := url.Parse(rec.Header().Get("Location"))
+		claims := m.idClaims(auth.Query().Get("nonce"))
+		claims["sub"], claims["preferred_username"], claims["groups"] = tt.sub, tt.username, tt.groups
+		q := url.Values{"state": {auth.Query().Get("state")}, "code": {m.authorize(auth.Query().Get("code_challenge"), claims)}}
+		req := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?"+q.Encode(), nil)
+		req.AddCookie(rec.Result().Cookies()[0])
+		rec = httptest.NewRecorder()
+		p.Callback(rec, req)
+
+		if rec.Code != http.StatusFound {
+			t.Errorf("%s: callback = %d %s", tt.name, rec.Code, rec.Body)
+			continue
+		}
+		var access bool
+		for _, c := range rec.Result().Cookies() {
+			access = access || c.Name == "access_token"
+		}
+		target, _ := url.Parse(rec.Header().Get("Location"))
+		if target.Path != "/services/42" || access != (tt.flag == "") {
+			t.Errorf("%s: redirected to %s, access token set %v", tt.name, target, access)
+			continue
+		}
+		if tt.flag == "" {
+			continue
+		}
+		fragment, _ := url.ParseQuery(target.Fragment)
+		challenge, err := parseToken(testKey, fragment.Get("mfa_token"))
+		if fragment.Get(tt.flag) != "true" || err != nil || !reflect.DeepEqual([]string(challenge.Audience), []string{tt.audience}) {
+			t.Errorf("%s: fragment %q (%v), want %s with an MFA token for %s", tt.name, target.Fragment, err, tt.flag, tt.audience)
+		}
+	}
+}
+
+// TestOIDCKeyRotation checks that a token signed with a key published after
+// the JWKS was cached is accepted once the cache may be refreshed, and that
+// unknown kids refresh it at most once per oidcJWKSMinRefresh.
+func TestOIDCKeyRotation(t *testing.T) {
+	p, m := newTestOIDC(t)
+	ctx := t.Context()
+
+	if _, err := p.verify(ctx, m.sign(m.idClaims("n")), "n"); err != nil {
+		t.Fatalf("first key: %v", err)
+	}
+	old := m.sign(m.idClaims("n"))
+	m.rotate("k2")
+	rotated := m.sign(m.idClaims("n"))
+
+	// Just fetched: the new kid is not looked up yet
+	if _, err := p.verify(ctx, rotated, "n"); err == nil {
+		t.Fatal("new key accepted before the JWKS could be refreshed")
+	}
+	if m.jwksFetches != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", m.jwksFetches)
+	}
+
+	p.keysAt = p.keysAt.Add(-oidcJWKSMinRefresh)
+	if _, err := p.verify(ctx, rotated, "n"); err != nil {
+		t.Fatalf("rotated key: %v", err)
+	}
+	if m.jwksFetches != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", m.jwksFetches)
+	}
+	// The withdrawn key is gone with the refresh, and asking for it again
+	// does not refetch
+	for range 3 {
+		if _, err := p.verify(ctx, old, "n"); err == nil {
+			t.Fatal("withdrawn key accepted")
+		}
+	}
+	if m.jwksFetches != 2 {
+		t.Errorf("unknown kids fetched the JWKS %d times, want 2", m.jwksFetches)
+	}
+}
+
+func TestParseGroupRoles(t *testing.T) {
+	tests := []struct {
+		in   string
+		want map[string][]string
+		ok   bool
+	}{
+		{"", map[string][]string{}, true},
+		{"sre=operator, platform=admin,sre=admin,sre=operator", map[string][]string{"sre": {"operator", "admin"}, "platform": {"admin"}}, true},
+		{"sre", nil, false},
+		{"=admin", nil, false},
+		{"sre= ", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseGroupRoles(tt.in)
+		if (err == nil) != tt.ok || (tt.ok && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("ParseGroupRoles(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	groupRoles := map[string][]string{"sre": {"operator"}, "platform": {"admin", "operator"}}
+	if got := mapGroupRoles(groupRoles, []string{"sre", "platform", "everyone"}); !reflect.DeepEqual(got, []string{"admin", "operator"}) {
+		t.Errorf("mapGroupRoles = %q", got)
+	}
+	if got := mapGroupRoles(groupRoles, []string{"everyone"}); got != nil {
+		t.Errorf("mapGroupRoles of unmapped groups = %q, want none", got)
+	}
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// health_metric_models is range-partitioned on timestamp (unix ms) into one
+// partition per UTC day, so queries over a time range only touch the days
+// involved and expired days are dropped instead of deleted row by row.
+// GORM cannot create partitioned tables, so this table is managed here
+// rather than by AutoMigrate; new HealthMetricModel columns must be added
+// to healthMetricColumns.
+
+const (
+	healthPartitionSpan = 24 * time.Hour
+	// healthPartitionsAhead is how many future days always have a partition,
+	// so inserts never fail if the retention job stops for a while.
+	healthPartitionsAhead = 7
+	healthLegacyPartition = healthMetricsTable + "_legacy"
+)
+
+// healthMetricColumns are the columns of health_metric_models besides id.
+// Both the partitioned table and the upgrade of tables from older releases,
+// which lack the later columns, are built from this list.
+var healthMetricColumns = []string{
+	"service_id     text",
+	"status         integer",
+	"latency_ms     integer",
+	"error_rate     decimal",
+	"timestamp      bigint NOT NULL",
+	"in_maintenance boolean",
+	"reasons        text",
+	"anomaly_score  double precision",
+}
+
+func healthMetricsDDL(table string) string {
+	return "CREATE TABLE " + table + " (\n    id             bigserial,\n    " +
+		strings.Join(healthMetricColumns, ",\n    ") +
+		",\n    PRIMARY KEY (id, timestamp)\n) PARTITION BY RANGE (timestamp)"
+}
+
+// addHealthMetricColumns adds the columns of healthMetricColumns that table
+// lacks.
+func addHealthMetricColumns(table string) string {
+	adds := make([]string, len(healthMetricColumns))
+	for i, col := range healthMetricColumns {
+		adds[i] = "ADD COLUMN IF NOT EXISTS " + col
+	}
+	return "ALTER TABLE " + table + " " + strings.Join(adds, ", ")
+}
+
+// migrateHealthMetrics creates the partitioned table, or converts an
+// unpartitioned one from an older release. The old table is attached as a
+// single partition holding everything before its last day, so no rows are
+// copied; it is dropped once all of it has expired.
+func migrateHealthMetrics(db *gorm.DB) error {
+	var kind string
+	err := db.Raw("SELECT relkind FROM pg_class WHERE oid = to_regclass(?)", healthMetricsTable).Scan(&kind).Error
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "p":
+		// Already partitioned
+	case "":
+		if err := db.Exec(healthMetricsDDL(healthMetricsTable)).Error; err != nil {
+			return err
+		}
+	default:
+		if err := convertHealthMetrics(db); err != nil {
+			return fmt.Errorf("partition %s: %w", healthMetricsTable, err)
+		}
+	}
+
+	// Columns added after the table was first partitioned
+	if err := db.Exec(addHealthMetricColumns(healthMetricsTable)).Error; err != nil {
+		return err
+	}
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_health_metric_models_service_timestamp ON " +
+		healthMetricsTable + " (service_id, timestamp)").Error; err != nil {
+		return err
+	}
+	return ensureHealthPartitions(context.Background(), db, time.Now())
+}
+
+func convertHealthMetrics(db *gorm.DB) error {
+	Logger(context.Background()).Info("converting health metrics to a partitioned table", "table", healthMetricsTable)
+	return db.Transaction(func(tx *gorm.DB) error {
+		var bounds struct {
+			MaxID int64
+			MaxTS *int64
+		}
+		err := tx.Raw("SELECT COALESCE(MAX(id), 0) AS max_id, MAX(timestamp) AS max_ts FROM " + healthMetricsTable).
+			Scan(&bounds).Error
+		if err != nil {
+			return err
+		}
+
+		stmts := []string{
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", healthMetricsTable, healthLegacyPartition),
+			fmt.Sprintf("ALTER INDEX %s_pkey RENAME TO %s_pkey", healthMetricsTable, healthLegacyPartition),
+			healthMetricsDDL(healthMetricsTable),
+			fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), %d)", healthMetricsTable, bounds.MaxID+1),
+		}
+		if bounds.MaxTS == nil {
+			stmts = append(stmts, "DROP TABLE "+healthLegacyPartition)
+		} else {
+			upper := (*bounds.MaxTS/healthPartitionSpan.Milliseconds() + 1) * healthPartitionSpan.Milliseconds()
+			stmts = append(stmts,
+				// Partitions must match the parent's columns and NOT NULL key
+				addHealthMetricColumns(healthLegacyPartition),
+				fmt.Sprintf("ALTER TABLE %s ALTER COLUMN timestamp SET NOT NULL", healthLegacyPartition),
+				fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM (MINVALUE) TO (%d)",
+					healthMetricsTable, healthLegacyPartition, upper),
+			)
+		}
+		for _, stmt := range stmts {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// healthPartition is one partition of health_metric_models covering [from, to).
+type healthPartition struct {
+	name     string
+	from, to int64
+	// detaching is set when an earlier concurrent detach was interrupted
+	detaching bool
+}
+
+var partitionBoundRE = regexp.MustCompile(`FROM \((.+)\) TO \((.+)\)`)
+
+func parsePartitionBound(v string) (int64, error) {
+	switch v = strings.Trim(v, "'"); v {
+	case "MINVALUE":
+		return math.MinInt64, nil
+	case "MAXVALUE":
+		return math.MaxInt64, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// healthPartitions lists the partitions in time order.
+func healthPartitions(ctx context.Context, db *gorm.DB) ([]healthPartition, error) {
+	var rows []struct {
+		Name    string
+		Bound   string
+		Pending bool
+	}
+	err := db.WithContext(ctx).Raw(`SELECT c.relname AS name, pg_get_expr(c.relpartbound, c.oid) AS bound,
+    i.inhdetachpending AS pending
+FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = to_regclass(?)`, healthMetricsTable).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]healthPartition, 0, len(rows))
+	for _, r := range rows {
+		m := partitionBoundRE.FindStringSubmatch(r.Bound)
+		if m == nil {
+			return nil, fmt.Errorf("unexpected bound %q of partition %s", r.Bound, r.Name)
+		}
+		from, err := parsePartitionBound(m[1])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parsePartitionBound(m[2])
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, healthPartition{name: r.Name, from: from, to: to, detaching: r.Pending})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].from < parts[j].from })
+	return parts, nil
+}
+
+// ensureHealthPartitions creates the partitions for today and the next
+// healthPartitionsAhead days that do not exist yet.
+func ensureHealthPartitions(ctx context.Context, db *gorm.DB, now time.Time) error {
+	parts, err := healthPartitions(ctx, db)
+	if err != nil {
+		return err
+	}
+	span := healthPartitionSpan.Milliseconds()
+	today := now.UnixMilli() / span * span
+
+	for day := today; day <= today+healthPartitionsAhead*span; day += span {
+		covered := false
+		for _, p := range parts {
+			if p.from < day+span && day < p.to {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		name := fmt.Sprintf("%s_p%s", healthMetricsTable, time.UnixMilli(day).UTC().Format("20060102"))
+		stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM (%d) TO (%d)",
+			name, healthMetricsTable, day, day+span)
+		if err := db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			return err
+		}
+		Logger(ctx).Info("created health metrics partition", "partition", name)
+	}
+	return nil
+}
+
+// dropHealthPartitionsBefore drops every partition that ends at or before
+// cutoff. Partitions are detached concurrently (Postgres 14+) so inserts
+// and queries on the other days are not blocked.
+func dropHealthPartitionsBefore(ctx context.Context, db *gorm.DB, cutoff int64) ([]string, error) {
+	parts, err := healthPartitions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var dropped []string
+	for _, p := range parts {
+		if p.to > cutoff {
+			break
+		}
+		detach := "CONCURRENTLY"
+		if p.detaching {
+			detach = "FINALIZE"
+		}
+		stmts := []string{
+			fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s %s", healthMetricsTable, p.name, detach),
+			"DROP TABLE " + p.name,
+		}
+		for _, stmt := range stmts {
+			if err := db.WithContext(ctx).Exec(stmt).Error; err != nil {
+				return dropped, err
+			}
+		}
+		dropped = append(dropped, p.name)
+	}
+	return dropped, nil
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPasswordBytes is where bcrypt stops reading a password.
+const maxPasswordBytes = 72
+
+// PasswordPolicy decides which new passwords are acceptable: long enough,
+// not the username, and not on a list of breached passwords.
+type PasswordPolicy struct {
+	MinLength int
+	// breached holds the upper-case hex SHA-1 of each breached password.
+	breached map[string]struct{}
+}
+
+// LoadPasswordPolicy returns a policy with the breached passwords listed in
+// file, if any. Lines are either passwords or the SHA-1 hashes of the Have
+// I Been Pwned downloads ("<40 hex digits>[:count]").
+func LoadPasswordPolicy(minLength int, file string) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{MinLength: minLength, breached: map[string]struct{}{}}
+	if file == "" {
+		return p, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		hash, _, _ := strings.Cut(line, ":")
+		if len(hash) == sha1.Size*2 && isHex(hash) {
+			p.breached[strings.ToUpper(hash)] = struct{}{}
+		} else {
+			p.breached[passwordSHA1(line)] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	return p, nil
+}
+
+func passwordSHA1(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// Check returns why password is not acceptable for username, or nil.
+func (p *PasswordPolicy) Check(username, password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("password must be at most %d bytes", maxPasswordBytes)
+	}
+	if strings.EqualFold(password, username) {
+		return errors.New("password must not be the username")
+	}
+	if _, ok := p.breached[passwordSHA1(password)]; ok {
+		return errors.New("password appears in a list of breached passwords")
+	}
+	return nil
+}
+
+// BreachedCount is the number of breached passwords loaded.
+func (p *PasswordPolicy) BreachedCount() int {
+	return len(p.breached)
+}
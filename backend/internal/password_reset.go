@@ -0,0 +1,417 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	purposePasswordReset = "password_reset"
+	purposeVerifyEmail   = "verify_email"
+
+	passwordResetTTL = 30 * time.Minute
+	verifyEmailTTL   = 24 * time.Hour
+	// passwordResetEvery limits how often reset emails go to one user.
+	passwordResetEvery = time.Minute
+	mailTimeout        = 30 * time.Second
+)
+
+// errInvalidAccountToken is returned for an unknown, used or expired token.
+var errInvalidAccountToken = errors.New("invalid or expired token")
+
+// issueAccountToken creates a single-use token for purpose, replacing any
+// unused one the user already has for it, and returns the secret.
+func (a *Accounts) issueAccountToken(ctx context.Context, u *UserModel, purpose string, ttl time.Duration) (string, error) {
+	secret := randomToken()
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at = 0", u.ID, purpose).Delete(&AccountTokenModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&AccountTokenModel{
+			ID:        newID(),
+			UserID:    u.ID,
+			Purpose:   purpose,
+			TokenHash: hashServiceToken(secret),
+			Email:     u.Email,
+			ExpiresAt: time.Now().Add(ttl).UnixMilli(),
+		}).Error
+	})
+	return secret, err
+}
+
+// accountToken looks up an unused, unexpired token and its user.
+func (a *Accounts) accountToken(ctx context.Context, purpose, secret string) (*AccountTokenModel, *UserModel, error) {
+	if secret == "" {
+		return nil, nil, errInvalidAccountToken
+	}
+	var t AccountTokenModel
+	err := a.db.WithContext(ctx).Where("token_hash = ? AND purpose = ? AND used_at = 0 AND expires_at > ?",
+		hashServiceToken(secret), purpose, time.Now().UnixMilli()).Take(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, errInvalidAccountToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var u UserModel
+	err = a.db.WithContext(ctx).Where("id = ?", t.UserID).Take(&u).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, errInvalidAccountToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &t, &u, nil
+}
+
+// useAccountToken marks a token used within tx. Of two concurrent uses
+// only one succeeds.
+func useAccountToken(tx *gorm.DB, t *AccountTokenModel) error {
+	result := tx.Model(&AccountTokenModel{}).Where("id = ? AND used_at = 0", t.ID).Update("used_at", time.Now().UnixMilli())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errInvalidAccountToken
+	}
+	return nil
+}
+
+func (a *Accounts) link(path, secret string) string {
+	return a.appURL + path + "?token=" + url.QueryEscape(secret)
+}
+
+// ForgotPassword mails a reset link for `{"username": ...}` or
+// `{"email": ...}`. Only local users with a verified email get one, but
+// the answer is the same for everyone, so it does not reveal which
+// accounts exist; the lookup and email happen after responding.
+func (a *Accounts) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || (body.Username == "" && body.Email == "") {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	SetAuditResource(r.Context(), "user", strings.ToLower(body.Username))
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), mailTimeout)
+	go func() {
+		defer cancel()
+		if err := a.sendPasswordReset(ctx, body.Username, body.Email); err != nil {
+			Logger(ctx).Error("sending password reset failed", "error", err)
+		}
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (a *Accounts) sendPasswordReset(ctx context.Context, username, email string) error {
+	q := a.db.WithContext(ctx).Where("provider = '' AND email_verified = ?", true)
+	if username != "" {
+		q = q.Where("username = ?", strings.ToLower(username))
+	} else {
+		q = q.Where("LOWER(email) = ?", strings.ToLower(email))
+	}
+	var users []UserModel
+	if err := q.Limit(2).Find(&users).Error; err != nil {
+		return err
+	}
+	if len(users) != 1 {
+		Logger(ctx).Info("password reset requested for no single verified account")
+		return nil
+	}
+	u := &users[0]
+
+	var recent int64
+	err := a.db.WithContext(ctx).Model(&AccountTokenModel{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?", u.ID, purposePasswordReset, time.Now().Add(-passwordResetEvery).UnixMilli()).
+		Count(&recent).Error
+	if err != nil {
+		return err
+	}
+	if recent > 0 {
+		Logger(ctx).Info("password reset already sent recently", "username", u.Username)
+		return nil
+	}
+
+	secret, err := a.issueAccountToken(ctx, u, purposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+	Logger(ctx).Info("password reset sent", "username", u.Username)
+	return a.mailer.SendMail(ctx, u.Email, "Reset your team15 password", fmt.Sprintf(
+		"Someone asked to reset the password of your team15 account %s.\n\n"+
+			"To choose a new password, open this link within %d minutes:\n\n%s\n\n"+
+			"If that was not you, ignore this email; your password stays the same.\n",
+		u.Username, int(passwordResetTTL.Minutes()), a.link("/reset-password", secret)))
+}
+
+// ResetPassword sets a new password with `{"token": ..., "password": ...}`
+// and logs the user out everywhere. A password the policy rejects leaves
+// the token usable.
+func (a *Accounts) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	t, u, err := a.accountToken(r.Context(), purposePasswordReset, body.Token)
+	if errors.Is(err, errInvalidAccountToken) {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	SetAuditResource(r.Context(), "user", u.Username)
+	if err := a.policy.Check(u.Username, body.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(body.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	before := u.PasswordHash
+	err = a.db.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := useAccountToken(tx, t); err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"password_hash": string(hash)}
+		if u.Email == t.Email {
+			// The link reached the address, so it is verified as well.
+			updates["email_verified"] = true
+		}
+		return tx.Model(u).Updates(updates).Error
+	})
+	if errors.Is(err, errInvalidAccountToken) {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	SetPrincipal(r.Context(), u.Username)
+	AuditChange(r.Context(), "password_hash", before, string(hash))
+	a.guard.Succeeded(r.Context(), u.Username)
+	// Whoever knew the old password is logged out.
+	if _, err := a.sessions.RevokeAll(r.Context(), u.Username); err != nil {
+		Logger(r.Context()).Error("revoking sessions after password reset failed", "error", err)
+	}
+	Logger(r.Context()).Info("password reset", "username", u.Username)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sendVerification mails u a link that verifies their current email.
+func (a *Accounts) sendVerification(ctx context.Context, u *UserModel) error {
+	secret, err := a.issueAccountToken(ctx, u, purposeVerifyEmail, verifyEmailTTL)
+	if err != nil {
+		return err
+	}
+	return a.mailer.SendMail(ctx, u.Email, "Verify your team15 email", fmt.Sprintf(
+		"Please confirm that %s is the email of your team15 account %s by opening this link within %d hours:\n\n%s\n\n"+
+			"If you do not have a team15 account, ignore this email.\n",
+		u.Email, u.Username, int(verifyEmailTTL.Hours()), a.link("/verify-email", secret)))
+}
+
+// parseEmail accepts a bare address such as ops@example.com.
+func parseEmail(s string) (string, error) {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return "", fmt.Errorf("invalid email %q", s)
+	}
+	return addr.Address, nil
+}
+
+// ChangeEmail sets the caller's email from `{"email": ...}` and mails a
+// verification link; posting the same email again resends it. It must run
+// behind RequireJWT.
+func (a *Accounts) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	email, err := parseEmail(body.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	u, err := a.user(r.Context(), PrincipalFromContext(r.Context()))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Only users have an email", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if u.Provider != "" {
+		http.Error(w, "The email of "+u.Provider+" users comes from "+u.Provider, http.StatusConflict)
+		return
+	}
+	SetAuditResource(r.Context(), "user", u.Username)
+	if u.Email == email && u.EmailVerified {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"email": u.Email, "email_verified": true})
+		return
+	}
+
+	if u.Email != email {
+		err := a.db.WithContext(r.Context()).Model(u).
+			Updates(map[string]interface{}{"email": email, "email_verified": false}).Error
+		if err != nil {
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		AuditChange(r.Context(), "email", u.Email, email)
+		u.Email, u.EmailVerified = email, false
+	}
+	if err := a.sendVerification(r.Context(), u); err != nil {
+		Logger(r.Context()).Error("sending verification email failed", "error", err)
+		http.Error(w, "Verification email could not be sent", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{"email": u.Email, "email_verified": false})
+}
+
+// VerifyEmail marks an email verified with `{"token": ...}` from the link.
+func (a *Accounts) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	t, u, err := a.accountToken(r.Context(), purposeVerifyEmail, body.Token)
+	if err == nil && t.Email != u.Email {
+		// The email changed again after this link was sent.
+		err = errInvalidAccountToken
+	}
+	if errors.Is(err, errInvalidAccountToken) {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	SetAuditResource(r.Context(), "user", u.Username)
+	err = a.db.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := useAccountToken(tx, t); err != nil {
+			return err
+		}
+		return tx.Model(u).Update("email_verified", true).Error
+	})
+	if errors.Is(err, errInvalidAccountToken) {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	AuditChange(r.Context(), "email_verified", false, true)
+	Logger(r.Context()).Info("email verified", "username", u.Username)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"email": u.Email, "email_verified": true})
+}
+
+// CreateUser creates a local user from `{"username": ..., "password": ...,
+// "email": ..., "roles": [...]}` and mails them a verification link, if
+// there is a mailer.
+func (a *Accounts) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Username string   `json:"username"`
+		Password string   `json:"password"`
+		Email    string   `json:"email"`
+		Roles    []string `json:"roles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Username == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	u := &UserModel{
+		ID:       newID(),
+		Username: strings.ToLower(body.Username),
+		Roles:    body.Roles,
+	}
+	SetAuditResource(r.Context(), "user", u.Username)
+	if strings.Contains(u.Username, ":") {
+		http.Error(w, "Username must not contain ':'", http.StatusBadRequest)
+		return
+	}
+	if body.Email != "" {
+		email, err := parseEmail(body.Email)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		u.Email = email
+	}
+	if err := a.policy.Check(u.Username, body.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(body.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	u.PasswordHash = string(hash)
+
+	var taken int64
+	if err := a.db.WithContext(r.Context()).Model(&UserModel{}).Where("username = ?", u.Username).Count(&taken).Error; err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if taken > 0 {
+		http.Error(w, "Username already exists", http.StatusConflict)
+		return
+	}
+	if err := a.db.WithContext(r.Context()).Create(u).Error; err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	AuditChange(r.Context(), "roles", nil, u.Roles)
+	Logger(r.Context()).Info("user created", "username", u.Username, "roles", u.Roles)
+
+	if u.Email != "" && a.mailer != nil {
+		if err := a.sendVerification(r.Context(), u); err != nil {
+			// The user can ask for another link by setting their email.
+			Logger(r.Context()).Error("sending verification email failed", "error", err)
+		}
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":             u.ID,
+		"username":       u.Username,
+		"email":          u.Email,
+		"email_verified": false,
+		"roles":          u.Roles,
+	})
+}
+
+// AdminRoutes mounts /users for admins.
+func (a *Accounts) AdminRoutes(r chi.Router) {
+	r.Post("/users", a.CreateUser)
+}
@@ -0,0 +1,271 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var linkToken = regexp.MustCompile(`\?token=(\S+)`)
+
+// mailedToken returns the token of the link in an email.
+func mailedToken(t *testing.T, m sentMail) string {
+	t.Helper()
+	match := linkToken.FindStringSubmatch(m.Body)
+	if match == nil {
+		t.Fatalf("no link in %q", m.Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// waitForMail waits for the n-th email, which some handlers send after
+// responding.
+func waitForMail(t *testing.T, m *memoryMailer, n int) sentMail {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if sent := m.Sent(); len(sent) >= n {
+			return sent[n-1]
+		}
+	}
+	t.Fatalf("email %d was not sent", n)
+	return sentMail{}
+}
+
+func serveJSON(h http.Handler, method, target, body, bearer string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTestUser(t *testing.T, a *Accounts, u UserModel) *UserModel {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("old password 1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.ID, u.PasswordHash = newID(), string(hash)
+	if err := a.db.Create(&u).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &u
+}
+
+func TestPasswordResetRecipients(t *testing.T) {
+	mailer := &memoryMailer{}
+	a, _ := newTestAccounts(t, mailer)
+	newTestUser(t, a, UserModel{Username: "alice", Email: "Alice@example.com", EmailVerified: true})
+	newTestUser(t, a, UserModel{Username: "bob", Email: "bob@example.com"})
+	newTestUser(t, a, UserModel{Username: "carol", Email: "carol@example.com", EmailVerified: true, Provider: "ldap", ExternalID: "uid=carol"})
+	newTestUser(t, a, UserModel{Username: "dave", Email: "shared@example.com", EmailVerified: true})
+	newTestUser(t, a, UserModel{Username: "erin", Email: "shared@example.com", EmailVerified: true})
+
+	tests := []struct {
+		name            string
+		username, email string
+		to              string // "" for no email
+	}{
+		{"by email ignoring case", "", "alice@EXAMPLE.com", "Alice@example.com"},
+		{"again within a minute", "Alice", "", ""},
+		{"unverified email", "bob", "", ""},
+		{"unverified email by email", "", "bob@example.com", ""},
+		{"directory user", "carol", "", ""},
+		{"email of two users", "", "shared@example.com", ""},
+		{"one of two users by username", "dave", "", "shared@example.com"},
+		{"unknown user", "mallory", "", ""},
+	}
+	for _, tt := range tests {
+		before := len(mailer.Sent())
+		if err := a.sendPasswordReset(context.Background(), tt.username, tt.email); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		sent := mailer.Sent()[before:]
+		switch {
+		case tt.to == "" && len(sent) > 0:
+			t.Errorf("%s: mailed %s", tt.name, sent[0].To)
+		case tt.to != "" && (len(sent) != 1 || sent[0].To != tt.to):
+			t.Errorf("%s: sent %+v, want one email to %s", tt.name, sent, tt.to)
+		}
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	mailer := &memoryMailer{}
+	a, _ := newTestAccounts(t, mailer)
+	router := chi.NewRouter()
+	a.Routes(router)
+	u := newTestUser(t, a, UserModel{Username: "alice", Email: "alice@example.com", EmailVerified: true})
+	session, err := a.sessions.Create(context.Background(), "alice", "test", "", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := serveJSON(router, http.MethodPost, "/auth/password/forgot", `{"username": "alice"}`, ""); rec.Code != http.StatusAccepted {
+		t.Fatalf("forgot = %d", rec.Code)
+	}
+	m := waitForMail(t, mailer, 1)
+	if m.To != "alice@example.com" || !strings.Contains(m.Body, "https://app.example.com/reset-password?token=") {
+		t.Fatalf("reset email = %+v", m)
+	}
+	token := mailedToken(t, m)
+	verifyToken, err := a.issueAccountToken(context.Background(), u, purposeVerifyEmail, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []struct {
+		name     string
+		token    string
+		password string
+		want     int
+	}{
+		{"unknown token", "forged", "new password 1", http.StatusBadRequest},
+		{"verification token", verifyToken, "new password 1", http.StatusBadRequest},
+		{"weak password", token, "short", http.StatusUnprocessableEntity},
+		{"reset", token, "new password 1", http.StatusNoContent}, // the weak password left it usable
+		{"used token", token, "new password 2", http.StatusBadRequest},
+	}
+	for _, st := range steps {
+		body := `{"token": "` + st.token + `", "password": "` + st.password + `"}`
+		if rec := serveJSON(router, http.MethodPost, "/auth/password/reset", body, ""); rec.Code != st.want {
+			t.Errorf("%s: %d %s, want %d", st.name, rec.Code, rec.Body, st.want)
+		}
+	}
+
+	if _, err := a.Authenticate(context.Background(), "alice", "new password 1"); err != nil {
+		t.Errorf("new password: %v", err)
+	}
+	if _, err := a.Authenticate(context.Background(), "alice", "old password 1"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("old password: %v", err)
+	}
+	if err := a.sessions.Check(context.Background(), session); !errors.Is(err, errSessionRevoked) {
+		t.Errorf("session before the reset: %v, want revoked", err)
+	}
+}
+
+func TestPasswordResetExpired(t *testing.T) {
+	mailer := &memoryMailer{}
+	a, _ := newTestAccounts(t, mailer)
+	router := chi.NewRouter()
+	a.Routes(router)
+	u := newTestUser(t, a, UserModel{Username: "alice", Email: "alice@example.com", EmailVerified: true})
+
+	token, err := a.issueAccountToken(context.Background(), u, purposePasswordReset, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := serveJSON(router, http.MethodPost, "/auth/password/reset", `{"token": "`+token+`", "password": "new password 1"}`, ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expired token = %d, want 400", rec.Code)
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	mailer := &memoryMailer{}
+	a, _ := newTestAccounts(t, mailer)
+	router := chi.NewRouter()
+	a.Routes(router)
+	newTestUser(t, a, UserModel{Username: "alice"})
+	newTestUser(t, a, UserModel{Username: "carol", Provider: "ldap", ExternalID: "uid=carol"})
+	bearer := func(username string) string {
+		token, _, err := issueToken(testKey, "", username, nil, "", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	verify := func(token string) int {
+		return serveJSON(router, http.MethodPost, "/auth/email/verify", `{"token": "`+token+`"}`, "").Code
+	}
+
+	tests := []struct {
+		name   string
+		user   string
+		body   string
+		want   int
+		mailTo string
+	}{
+		{"invalid email", "alice", `{"email": "Alice <alice@example.com>"}`, http.StatusBadRequest, ""},
+		{"directory user", "carol", `{"email": "carol@example.com"}`, http.StatusConflict, ""},
+		{"not a user", "sa:deploy", `{"email": "deploy@example.com"}`, http.StatusForbidden, ""},
+		{"first email", "alice", `{"email": "old@example.com"}`, http.StatusAccepted, "old@example.com"},
+		{"changed before verifying", "alice", `{"email": "alice@example.com"}`, http.StatusAccepted, "alice@example.com"},
+	}
+	var links []string
+	for _, tt := range tests {
+		before := len(mailer.Sent())
+		if rec := serveJSON(router, http.MethodPut, "/auth/email/", tt.body, bearer(tt.user)); rec.Code != tt.want {
+			t.Errorf("%s: %d %s, want %d", tt.name, rec.Code, rec.Body, tt.want)
+		}
+		sent := mailer.Sent()[before:]
+		if tt.mailTo == "" {
+			if len(sent) > 0 {
+				t.Errorf("%s: mailed %s", tt.name, sent[0].To)
+			}
+			continue
+		}
+		if len(sent) != 1 || sent[0].To != tt.mailTo || !strings.Contains(sent[0].Body, "https://app.example.com/verify-email?token=") {
+			t.Fatalf("%s: sent %+v, want a link to %s", tt.name, sent, tt.mailTo)
+		}
+		links = append(links, mailedToken(t, sent[0]))
+	}
+
+	if code := verify(links[0]); code != http.StatusBadRequest {
+		t.Errorf("link to the replaced email = %d, want 400", code)
+	}
+	if code := verify(links[1]); code != http.StatusOK {
+		t.Errorf("link = %d, want 200", code)
+	}
+	if code := verify(links[1]); code != http.StatusBadRequest {
+		t.Errorf("used link = %d, want 400", code)
+	}
+	u, _ := a.user(context.Background(), "alice")
+	if u.Email != "alice@example.com" || !u.EmailVerified {
+		t.Errorf("user has %s, verified %v", u.Email, u.EmailVerified)
+	}
+
+	// Setting the verified email again sends nothing
+	before := len(mailer.Sent())
+	if rec := serveJSON(router, http.MethodPut, "/auth/email/", `{"email": "alice@example.com"}`, bearer("alice")); rec.Code != http.StatusOK || len(mailer.Sent()) != before {
+		t.Errorf("same email = %d, %d emails sent", rec.Code, len(mailer.Sent())-before)
+	}
+}
+
+// TestAccountsWithoutMailer checks that without a mailer the endpoints
+// that mail links are not mounted and users are created unverified.
+func TestAccountsWithoutMailer(t *testing.T) {
+	a, _ := newTestAccounts(t, nil)
+	router := chi.NewRouter()
+	a.Routes(router)
+	router.Route("/admin", a.AdminRoutes)
+
+	for _, target := range []string{"/auth/password/forgot", "/auth/password/reset", "/auth/email/verify"} {
+		if rec := serveJSON(router, http.MethodPost, target, `{}`, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("POST %s = %d, want 404", target, rec.Code)
+		}
+	}
+	if rec := serveJSON(router, http.MethodPost, "/auth/mfa/enroll", `{}`, ""); rec.Code == http.StatusNotFound {
+		t.Error("MFA routes are not mounted")
+	}
+	rec := serveJSON(router, http.MethodPost, "/admin/users", `{"username": "ana", "password": "ana password 1", "email": "ana@example.com"}`, "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create user = %d %s", rec.Code, rec.Body)
+	}
+	if u, err := a.user(context.Background(), "ana"); err != nil || u.Email != "ana@example.com" || u.EmailVerified {
+		t.Errorf("created %+v, %v", u, err)
+	}
+}
@@ -0,0 +1,18 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PostgresDSN builds the connection string for a local/Docker host or,
+// when host is a Cloud SQL connection name (project:region:instance), for
+// the Cloud SQL unix socket.
+func PostgresDSN(host, user, password, name, port string) string {
+	if strings.Contains(host, ":") {
+		return fmt.Sprintf("host=/cloudsql/%s user=%s password=%s dbname=%s sslmode=disable",
+			host, user, password, name)
+	}
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		host, user, password, name, port)
+}
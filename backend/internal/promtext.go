@@ -0,0 +1,299 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// promSample is one sample line of the Prometheus text exposition format.
+type promSample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// parsePromText parses the Prometheus text exposition format (version
+// 0.0.4). It returns every sample and the declared type of each metric
+// family. Timestamps are ignored.
+func parsePromText(r io.Reader) ([]promSample, map[string]string, error) {
+	var samples []promSample
+	types := map[string]string{}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			fields := strings.Fields(line)
+			if len(fields) >= 4 && fields[1] == "TYPE" {
+				types[fields[2]] = fields[3]
+			}
+			continue
+		}
+		s, err := parsePromSample(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, types, sc.Err()
+}
+
+func parsePromSample(line string) (promSample, error) {
+	s := promSample{Labels: map[string]string{}}
+
+	i := strings.IndexAny(line, "{ \t")
+	if i <= 0 {
+		return s, fmt.Errorf("invalid sample %q", line)
+	}
+	s.Name, line = line[:i], line[i:]
+
+	if line[0] == '{' {
+		var err error
+		if line, err = parsePromLabels(line[1:], s.Labels); err != nil {
+			return s, err
+		}
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 || len(fields) > 2 {
+		return s, fmt.Errorf("invalid value in %q", line)
+	}
+	v, err := parsePromValue(fields[0])
+	if err != nil {
+		return s, err
+	}
+	s.Value = v
+	return s, nil
+}
+
+// parsePromLabels parses `name="value",...}` into labels and returns the
+// rest of the line.
+func parsePromLabels(line string, labels map[string]string) (string, error) {
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(line, "}") {
+			return line[1:], nil
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || len(line) < eq+2 || line[eq+1] != '"' {
+			return "", fmt.Errorf("invalid label in %q", line)
+		}
+		name := strings.TrimSpace(line[:eq])
+		line = line[eq+2:]
+
+		var value strings.Builder
+		closed := false
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			if c == '\\' && j+1 < len(line) {
+				j++
+				switch line[j] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(line[j])
+				}
+				continue
+			}
+			if c == '"' {
+				line, closed = line[j+1:], true
+				break
+			}
+			value.WriteByte(c)
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated label value for %q", name)
+		}
+		labels[name] = value.String()
+
+		line = strings.TrimLeft(line, " \t")
+		line = strings.TrimPrefix(line, ",")
+	}
+}
+
+func parsePromValue(v string) (float64, error) {
+	switch v {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+// promSelector picks series by metric name and label matchers, written as
+// in PromQL: name{label="v",other!="v",code=~"5.."}.
+type promSelector struct {
+	Name     string
+	matchers []promMatcher
+}
+
+type promMatcher struct {
+	label string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+var promMatcherRE = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*"((?:[^"\\]|\\.)*)"\s*$`)
+
+func parsePromSelector(s string) (*promSelector, error) {
+	s = strings.TrimSpace(s)
+	name, rest, hasLabels := strings.Cut(s, "{")
+	sel := &promSelector{Name: strings.TrimSpace(name)}
+	if sel.Name == "" {
+		return nil, fmt.Errorf("selector %q needs a metric name", s)
+	}
+	if !hasLabels {
+		return sel, nil
+	}
+	body, ok := strings.CutSuffix(strings.TrimSpace(rest), "}")
+	if !ok {
+		return nil, fmt.Errorf("selector %q is missing }", s)
+	}
+	for _, part := range splitPromMatchers(body) {
+		m := promMatcherRE.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("invalid matcher %q", part)
+		}
+		value, err := strconv.Unquote(`"` + m[3] + `"`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q", part)
+		}
+		pm := promMatcher{label: m[1], op: m[2], value: value}
+		if pm.op == "=~" || pm.op == "!~" {
+			if pm.re, err = regexp.Compile("^(?:" + value + ")$"); err != nil {
+				return nil, fmt.Errorf("invalid regexp in %q: %w", part, err)
+			}
+		}
+		sel.matchers = append(sel.matchers, pm)
+	}
+	return sel, nil
+}
+
+// splitPromMatchers splits on commas outside quoted values.
+func splitPromMatchers(s string) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == ',' && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+func (sel *promSelector) matches(s promSample, name string) bool {
+	if s.Name != name {
+		return false
+	}
+	for _, m := range sel.matchers {
+		v := s.Labels[m.label]
+		var ok bool
+		switch m.op {
+		case "=":
+			ok = v == m.value
+		case "!=":
+			ok = v != m.value
+		case "=~":
+			ok = m.re.MatchString(v)
+		case "!~":
+			ok = !m.re.MatchString(v)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sum adds up the series of the selector's metric (or of name, for the
+// _bucket/_sum/_count series of a histogram) that match.
+func (sel *promSelector) sum(samples []promSample, name string) (float64, bool) {
+	total, found := 0.0, false
+	for _, s := range samples {
+		if sel.matches(s, name) {
+			total += s.Value
+			found = true
+		}
+	}
+	return total, found
+}
+
+// histogramBucket is a cumulative histogram bucket.
+type histogramBucket struct {
+	upper float64
+	count float64
+}
+
+// buckets sums the matching <name>_bucket series per upper bound.
+func (sel *promSelector) buckets(samples []promSample) []histogramBucket {
+	byLE := map[float64]float64{}
+	for _, s := range samples {
+		if !sel.matches(s, sel.Name+"_bucket") {
+			continue
+		}
+		le, err := parsePromValue(s.Labels["le"])
+		if err != nil {
+			continue
+		}
+		byLE[le] += s.Value
+	}
+	out := make([]histogramBucket, 0, len(byLE))
+	for le, c := range byLE {
+		out = append(out, histogramBucket{upper: le, count: c})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].upper < out[j].upper })
+	return out
+}
+
+// histogramQuantile estimates the q-quantile from cumulative buckets by
+// linear interpolation within the bucket, like PromQL's histogram_quantile.
+func histogramQuantile(q float64, buckets []histogramBucket) float64 {
+	if len(buckets) == 0 || !math.IsInf(buckets[len(buckets)-1].upper, 1) {
+		return math.NaN()
+	}
+	total := buckets[len(buckets)-1].count
+	if total <= 0 {
+		return math.NaN()
+	}
+	rank := q * total
+	i := sort.Search(len(buckets), func(i int) bool { return buckets[i].count >= rank })
+	if i == len(buckets)-1 {
+		// Falls in the +Inf bucket: the best estimate is the highest finite bound
+		if i == 0 {
+			return math.NaN()
+		}
+		return buckets[i-1].upper
+	}
+	lower, prevCount := 0.0, 0.0
+	if i > 0 {
+		lower, prevCount = buckets[i-1].upper, buckets[i-1].count
+	}
+	inBucket := buckets[i].count - prevCount
+	if inBucket <= 0 {
+		return buckets[i].upper
+	}
+	return lower + (buckets[i].upper-lower)*(rank-prevCount)/inBucket
+}
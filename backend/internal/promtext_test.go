@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePromText(t *testing.T) {
+	in := `# HELP http_requests_total Requests served.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",code="200"} 1027 1395066363000
+http_requests_total{method="POST", code="500",} 3
+
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.99"} 0.25
+msg{text="a \"quoted\", multi\nline \\ value"} +Inf
+up NaN
+go_goroutines -1.5e1
+`
+	samples, types, err := parsePromText(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []promSample{
+		{"http_requests_total", map[string]string{"method": "GET", "code": "200"}, 1027},
+		{"http_requests_total", map[string]string{"method": "POST", "code": "500"}, 3},
+		{"rpc_duration_seconds", map[string]string{"quantile": "0.99"}, 0.25},
+		{"msg", map[string]string{"text": "a \"quoted\", multi\nline \\ value"}, math.Inf(1)},
+		{"up", map[string]string{}, math.NaN()},
+		{"go_goroutines", map[string]string{}, -15},
+	}
+	if len(samples) != len(want) {
+		t.Fatalf("got %d samples, want %d: %+v", len(samples), len(want), samples)
+	}
+	for i, s := range samples {
+		w := want[i]
+		sameValue := s.Value == w.Value || (math.IsNaN(s.Value) && math.IsNaN(w.Value))
+		if s.Name != w.Name || !reflect.DeepEqual(s.Labels, w.Labels) || !sameValue {
+			t.Errorf("sample %d = %+v, want %+v", i, s, w)
+		}
+	}
+	if types["http_requests_total"] != "counter" || types["rpc_duration_seconds"] != "summary" {
+		t.Errorf("types = %v", types)
+	}
+}
+
+func TestParsePromTextErrors(t *testing.T) {
+	for _, in := range []string{
+		"{code=\"200\"} 1",
+		"requests_total",
+		"requests_total 1 2 3",
+		"requests_total one",
+		"requests_total{code=200} 1",
+		"requests_total{code=\"200} 1",
+	} {
+		if _, _, err := parsePromText(strings.NewReader(in)); err == nil {
+			t.Errorf("parsePromText(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestPromSelector(t *testing.T) {
+	samples := []promSample{
+		{"requests_total", map[string]string{"code": "200", "method": "GET"}, 10},
+		{"requests_total", map[string]string{"code": "500", "method": "GET"}, 2},
+		{"requests_total", map[string]string{"code": "503", "method": "POST"}, 1},
+		{"requests_total", map[string]string{"code": "404", "method": "GET"}, 4},
+		{"other_total", map[string]string{"code": "500"}, 100},
+	}
+	tests := []struct {
+		sel   string
+		want  float64
+		found bool
+	}{
+		{"requests_total", 17, true},
+		{`requests_total{code="500"}`, 2, true},
+		{`requests_total{code=~"5.."}`, 3, true},
+		{`requests_total{code!~"5..", method="GET"}`, 14, true},
+		{`requests_total{method!="GET"}`, 1, true},
+		{`requests_total{code=~"2"}`, 0, false}, // anchored
+		{`missing_total`, 0, false},
+	}
+	for _, tt := range tests {
+		sel, err := parsePromSelector(tt.sel)
+		if err != nil {
+			t.Errorf("parsePromSelector(%q): %v", tt.sel, err)
+			continue
+		}
+		if got, found := sel.sum(samples, sel.Name); got != tt.want || found != tt.found {
+			t.Errorf("%s: sum = %v, %v; want %v, %v", tt.sel, got, found, tt.want, tt.found)
+		}
+	}
+
+	for _, in := range []string{"", `{code="500"}`, `requests_total{code="500"`, `requests_total{code=500}`, `requests_total{code=~"("}`} {
+		if _, err := parsePromSelector(in); err == nil {
+			t.Errorf("parsePromSelector(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	inf := math.Inf(1)
+	buckets := []histogramBucket{{0.1, 50}, {0.5, 90}, {1, 100}, {inf, 100}}
+	tests := []struct {
+		q       float64
+		buckets []histogramBucket
+		want    float64
+	}{
+		{0.5, buckets, 0.1},
+		{0.25, buckets, 0.05},
+		{0.7, buckets, 0.3},
+		{0.95, buckets, 0.75},
+		{0.99, []histogramBucket{{0.1, 50}, {inf, 100}}, 0.1}, // in the +Inf bucket
+		{0.5, []histogramBucket{{0.1, 0}, {inf, 0}}, math.NaN()},
+		{0.5, []histogramBucket{{0.1, 5}, {1, 10}}, math.NaN()}, // no +Inf bucket
+		{0.5, nil, math.NaN()},
+	}
+	for _, tt := range tests {
+		got := histogramQuantile(tt.q, tt.buckets)
+		if math.IsNaN(tt.want) {
+			if !math.IsNaN(got) {
+				t.Errorf("histogramQuantile(%v, %v) = %v, want NaN", tt.q, tt.buckets, got)
+			}
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("histogramQuantile(%v, %v) = %v, want %v", tt.q, tt.buckets, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const hubChannelPrefix = "hub:"
+
+// Hub fans messages out to in-process subscribers of a topic. Messages are
+// relayed through Redis pub/sub so subscribers on every backend instance see
+// them; while Redis is unavailable they are delivered locally only.
+type Hub struct {
+	redis *redis.Client
+
+	mu   sync.RWMutex
+	subs map[string]map[chan []byte]struct{}
+}
+
+func NewHub(redisClient *redis.Client) *Hub {
+	return &Hub{redis: redisClient, subs: map[string]map[chan []byte]struct{}{}}
+}
+
+// Subscribe returns a channel of messages published to topic and a function
+// that must be called to unsubscribe. Slow subscribers miss messages rather
+// than block publishers.
+func (h *Hub) Subscribe(topic string) (<-chan []byte, func()) {
+	ch := make(chan []byte, 16)
+
+	h.mu.Lock()
+	if h.subs[topic] == nil {
+		h.subs[topic] = map[chan []byte]struct{}{}
+	}
+	h.subs[topic][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs[topic], ch)
+		if len(h.subs[topic]) == 0 {
+			delete(h.subs, topic)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Publish sends payload to every subscriber of topic.
+func (h *Hub) Publish(ctx context.Context, topic string, payload []byte) {
+	if err := h.redis.Publish(ctx, hubChannelPrefix+topic, payload).Err(); err != nil {
+		Logger(ctx).Debug("hub publish via Redis failed, delivering locally", "topic", topic, "error", err)
+		h.deliver(topic, payload)
+	}
+}
+
+func (h *Hub) deliver(topic string, payload []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subs[topic] {
+		select {
+		case ch <- payload:
+		default:
+		}
+	}
+}
+
+// Run relays messages from Redis to local subscribers until ctx is done.
+func (h *Hub) Run(ctx context.Context) {
+	sub := h.redis.PSubscribe(ctx, hubChannelPrefix+"*")
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+			h.deliver(strings.TrimPrefix(msg.Channel, hubChannelPrefix), []byte(msg.Payload))
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// TrustedProxyHops is how many proxies in front of the backend append to
+// X-Forwarded-For (Envoy, by default). The client IP used for rate limits,
+// audit events and logs is the entry that many from the end, which a client
+// cannot forge.
+var TrustedProxyHops = 1
+
+var (
+	rateLimited = NewCounter("team15_rate_limited_total",
+		"Requests rejected by a rate limit or login lockout.", nil)
+	rateLimitErrors = NewCounter("team15_rate_limit_errors_total",
+		"Rate limit checks that failed open because Redis was unavailable.", nil)
+)
+
+// RateLimit allows Requests per Window. The zero value is unlimited.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseRateLimit parses limits written as "<requests>/<window>", e.g.
+// "10/1m"; "0" means unlimited.
+func ParseRateLimit(s string) (RateLimit, error) {
+	if s == "" || s == "0" {
+		return RateLimit{}, nil
+	}
+	n, w, ok := strings.Cut(s, "/")
+	requests, err := strconv.Atoi(n)
+	if !ok || err != nil || requests <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q", s)
+	}
+	window, err := time.ParseDuration(w)
+	if err != nil || window < time.Second {
+		return RateLimit{}, fmt.Errorf("invalid rate limit window in %q", s)
+	}
+	return RateLimit{Requests: requests, Window: window}, nil
+}
+
+// RateRule limits one gRPC method, gRPC service ("/pkg.v1.Service/") or
+// HTTP route ("POST /login") per client IP and per authenticated principal.
+type RateRule struct {
+	PerIP        RateLimit
+	PerPrincipal RateLimit
+}
+
+// DefaultRateRules are the limits in force unless RATE_LIMITS overrides
+// them. "*" applies to everything without a more specific rule.
+var DefaultRateRules = map[string]RateRule{
+	"*": {
+		PerIP:        RateLimit{Requests: 1200, Window: time.Minute},
+		PerPrincipal: RateLimit{Requests: 600, Window: time.Minute},
+	},
+	"POST /login": {
+		PerIP: RateLimit{Requests: 20, Window: time.Minute},
+	},
+	"POST /login/mfa": {
+		PerIP: RateLimit{Requests: 20, Window: time.Minute},
+	},
+	"GET /auth/oidc/callback": {
+		PerIP: RateLimit{Requests: 20, Window: time.Minute},
+	},
+	"POST /auth/password/forgot": {
+		PerIP: RateLimit{Requests: 5, Window: time.Minute},
+	},
+	"POST /auth/password/reset": {
+		PerIP: RateLimit{Requests: 20, Window: time.Minute},
+	},
+	"POST /auth/email/verify": {
+		PerIP: RateLimit{Requests: 20, Window: time.Minute},
+	},
+	"POST /oauth/token": {
+		PerIP: RateLimit{Requests: 60, Window: time.Minute},
+	},
+	"/health.v1.HealthService/WatchHealth": {
+		PerIP:        RateLimit{Requests: 120, Window: time.Minute},
+		PerPrincipal: RateLimit{Requests: 60, Window: time.Minute},
+	},
+	"/catalog.v1.CatalogService/CreateServiceToken": {
+		PerPrincipal: RateLimit{Requests: 10, Window: time.Minute},
+	},
+	"/audit.v1.AuditService/": {
+		PerPrincipal: RateLimit{Requests: 60, Window: time.Minute},
+	},
+}
+
+// ParseRateRules applies a JSON object of overrides to a copy of base, e.g.
+// {"POST /login": {"per_ip": "5/1m"}, "/slo.v1.SLOService/": {"per_principal": "0"}}.
+// Limits left out of an override keep their value in base.
+func ParseRateRules(base map[string]RateRule, text string) (map[string]RateRule, error) {
+	rules := make(map[string]RateRule, len(base))
+	for k, v := range base {
+		rules[k] = v
+	}
+	var overrides map[string]struct {
+		PerIP        *string `json:"per_ip"`
+		PerPrincipal *string `json:"per_principal"`
+	}
+	if err := json.Unmarshal([]byte(text), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid rate limits: %w", err)
+	}
+	for name, o := range overrides {
+		rule := rules[name]
+		var err error
+		if o.PerIP != nil {
+			if rule.PerIP, err = ParseRateLimit(*o.PerIP); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		if o.PerPrincipal != nil {
+			if rule.PerPrincipal, err = ParseRateLimit(*o.PerPrincipal); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		rules[name] = rule
+	}
+	return rules, nil
+}
+
+// slidingWindowScript counts a request against a sliding window estimated
+// from the current and previous fixed windows, and returns 0 when it is
+// allowed or the milliseconds until it would be.
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+local c = tonumber(redis.call('GET', KEYS[1]) or '0')
+local p = tonumber(redis.call('GET', KEYS[2]) or '0')
+if p * (window - elapsed) / window + c + 1 > limit then
+  if c + 1 > limit or p == 0 then
+    return window - elapsed
+  end
+  local w = (limit - 1 - c) / p
+  return math.max(1, math.ceil(window * (1 - w) - elapsed))
+end
+redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], window * 2)
+return 0
+`)
+
+// RateLimiter enforces RateRules with sliding windows kept in Redis, so
+// the limits hold across instances. While Redis is unavailable it lets
+// everything through.
+type RateLimiter struct {
+	redis *redis.Client
+	rules map[string]RateRule
+}
+
+func NewRateLimiter(redisClient *redis.Client, rules map[string]RateRule) *RateLimiter {
+	return &RateLimiter{redis: redisClient, rules: rules}
+}
+
+// rule finds the most specific rule for a gRPC method or HTTP route.
+func (l *RateLimiter) rule(name string) (string, RateRule) {
+	if r, ok := l.rules[name]; ok {
+		return name, r
+	}
+	if i := strings.LastIndex(name, "/"); strings.HasPrefix(name, "/") && i > 0 {
+		if r, ok := l.rules[name[:i+1]]; ok {
+			return name[:i+1], r
+		}
+	}
+	return "*", l.rules["*"]
+}
+
+// allow counts one request for subject and returns how long to wait when
+// it is over the limit.
+func (l *RateLimiter) allow(ctx context.Context, key string, limit RateLimit, now time.Time) time.Duration {
+	window := limit.Window.Milliseconds()
+	idx := now.UnixMilli() / window
+	wait, err := slidingWindowScript.Run(ctx, l.redis,
+		[]string{fmt.Sprintf("%s:%d", key, idx), fmt.Sprintf("%s:%d", key, idx-1)},
+		limit.Requests, window, now.UnixMilli()-idx*window,
+	).Int64()
+	if err != nil {
+		rateLimitErrors.Inc()
+		return 0
+	}
+	return time.Duration(wait) * time.Millisecond
+}
+
+// check applies the rule for name to the client IP and principal.
+func (l *RateLimiter) check(ctx context.Context, name, ip, principal string) time.Duration {
+	ruleName, rule := l.rule(name)
+	now := time.Now()
+	if rule.PerIP.Requests > 0 && ip != "" {
+		if wait := l.allow(ctx, "ratelimit:"+ruleName+":ip:"+ip, rule.PerIP, now); wait > 0 {
+			return wait
+		}
+	}
+	if rule.PerPrincipal.Requests > 0 && principal != "" {
+		return l.allow(ctx, "ratelimit:"+ruleName+":principal:"+principal, rule.PerPrincipal, now)
+	}
+	return 0
+}
+
+// retryAfter rounds a wait up to whole seconds, as Retry-After requires.
+func retryAfter(wait time.Duration) string {
+	return strconv.FormatInt(int64((wait+time.Second-1)/time.Second), 10)
+}
+
+func rateLimitError(ctx context.Context, method string, wait time.Duration) error {
+	rateLimited.Inc()
+	Logger(ctx).Warn("rate limited", "method", method, "retry_after", wait)
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ss", retryAfter(wait))
+}
+
+// UnaryInterceptor applies the per-IP limit of the method's rule,
+// rejecting calls over it with ResourceExhausted and a retry-after header.
+// It runs before the audit and JWT interceptors, so unauthenticated calls
+// are limited too and cannot flood the audit log; PrincipalUnaryInterceptor
+// adds the per-principal limit behind the JWT interceptor.
+func (l *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return l.unaryInterceptor(func(ctx context.Context) (string, string) { return grpcClientIP(ctx), "" })
+}
+
+// PrincipalUnaryInterceptor applies the per-principal limit of the
+// method's rule; it must run after the JWT interceptor.
+func (l *RateLimiter) PrincipalUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return l.unaryInterceptor(func(ctx context.Context) (string, string) { return "", PrincipalFromContext(ctx) })
+}
+
+// StreamInterceptor applies the per-IP limit to opening streams.
+func (l *RateLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return l.streamInterceptor(func(ctx context.Context) (string, string) { return grpcClientIP(ctx), "" })
+}
+
+// PrincipalStreamInterceptor applies the per-principal limit to opening
+// streams; it must run after the JWT stream interceptor.
+func (l *RateLimiter) PrincipalStreamInterceptor() grpc.StreamServerInterceptor {
+	return l.streamInterceptor(func(ctx context.Context) (string, string) { return "", PrincipalFromContext(ctx) })
+}
+
+// subjectFunc returns the client IP and principal a call is counted
+// against; an empty one is not limited.
+type subjectFunc func(ctx context.Context) (ip, principal string)
+
+func (l *RateLimiter) unaryInterceptor(subject subjectFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ip, principal := subject(ctx)
+		if wait := l.check(ctx, info.FullMethod, ip, principal); wait > 0 {
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter(wait)))
+			return nil, rateLimitError(ctx, info.FullMethod, wait)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (l *RateLimiter) streamInterceptor(subject subjectFunc) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		ip, principal := subject(ctx)
+		if wait := l.check(ctx, info.FullMethod, ip, principal); wait > 0 {
+			ss.SetHeader(metadata.Pairs("retry-after", retryAfter(wait)))
+			return rateLimitError(ctx, info.FullMethod, wait)
+		}
+		return handler(srv, ss)
+	}
+}
+
+// Middleware is chi middleware applying the per-IP limit of the rule for
+// "<METHOD> <route pattern>". It runs before routing and authentication,
+// so it also covers logins; PrincipalMiddleware adds the per-principal
+// limit behind RequireJWT. Gateway requests are left to the gRPC
+// interceptors, which limit them by method.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGatewayPath(r.URL.Path) || l.allowHTTP(w, r, HTTPClientIP(r), "") {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// PrincipalMiddleware applies the per-principal limit of the rule for the
+// route; it must run behind RequireJWT.
+func (l *RateLimiter) PrincipalMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.allowHTTP(w, r, "", PrincipalFromContext(r.Context())) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// allowHTTP checks r against its route's rule and answers 429 when it is
+// over; it reports whether the request may go on.
+func (l *RateLimiter) allowHTTP(w http.ResponseWriter, r *http.Request, ip, principal string) bool {
+	name := routeName(r)
+	wait := l.check(r.Context(), name, ip, principal)
+	if wait <= 0 {
+		return true
+	}
+	rateLimited.Inc()
+	Logger(r.Context()).Warn("rate limited", "route", name, "retry_after", wait)
+	w.Header().Set("Retry-After", retryAfter(wait))
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+	return false
+}
+
+// routeName is "<METHOD> <pattern>" for the chi route r matches, so that
+// path parameters do not make up new rule names. The pattern is looked up
+// from the root router because RoutePattern is only complete once routing
+// is done. Requests that match no route get "" and fall under "*".
+func routeName(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+	pattern := rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+	if pattern == "" {
+		return ""
+	}
+	return r.Method + " " + pattern
+}
+
+// clientIP picks the address TrustedProxyHops entries from the end of
+// X-Forwarded-For, or the peer address without it.
+func clientIP(forwardedFor, peerAddr string) string {
+	if forwardedFor != "" && TrustedProxyHops > 0 {
+		hops := strings.Split(forwardedFor, ",")
+		return strings.TrimSpace(hops[max(0, len(hops)-TrustedProxyHops)])
+	}
+	if host, _, err := net.SplitHostPort(peerAddr); err == nil {
+		return host
+	}
+	return peerAddr
+}
+
+// HTTPClientIP is the client address used for rate limits, lockouts, audit
+// events and logs.
+func HTTPClientIP(r *http.Request) string {
+	return clientIP(r.Header.Get("X-Forwarded-For"), r.RemoteAddr)
+}
+
+func grpcClientIP(ctx context.Context) string {
+	var fwd, addr string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		fwd = strings.Join(md.Get("x-forwarded-for"), ",")
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	return clientIP(fwd, addr)
+}
@@ -0,0 +1,229 @@
+package internal
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientIP(t *testing.T) {
+	defer func(hops int) { TrustedProxyHops = hops }(TrustedProxyHops)
+	tests := []struct {
+		hops      int
+		forwarded string
+		peer      string
+		want      string
+	}{
+		{1, "", "10.0.0.9:5123", "10.0.0.9"},
+		{1, "203.0.113.7", "10.0.0.9:5123", "203.0.113.7"},
+		// a client cannot choose its address by sending its own header
+		{1, "1.2.3.4, 203.0.113.7", "10.0.0.9:5123", "203.0.113.7"},
+		{2, "1.2.3.4, 203.0.113.7, 10.0.0.2", "10.0.0.9:5123", "203.0.113.7"},
+		{3, "203.0.113.7", "10.0.0.9:5123", "203.0.113.7"},
+		{0, "1.2.3.4", "10.0.0.9:5123", "10.0.0.9"},
+	}
+	for _, tt := range tests {
+		TrustedProxyHops = tt.hops
+		if got := clientIP(tt.forwarded, tt.peer); got != tt.want {
+			t.Errorf("hops %d: clientIP(%q, %q) = %q, want %q", tt.hops, tt.forwarded, tt.peer, got, tt.want)
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.peer
+		if tt.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if got := HTTPClientIP(r); got != tt.want {
+			t.Errorf("hops %d: HTTPClientIP with %q from %q = %q, want %q", tt.hops, tt.forwarded, tt.peer, got, tt.want)
+		}
+
+		ctx := context.Background()
+		if addr, err := net.ResolveTCPAddr("tcp", tt.peer); err == nil {
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+		}
+		if tt.forwarded != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", tt.forwarded))
+		}
+		if got := grpcClientIP(ctx); got != tt.want {
+			t.Errorf("hops %d: grpcClientIP with %q from %q = %q, want %q", tt.hops, tt.forwarded, tt.peer, got, tt.want)
+		}
+	}
+}
+
+func TestParseRateRules(t *testing.T) {
+	rules, err := ParseRateRules(DefaultRateRules, `{"POST /login": {"per_ip": "5/1m"}, "*": {"per_principal": "0"}, "GET /x": {"per_principal": "3/10s"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]RateRule{
+		"POST /login": {PerIP: RateLimit{5, time.Minute}},
+		"*":           {PerIP: DefaultRateRules["*"].PerIP},
+		"GET /x":      {PerPrincipal: RateLimit{3, 10 * time.Second}},
+	}
+	for name, w := range want {
+		if rules[name] != w {
+			t.Errorf("rules[%q] = %+v, want %+v", name, rules[name], w)
+		}
+	}
+	if DefaultRateRules["POST /login"].PerIP.Requests != 20 {
+		t.Error("ParseRateRules changed its base")
+	}
+	for _, in := range []string{`[]`, `{"*": {"per_ip": "5"}}`, `{"*": {"per_ip": "0/1m"}}`, `{"*": {"per_principal": "5/1ms"}}`} {
+		if _, err := ParseRateRules(DefaultRateRules, in); err == nil {
+			t.Errorf("ParseRateRules(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	client, _ := newTestRedis(t)
+	limiter := NewRateLimiter(client, map[string]RateRule{
+		"*":                  {PerIP: RateLimit{100, time.Minute}, PerPrincipal: RateLimit{2, time.Minute}},
+		"GET /items/{id}":    {PerIP: RateLimit{2, time.Minute}},
+		"POST /login":        {PerIP: RateLimit{1, time.Minute}},
+		"GET /admin/reports": {PerPrincipal: RateLimit{1, time.Minute}},
+	})
+	// fakeAuth stands in for RequireJWT
+	fakeAuth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := &AccessClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: r.Header.Get("X-User")}}
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+		})
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	router := chi.NewRouter()
+	router.Use(limiter.Middleware)
+	router.Get("/items/{id}", ok)
+	router.Post("/login", ok)
+	router.Route("/admin", func(r chi.Router) {
+		r.Use(fakeAuth)
+		r.Use(limiter.PrincipalMiddleware)
+		r.Get("/reports", ok)
+		r.Get("/users", ok)
+	})
+
+	// Each step is one request, in order, against the same limiter.
+	steps := []struct {
+		method, path, ip, user string
+		want                   int
+	}{
+		// path parameters share the route's limit
+		{"GET", "/items/1", "1.1.1.1", "", 200},
+		{"GET", "/items/2", "1.1.1.1", "", 200},
+		{"GET", "/items/3", "1.1.1.1", "", 429},
+		{"GET", "/items/3", "2.2.2.2", "", 200},
+		{"POST", "/login", "1.1.1.1", "", 200},
+		{"POST", "/login", "1.1.1.1", "", 429},
+		// the principal is known behind authentication
+		{"GET", "/admin/reports", "1.1.1.1", "alice", 200},
+		{"GET", "/admin/reports", "2.2.2.2", "alice", 429},
+		{"GET", "/admin/reports", "1.1.1.1", "bob", 200},
+		{"GET", "/admin/users", "3.3.3.3", "alice", 200},
+		{"GET", "/admin/users", "4.4.4.4", "alice", 200},
+		{"GET", "/admin/users", "5.5.5.5", "alice", 429},
+		// unknown paths fall under "*"
+		{"GET", "/nowhere/1", "1.1.1.1", "", 404},
+	}
+	for i, s := range steps {
+		r := httptest.NewRequest(s.method, s.path, nil)
+		r.RemoteAddr = s.ip + ":1234"
+		r.Header.Set("X-User", s.user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != s.want {
+			t.Errorf("step %d: %s %s from %s as %q = %d, want %d", i, s.method, s.path, s.ip, s.user, w.Code, s.want)
+		}
+		if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Errorf("step %d: 429 without Retry-After", i)
+		}
+	}
+}
+
+func TestRouteName(t *testing.T) {
+	router := chi.NewRouter()
+	var got string
+	record := func(w http.ResponseWriter, r *http.Request) { got = routeName(r) }
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = routeName(r)
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.Get("/items/{id}", record)
+	router.Route("/auth/sessions", func(r chi.Router) {
+		r.Delete("/{id}", record)
+	})
+	tests := []struct{ method, path, want string }{
+		{"GET", "/items/42", "GET /items/{id}"},
+		{"DELETE", "/auth/sessions/abc", "DELETE /auth/sessions/{id}"},
+		{"POST", "/items/42", ""},
+		{"GET", "/missing", ""},
+	}
+	for _, tt := range tests {
+		got = "unset"
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		if got != tt.want {
+			t.Errorf("routeName(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestRateLimitInterceptors chains the interceptors as the server does and
+// checks that callers without a token are limited per IP.
+func TestRateLimitInterceptors(t *testing.T) {
+	client, _ := newTestRedis(t)
+	limiter := NewRateLimiter(client, map[string]RateRule{
+		"*": {PerIP: RateLimit{2, time.Minute}, PerPrincipal: RateLimit{1, time.Minute}},
+	})
+	sessions := NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client))
+	chain := []grpc.UnaryServerInterceptor{limiter.UnaryInterceptor(), JWTInterceptor(testKey, sessions), limiter.PrincipalUnaryInterceptor()}
+	call := func(ip, bearer string) codes.Code {
+		md := metadata.Pairs("x-forwarded-for", ip)
+		if bearer != "" {
+			md.Set("authorization", "Bearer "+bearer)
+		}
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		info := &grpc.UnaryServerInfo{FullMethod: "/catalog.v1.CatalogService/ListServices"}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+		for i := len(chain) - 1; i >= 0; i-- {
+			next, interceptor := handler, chain[i]
+			handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		_, err := handler(ctx, nil)
+		return status.Code(err)
+	}
+	token := testAccessToken(t)
+
+	steps := []struct {
+		ip, bearer string
+		want       codes.Code
+	}{
+		{"1.1.1.1", "", codes.Unauthenticated},
+		{"1.1.1.1", "", codes.Unauthenticated},
+		// over the per-IP limit before the token is even read
+		{"1.1.1.1", "", codes.ResourceExhausted},
+		{"1.1.1.1", token, codes.ResourceExhausted},
+		{"2.2.2.2", token, codes.OK},
+		// the principal's limit holds across addresses
+		{"3.3.3.3", token, codes.ResourceExhausted},
+		{"3.3.3.3", "", codes.Unauthenticated},
+	}
+	for i, s := range steps {
+		if got := call(s.ip, s.bearer); got != s.want {
+			t.Errorf("step %d: call from %s = %v, want %v", i, s.ip, got, s.want)
+		}
+	}
+}
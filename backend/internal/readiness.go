@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Readiness tracks whether Postgres and Redis are reachable so the backend
+// can report degraded mode instead of failing outright.
+type Readiness struct {
+	db      *gorm.DB
+	redis   *redis.Client
+	catalog *CatalogCache
+
+	postgresUp atomic.Bool
+	redisUp    atomic.Bool
+}
+
+func NewReadiness(db *gorm.DB, redisClient *redis.Client, catalog *CatalogCache) *Readiness {
+	r := &Readiness{db: db, redis: redisClient, catalog: catalog}
+	r.postgresUp.Store(true)
+	r.redisUp.Store(true)
+	for name, up := range map[string]*atomic.Bool{"postgres": &r.postgresUp, "redis": &r.redisUp} {
+		RegisterGaugeFunc("team15_dependency_up", "Whether a backing dependency is reachable (1) or not (0).",
+			map[string]string{"dependency": name}, func() float64 {
+				if up.Load() {
+					return 1
+				}
+				return 0
+			})
+	}
+	RegisterGaugeFunc("team15_degraded", "Whether the backend is running in degraded mode.", nil, func() float64 {
+		if r.Degraded() {
+			return 1
+		}
+		return 0
+	})
+	return r
+}
+
+// Monitor pings both dependencies every interval until ctx is done. Redis
+// pings go through the breaker hook, so they also serve as half-open probes.
+func (r *Readiness) Monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Readiness) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	sqlDB, err := r.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	r.update(ctx, "postgres", &r.postgresUp, err)
+	r.update(ctx, "redis", &r.redisUp, r.redis.Ping(ctx).Err())
+}
+
+func (r *Readiness) update(ctx context.Context, name string, up *atomic.Bool, err error) {
+	if was := up.Swap(err == nil); was != (err == nil) {
+		if err != nil {
+			Logger(ctx).Warn("dependency unavailable, entering degraded mode", "dependency", name, "error", err)
+		} else {
+			Logger(ctx).Info("dependency recovered", "dependency", name)
+		}
+	}
+}
+
+// Degraded reports whether any dependency is currently down.
+func (r *Readiness) Degraded() bool {
+	return !r.postgresUp.Load() || !r.redisUp.Load()
+}
+
+// Handler serves /readyz. The backend stays ready while it can still answer
+// catalog reads: Redis being down only costs caching, and Postgres being down
+// is survivable as long as a last-known catalog is held in memory.
+func (r *Readiness) Handler(w http.ResponseWriter, req *http.Request) {
+	postgresUp, redisUp := r.postgresUp.Load(), r.redisUp.Load()
+
+	body := map[string]string{
+		"status":   "ok",
+		"postgres": upDown(postgresUp),
+		"redis":    upDown(redisUp),
+	}
+	code := http.StatusOK
+	switch {
+	case !postgresUp && !r.catalog.HasLastKnown():
+		body["status"] = "unavailable"
+		code = http.StatusServiceUnavailable
+	case r.Degraded():
+		body["status"] = "degraded"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+func upDown(up bool) string {
+	if up {
+		return "up"
+	}
+	return "down"
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadiness(t *testing.T) {
+	db := newTestDB(t, &ServiceModel{})
+	client, mr := newTestRedis(t)
+	catalog := NewCatalogCache(db, client)
+	r := NewReadiness(db, client, catalog)
+	ctx := context.Background()
+
+	steps := []struct {
+		name   string
+		before func()
+		code   int
+		status string
+	}{
+		{name: "both up", code: http.StatusOK, status: "ok"},
+		{name: "redis down", before: func() { mr.Close() }, code: http.StatusOK, status: "degraded"},
+		{name: "postgres down without a catalog", before: func() {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		}, code: http.StatusServiceUnavailable, status: "unavailable"},
+		{name: "postgres down with a last-known catalog", before: func() {
+			catalog.lastKnown.Store(&[]ServiceModel{{ID: "api"}})
+		}, code: http.StatusOK, status: "degraded"},
+	}
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		r.check(ctx)
+		rec := httptest.NewRecorder()
+		r.Handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var body map[string]string
+		json.NewDecoder(rec.Body).Decode(&body)
+		if rec.Code != step.code || body["status"] != step.status {
+			t.Errorf("%s: %d %v, want %d with status %s", step.name, rec.Code, body, step.code, step.status)
+		}
+		if r.Degraded() != (step.status != "ok") {
+			t.Errorf("%s: Degraded() = %v", step.name, r.Degraded())
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	healthMetricsTable = "health_metric_models"
+
+	// rollupLateness is how long after a minute ends before it is rolled up,
+	// leaving room for samples that arrive slightly late.
+	rollupLateness = 2 * time.Minute
+	purgeBatchSize = 5000
+	// purgePause is slept between purge batches so deletes never hold locks
+	// or saturate I/O for long.
+	purgePause = 100 * time.Millisecond
+)
+
+// rollupLevel is one rollup table. Level 0 is built from raw samples and
+// every other level from the level before it.
+type rollupLevel struct {
+	table string
+	step  time.Duration
+	// span bounds how much source data one rollup statement reads.
+	span time.Duration
+}
+
+var rollupLevels = []rollupLevel{
+	{table: "health_rollups_1m", step: time.Minute, span: 6 * time.Hour},
+	{table: "health_rollups_1h", step: time.Hour, span: 7 * 24 * time.Hour},
+	{table: "health_rollups_1d", step: 24 * time.Hour, span: 180 * 24 * time.Hour},
+}
+
+// RetentionPolicy says how long raw samples and each rollup resolution are
+// kept. Zero keeps data forever.
+type RetentionPolicy struct {
+	Raw    time.Duration
+	Minute time.Duration
+	Hour   time.Duration
+	Day    time.Duration
+}
+
+// DefaultRetentionPolicy keeps raw samples long enough for the default
+// 30-day SLO window.
+var DefaultRetentionPolicy = RetentionPolicy{
+	Raw:    35 * 24 * time.Hour,
+	Minute: 90 * 24 * time.Hour,
+	Hour:   400 * 24 * time.Hour,
+}
+
+// forLevel returns the retention of rollup level i, or of raw samples for -1.
+func (p RetentionPolicy) forLevel(i int) time.Duration {
+	switch i {
+	case -1:
+		return p.Raw
+	case 0:
+		return p.Minute
+	case 1:
+		return p.Hour
+	default:
+		return p.Day
+	}
+}
+
+// Validate rejects policies that would purge data the rest of the backend
+// still reads from raw samples (alert rules and incident auto-open look at
+// the last hour at most).
+func (p RetentionPolicy) Validate() error {
+	if p.Raw != 0 && p.Raw < time.Hour {
+		return fmt.Errorf("raw retention must be at least 1h, got %s", p.Raw)
+	}
+	for i, d := range []time.Duration{p.Minute, p.Hour, p.Day} {
+		if d != 0 && d < rollupLevels[i].span {
+			return fmt.Errorf("%s retention must be at least %s, got %s", rollupLevels[i].table, rollupLevels[i].span, d)
+		}
+	}
+	return nil
+}
+
+// ParseRetention parses a Go duration, a number of days such as "30d", or
+// "0" / "forever" to keep data forever.
+func ParseRetention(s string) (time.Duration, error) {
+	switch s {
+	case "0", "forever":
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid retention %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid retention %q", s)
+	}
+	return d, nil
+}
+
+// Retainer rolls raw health samples up into the rollup tables and purges
+// data past its retention. Rollups are idempotent (each bucket is
+// recomputed in full from its source), so several instances may run it.
+type Retainer struct {
+	db     *gorm.DB
+	policy RetentionPolicy
+}
+
+func NewRetainer(db *gorm.DB, policy RetentionPolicy) *Retainer {
+	return &Retainer{db: db, policy: policy}
+}
+
+// Run rolls up and purges every interval until ctx is done.
+func (r *Retainer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.RunOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			Logger(ctx).Warn("retention run failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce creates upcoming raw partitions, brings every rollup level up to
+// date, then purges.
+func (r *Retainer) RunOnce(ctx context.Context, now time.Time) error {
+	if err := ensureHealthPartitions(ctx, r.db, now); err != nil {
+		return fmt.Errorf("create partitions: %w", err)
+	}
+	for i := range rollupLevels {
+		if err := r.rollup(ctx, i, now); err != nil {
+			return fmt.Errorf("rollup %s: %w", rollupLevels[i].table, err)
+		}
+	}
+	return r.purge(ctx, now)
+}
+
+// rollupWatermark returns the watermark of a rollup table; ok is false
+// before its first rollup.
+func rollupWatermark(ctx context.Context, db *gorm.DB, table string) (wm int64, ok bool, err error) {
+	var m RollupWatermarkModel
+	err = db.WithContext(ctx).Where("rollup_table = ?", table).Limit(1).Find(&m).Error
+	return m.Watermark, err == nil && m.Table != "", err
+}
+
+const rollupFromRawSQL = `INSERT INTO %[1]s (service_id, bucket, samples, up_samples, maintenance_samples,
+    maintenance_up_samples, latency_sum_ms, latency_max_ms, error_rate_sum, error_rate_max)
+SELECT service_id, timestamp / @step * @step, COUNT(*),
+    SUM(CASE WHEN status = @up THEN 1 ELSE 0 END),
+    SUM(CASE WHEN in_maintenance THEN 1 ELSE 0 END),
+    SUM(CASE WHEN in_maintenance AND status = @up THEN 1 ELSE 0 END),
+    SUM(latency_ms), MAX(latency_ms), SUM(error_rate), MAX(error_rate)
+FROM %[2]s WHERE timestamp >= @from AND timestamp < @to
+GROUP BY 1, 2
+ON CONFLICT (service_id, bucket) DO UPDATE SET ` + rollupUpdateSQL
+
+const rollupFromRollupSQL = `INSERT INTO %[1]s (service_id, bucket, samples, up_samples, maintenance_samples,
+    maintenance_up_samples, latency_sum_ms, latency_max_ms, error_rate_sum, error_rate_max)
+SELECT service_id, bucket / @step * @step, SUM(samples), SUM(up_samples),
+    SUM(maintenance_samples), SUM(maintenance_up_samples),
+    SUM(latency_sum_ms), MAX(latency_max_ms), SUM(error_rate_sum), MAX(error_rate_max)
+FROM %[2]s WHERE bucket >= @from AND bucket < @to
+GROUP BY 1, 2
+ON CONFLICT (service_id, bucket) DO UPDATE SET ` + rollupUpdateSQL
+
+const rollupUpdateSQL = `samples = EXCLUDED.samples, up_samples = EXCLUDED.up_samples,
+    maintenance_samples = EXCLUDED.maintenance_samples, maintenance_up_samples = EXCLUDED.maintenance_up_samples,
+    latency_sum_ms = EXCLUDED.latency_sum_ms, latency_max_ms = EXCLUDED.latency_max_ms,
+    error_rate_sum = EXCLUDED.error_rate_sum, error_rate_max = EXCLUDED.error_rate_max`
+
+// rollup aggregates every complete bucket of level i not rolled up yet, a
+// span at a time. Each span and its new watermark commit together.
+func (r *Retainer) rollup(ctx context.Context, i int, now time.Time) error {
+	lvl := rollupLevels[i]
+	step := lvl.step.Milliseconds()
+
+	source, column, query := healthMetricsTable, "timestamp", rollupFromRawSQL
+	limit := now.Add(-rollupLateness).UnixMilli()
+	if i > 0 {
+		source, column, query = rollupLevels[i-1].table, "bucket", rollupFromRollupSQL
+		wm, ok, err := rollupWatermark(ctx, r.db, source)
+		if err != nil || !ok {
+			return err
+		}
+		limit = wm
+	}
+	limit = limit / step * step
+
+	wm, ok, err := rollupWatermark(ctx, r.db, lvl.table)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// Start from the oldest source data
+		var oldest *int64
+		if err := r.db.WithContext(ctx).Table(source).Select("MIN(" + column + ")").Scan(&oldest).Error; err != nil {
+			return err
+		}
+		if oldest == nil {
+			return nil
+		}
+		wm = *oldest / step * step
+	}
+
+	for wm < limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(wm+lvl.span.Milliseconds(), limit)
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Exec(fmt.Sprintf(query, lvl.table, source), map[string]interface{}{
+				"step": step, "from": wm, "to": end, "up": int32(1), // STATUS_UP
+			}).Error
+			if err != nil {
+				return err
+			}
+			// Only advance from where this run started: a late sample may
+			// have rewound the watermark meanwhile
+			return tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.Assignments(map[string]interface{}{"watermark": end}),
+				Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "rollup_watermark_models.watermark = ?", Vars: []interface{}{wm}}}},
+			}).Create(&RollupWatermarkModel{Table: lvl.table, Watermark: end}).Error
+		})
+		if err != nil {
+			return err
+		}
+		wm = end
+	}
+	return nil
+}
+
+// rewindRollups moves every watermark back to the bucket containing ts, so
+// the buckets a late sample belongs to are recomputed on the next run.
+func rewindRollups(ctx context.Context, db *gorm.DB, ts int64) error {
+	for _, lvl := range rollupLevels {
+		start := ts / lvl.step.Milliseconds() * lvl.step.Milliseconds()
+		err := db.WithContext(ctx).Model(&RollupWatermarkModel{}).
+			Where("rollup_table = ? AND watermark > ?", lvl.table, start).
+			Update("watermark", start).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purge drops raw partitions and deletes rollups past their retention.
+// Nothing is removed before the next level has rolled it up.
+func (r *Retainer) purge(ctx context.Context, now time.Time) error {
+	for i := -1; i < len(rollupLevels); i++ {
+		keep := r.policy.forLevel(i)
+		if keep == 0 {
+			continue
+		}
+		cutoff := now.Add(-keep).UnixMilli()
+		if i+1 < len(rollupLevels) {
+			wm, ok, err := rollupWatermark(ctx, r.db, rollupLevels[i+1].table)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			cutoff = min(cutoff, wm)
+		}
+
+		if i < 0 {
+			// Raw samples expire a whole day partition at a time
+			dropped, err := dropHealthPartitionsBefore(ctx, r.db, cutoff)
+			for _, name := range dropped {
+				Logger(ctx).Info("dropped expired health metrics partition", "partition", name)
+			}
+			if err != nil {
+				return fmt.Errorf("drop partitions: %w", err)
+			}
+			continue
+		}
+		table := rollupLevels[i].table
+		n, err := purgeBefore(ctx, r.db, table, "bucket", cutoff)
+		if n > 0 {
+			Logger(ctx).Info("purged expired health data", "table", table, "rows", n)
+		}
+		if err != nil {
+			return fmt.Errorf("purge %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// purgeBefore deletes rows with column < cutoff in small batches, each its
+// own statement, so no long-running delete locks the table.
+func purgeBefore(ctx context.Context, db *gorm.DB, table, column string, cutoff int64) (int64, error) {
+	stmt := fmt.Sprintf("DELETE FROM %[1]s WHERE ctid IN (SELECT ctid FROM %[1]s WHERE %[2]s < ? LIMIT ?)", table, column)
+	var total int64
+	for {
+		res := db.WithContext(ctx).Exec(stmt, cutoff, purgeBatchSize)
+		if res.Error != nil {
+			return total, res.Error
+		}
+		total += res.RowsAffected
+		if res.RowsAffected < purgeBatchSize {
+			return total, nil
+		}
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case <-time.After(purgePause):
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff returns the delay before retry number attempt (starting at 0):
+// exponential from base, capped at max, with up to 20% jitter so that many
+// instances do not retry in lockstep.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+// Retry calls fn until it succeeds or ctx is done, sleeping with Backoff
+// between attempts. what names the operation in log messages.
+func Retry(ctx context.Context, what string, base, max time.Duration, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		delay := Backoff(attempt, base, max)
+		Logger(ctx).Warn("retrying after failure", "operation", what, "attempt", attempt+1, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
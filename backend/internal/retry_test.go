@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	errDown := errors.New("down")
+	tests := []struct {
+		name     string
+		failures int // calls that fail before one succeeds; -1 never succeeds
+		timeout  time.Duration
+		want     error
+	}{
+		{"first call succeeds", 0, time.Second, nil},
+		{"succeeds after failures", 3, time.Second, nil},
+		{"gives up when the context ends", -1, 50 * time.Millisecond, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+		calls := 0
+		err := Retry(ctx, "test", time.Millisecond, 5*time.Millisecond, func() error {
+			calls++
+			if tt.failures < 0 || calls <= tt.failures {
+				return errDown
+			}
+			return nil
+		})
+		cancel()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: Retry = %v, want %v", tt.name, err, tt.want)
+		}
+		if tt.failures >= 0 && calls != tt.failures+1 {
+			t.Errorf("%s: %d calls, want %d", tt.name, calls, tt.failures+1)
+		}
+		if tt.failures < 0 && calls < 2 {
+			t.Errorf("%s: gave up after %d calls", tt.name, calls)
+		}
+	}
+}
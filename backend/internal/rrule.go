@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRRuleIterations bounds the work done walking an unbounded rule.
+const maxRRuleIterations = 100000
+
+// RRule is the subset of RFC 5545 recurrence rules used by maintenance
+// windows: FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, COUNT, UNTIL
+// and BYDAY (weekday names only, no ordinals).
+type RRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []time.Weekday
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// ParseRRule parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=10".
+func ParseRRule(s string) (*RRule, error) {
+	r := &RRule{Interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(s, "RRULE:"), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+			if r.Freq != "DAILY" && r.Freq != "WEEKLY" && r.Freq != "MONTHLY" {
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			if r.Interval, err = strconv.Atoi(value); err != nil || r.Interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+		case "COUNT":
+			if r.Count, err = strconv.Atoi(value); err != nil || r.Count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+		case "UNTIL":
+			if r.Until, err = parseRRuleTime(value); err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", value)
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				wd, ok := rruleWeekdays[strings.ToUpper(d)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY %q", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %q", key)
+		}
+	}
+	if r.Freq == "" {
+		return nil, fmt.Errorf("rrule needs FREQ")
+	}
+	if r.Freq == "MONTHLY" && len(r.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY is not supported with FREQ=MONTHLY")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL are mutually exclusive")
+	}
+	return r, nil
+}
+
+func parseRRuleTime(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", v)
+}
+
+// Each calls fn with every occurrence start in order, beginning with dtstart
+// when it matches the rule, until fn returns false or the rule ends.
+func (r *RRule) Each(dtstart time.Time, fn func(time.Time) bool) {
+	emitted := 0
+	emit := func(t time.Time) bool {
+		if t.Before(dtstart) {
+			return true
+		}
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		emitted++
+		if !fn(t) {
+			return false
+		}
+		return r.Count == 0 || emitted < r.Count
+	}
+
+	for i := 0; i < maxRRuleIterations; i++ {
+		switch r.Freq {
+		case "DAILY":
+			t := dtstart.AddDate(0, 0, i*r.Interval)
+			if len(r.ByDay) > 0 && !containsWeekday(r.ByDay, t.Weekday()) {
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		case "WEEKLY":
+			days := r.ByDay
+			if len(days) == 0 {
+				days = []time.Weekday{dtstart.Weekday()}
+			}
+			// Weeks start on Monday (the RFC 5545 default WKST)
+			weekStart := dtstart.AddDate(0, 0, -((int(dtstart.Weekday())+6)%7)+7*i*r.Interval)
+			offsets := make([]int, len(days))
+			for j, d := range days {
+				offsets[j] = (int(d) + 6) % 7
+			}
+			sort.Ints(offsets)
+			for _, off := range offsets {
+				if !emit(weekStart.AddDate(0, 0, off)) {
+					return
+				}
+			}
+		case "MONTHLY":
+			t := dtstart.AddDate(0, i*r.Interval, 0)
+			if t.Day() != dtstart.Day() {
+				// e.g. the 31st in a 30-day month: RFC 5545 skips it
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+func containsWeekday(days []time.Weekday, d time.Weekday) bool {
+	for _, x := range days {
+		if x == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestRRuleEach(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	local := func(s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02T15:04", s, la)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		want    []string // RFC 3339; at most 10 occurrences are taken
+	}{
+		{"daily count", "FREQ=DAILY;COUNT=3", utc("2025-01-30T09:00:00Z"),
+			[]string{"2025-01-30T09:00:00Z", "2025-01-31T09:00:00Z", "2025-02-01T09:00:00Z"}},
+		{"daily interval", "FREQ=DAILY;INTERVAL=2;COUNT=3", utc("2025-01-30T09:00:00Z"),
+			[]string{"2025-01-30T09:00:00Z", "2025-02-01T09:00:00Z", "2025-02-03T09:00:00Z"}},
+		{"daily on weekends", "FREQ=DAILY;BYDAY=SA,SU;COUNT=3", utc("2025-01-30T09:00:00Z"),
+			[]string{"2025-02-01T09:00:00Z", "2025-02-02T09:00:00Z", "2025-02-08T09:00:00Z"}},
+		{"weekly byday skips days before dtstart", "FREQ=WEEKLY;BYDAY=TH,TU;COUNT=4", utc("2025-01-29T09:00:00Z"),
+			[]string{"2025-01-30T09:00:00Z", "2025-02-04T09:00:00Z", "2025-02-06T09:00:00Z", "2025-02-11T09:00:00Z"}},
+		{"weekly interval", "FREQ=WEEKLY;INTERVAL=2;COUNT=3", utc("2025-01-27T09:00:00Z"),
+			[]string{"2025-01-27T09:00:00Z", "2025-02-10T09:00:00Z", "2025-02-24T09:00:00Z"}},
+		{"until is inclusive", "FREQ=WEEKLY;BYDAY=MO;UNTIL=20250217T090000Z", utc("2025-02-03T09:00:00Z"),
+			[]string{"2025-02-03T09:00:00Z", "2025-02-10T09:00:00Z", "2025-02-17T09:00:00Z"}},
+		{"until as a date", "FREQ=MONTHLY;UNTIL=20250401", utc("2025-01-15T00:00:00Z"),
+			[]string{"2025-01-15T00:00:00Z", "2025-02-15T00:00:00Z", "2025-03-15T00:00:00Z"}},
+		{"monthly skips short months", "FREQ=MONTHLY;COUNT=4", utc("2025-01-31T22:00:00Z"),
+			[]string{"2025-01-31T22:00:00Z", "2025-03-31T22:00:00Z", "2025-05-31T22:00:00Z", "2025-07-31T22:00:00Z"}},
+		{"unbounded", "FREQ=MONTHLY;INTERVAL=6", utc("2025-01-01T00:00:00Z"),
+			[]string{"2025-01-01T00:00:00Z", "2025-07-01T00:00:00Z", "2026-01-01T00:00:00Z", "2026-07-01T00:00:00Z",
+				"2027-01-01T00:00:00Z", "2027-07-01T00:00:00Z", "2028-01-01T00:00:00Z", "2028-07-01T00:00:00Z",
+				"2029-01-01T00:00:00Z", "2029-07-01T00:00:00Z"}},
+
+		// Maintenance windows are evaluated in UTC, where days are always 24 hours
+		{"utc across a DST change", "FREQ=DAILY;COUNT=2", utc("2025-03-09T09:30:00Z"),
+			[]string{"2025-03-09T09:30:00Z", "2025-03-10T09:30:00Z"}},
+		// In a zone with DST the wall-clock time is kept
+		{"spring forward", "FREQ=DAILY;COUNT=3", local("2025-03-08T09:00"),
+			[]string{"2025-03-08T09:00:00-08:00", "2025-03-09T09:00:00-07:00", "2025-03-10T09:00:00-07:00"}},
+		{"fall back", "FREQ=WEEKLY;COUNT=2", local("2025-10-29T01:30"),
+			[]string{"2025-10-29T01:30:00-07:00", "2025-11-05T01:30:00-08:00"}},
+	}
+	for _, tt := range tests {
+		r, err := ParseRRule(tt.rule)
+		if err != nil {
+			t.Errorf("%s: ParseRRule(%q): %v", tt.name, tt.rule, err)
+			continue
+		}
+		var got []string
+		r.Each(tt.dtstart, func(t time.Time) bool {
+			got = append(got, t.Format(time.RFC3339))
+			return len(got) < 10
+		})
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	r, err := ParseRRule("RRULE:freq=weekly;interval=2;byday=tu,th;count=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Freq != "WEEKLY" || r.Interval != 2 || r.Count != 10 || len(r.ByDay) != 2 || r.ByDay[0] != time.Tuesday {
+		t.Errorf("parsed %+v", r)
+	}
+
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COUNT",
+	} {
+		if _, err := ParseRRule(rule); err == nil {
+			t.Errorf("ParseRRule(%q) succeeded, want an error", rule)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	catalogpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1"
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+)
+
+const (
+	defaultScrapeInterval = 15 * time.Second
+	defaultScrapeQuantile = 0.99
+	maxScrapeTimeout      = 10 * time.Second
+	maxScrapeBody         = 10 << 20
+)
+
+var scrapeFailures = NewCounter("team15_scrape_failures_total",
+	"Scrapes of service metrics endpoints that failed.", nil)
+
+// scrapeState is what a scrape leaves for the next one to compute rates. It
+// is kept in Redis so that whichever instance scrapes next can use it.
+type scrapeState struct {
+	At      int64     `json:"at"`
+	Errors  float64   `json:"errors"`
+	Total   float64   `json:"total"`
+	Uppers  []string  `json:"uppers"` // as text: JSON has no +Inf
+	Counts  []float64 `json:"counts"`
+	Counted bool      `json:"counted"`
+}
+
+// Scraper derives health samples from the Prometheus /metrics endpoints of
+// catalog services that have a ScrapeConfig, and records them like pushed
+// samples. A Redis lock makes sure only one instance scrapes a service per
+// interval.
+type Scraper struct {
+	health *HealthServerImpl
+	client *http.Client
+
+	mu       sync.Mutex
+	inflight map[string]bool
+}
+
+func NewScraper(health *HealthServerImpl, client *http.Client) *Scraper {
+	return &Scraper{health: health, client: client, inflight: map[string]bool{}}
+}
+
+func scrapeInterval(cfg *ScrapeConfig) time.Duration {
+	if cfg.IntervalSeconds > 0 {
+		return time.Duration(cfg.IntervalSeconds) * time.Second
+	}
+	return defaultScrapeInterval
+}
+
+// validateScrapeConfig checks a configuration before it is saved.
+func validateScrapeConfig(cfg *ScrapeConfig) error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("scrape.url must be an http(s) URL")
+	}
+	if cfg.IntervalSeconds < 0 {
+		return fmt.Errorf("scrape.interval_seconds must not be negative")
+	}
+	if cfg.LatencyQuantile < 0 || cfg.LatencyQuantile >= 1 {
+		return fmt.Errorf("scrape.latency_quantile must be between 0 and 1")
+	}
+	if cfg.LatencyUnit != "" && cfg.LatencyUnit != "s" && cfg.LatencyUnit != "ms" {
+		return fmt.Errorf(`scrape.latency_unit must be "s" or "ms"`)
+	}
+	if (cfg.ErrorsMetric == "") != (cfg.TotalMetric == "") {
+		return fmt.Errorf("scrape.errors_metric and scrape.total_metric go together")
+	}
+	for _, sel := range []string{cfg.LatencyMetric, cfg.ErrorsMetric, cfg.TotalMetric} {
+		if sel == "" {
+			continue
+		}
+		if _, err := parsePromSelector(sel); err != nil {
+			return fmt.Errorf("scrape: %w", err)
+		}
+	}
+	return nil
+}
+
+// Run starts the scrapes that are due every tick until ctx is done.
+func (s *Scraper) Run(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		services, err := s.health.catalog.Services(ctx)
+		if err != nil {
+			Logger(ctx).Warn("scraper could not list services", "error", err)
+		}
+		for _, svc := range services {
+			if svc.Scrape == nil || svc.Scrape.URL == "" || !s.claim(ctx, svc) {
+				continue
+			}
+			go func(svc ServiceModel) {
+				defer s.release(svc.ID)
+				s.scrape(ctx, svc, time.Now())
+			}(svc)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim reports whether this instance should scrape svc now. While Redis is
+// unavailable every instance scrapes, which only duplicates samples.
+func (s *Scraper) claim(ctx context.Context, svc ServiceModel) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inflight[svc.ID] {
+		return false
+	}
+	ok, err := s.health.redis.SetNX(ctx, "scrape:lock:"+svc.ID, 1, scrapeInterval(svc.Scrape)).Result()
+	if err == nil && !ok {
+		return false
+	}
+	s.inflight[svc.ID] = true
+	return true
+}
+
+func (s *Scraper) release(serviceID string) {
+	s.mu.Lock()
+	delete(s.inflight, serviceID)
+	s.mu.Unlock()
+}
+
+// scrape fetches one endpoint and records the derived sample. An endpoint
+// that cannot be scraped is recorded as DOWN.
+func (s *Scraper) scrape(ctx context.Context, svc ServiceModel, now time.Time) {
+	cfg := svc.Scrape
+	ctx, cancel := context.WithTimeout(ctx, min(scrapeInterval(cfg), maxScrapeTimeout))
+	defer cancel()
+
+	samples, types, err := s.fetch(ctx, cfg.URL)
+	metric := HealthMetricModel{
+		ServiceID: svc.ID,
+		Status:    int32(healthpb.Status_STATUS_UP),
+		Timestamp: now.UnixMilli(),
+	}
+	if err != nil {
+		scrapeFailures.Inc()
+		Logger(ctx).Warn("scrape failed", "service_id", svc.ID, "url", cfg.URL, "error", err)
+		metric.Status = int32(healthpb.Status_STATUS_DOWN)
+		metric.LatencyMs = int32(time.Since(now).Milliseconds())
+		metric.ErrorRate = 1
+		metric.Reasons = []string{"scrape failed: " + err.Error()}
+	} else {
+		stateKey := "scrape:state:" + svc.ID
+		var prev *scrapeState
+		if raw, err := s.health.redis.Get(ctx, stateKey).Bytes(); err == nil {
+			prev = &scrapeState{}
+			if json.Unmarshal(raw, prev) != nil {
+				prev = nil
+			}
+		}
+		state := deriveScrapeSample(cfg, samples, types, prev, &metric)
+		state.At = metric.Timestamp
+		raw, _ := json.Marshal(state)
+		s.health.redis.Set(ctx, stateKey, raw, 10*scrapeInterval(cfg))
+	}
+
+	if err := s.health.record(ctx, &metric); err != nil {
+		Logger(ctx).Warn("failed to record scraped sample", "service_id", svc.ID, "error", err)
+	}
+}
+
+func (s *Scraper) fetch(ctx context.Context, target string) ([]promSample, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "text/plain;version=0.0.4")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parsePromText(io.LimitReader(resp.Body, maxScrapeBody))
+}
+
+// deriveScrapeSample fills in the latency and error rate of metric from one
+// scrape, using prev (nil on the first scrape) to turn counters into
+// increases. It returns the state for the next scrape.
+func deriveScrapeSample(cfg *ScrapeConfig, samples []promSample, types map[string]string, prev *scrapeState, metric *HealthMetricModel) *scrapeState {
+	state := &scrapeState{}
+	q := cfg.LatencyQuantile
+	if q == 0 {
+		q = defaultScrapeQuantile
+	}
+
+	if sel, err := parsePromSelector(cfg.LatencyMetric); err == nil {
+		latency := math.NaN()
+		if buckets := sel.buckets(samples); len(buckets) > 0 {
+			for _, b := range buckets {
+				state.Uppers = append(state.Uppers, formatBucketBound(b.upper))
+				state.Counts = append(state.Counts, b.count)
+			}
+			latency = histogramQuantile(q, bucketIncrease(buckets, prev))
+			if math.IsNaN(latency) {
+				// Nothing observed since the previous scrape
+				latency = histogramQuantile(q, buckets)
+			}
+		} else if types[sel.Name] == "summary" {
+			latency = maxMatching(sel, samples, func(s promSample) bool {
+				v, err := strconv.ParseFloat(s.Labels["quantile"], 64)
+				return err == nil && math.Abs(v-q) < 1e-9
+			})
+		} else {
+			latency = maxMatching(sel, samples, func(promSample) bool { return true })
+		}
+		if !math.IsNaN(latency) && !math.IsInf(latency, 0) {
+			if cfg.LatencyUnit != "ms" {
+				latency *= 1000
+			}
+			metric.LatencyMs = int32(math.Round(latency))
+		}
+	}
+
+	errSel, err1 := parsePromSelector(cfg.ErrorsMetric)
+	totalSel, err2 := parsePromSelector(cfg.TotalMetric)
+	if err1 == nil && err2 == nil {
+		errs, _ := errSel.sum(samples, errSel.Name)
+		total, ok := totalSel.sum(samples, totalSel.Name)
+		state.Errors, state.Total, state.Counted = errs, total, ok
+		dErr, dTotal := errs, total
+		if prev != nil && prev.Counted && errs >= prev.Errors && total >= prev.Total {
+			// Otherwise the counters were reset and the whole value is the increase
+			dErr, dTotal = errs-prev.Errors, total-prev.Total
+		}
+		if dTotal > 0 {
+			metric.ErrorRate = float32(math.Min(1, math.Max(0, dErr/dTotal)))
+		}
+	}
+	return state
+}
+
+// bucketIncrease subtracts the buckets seen by the previous scrape, unless
+// the bucket layout changed or the counters were reset.
+func bucketIncrease(buckets []histogramBucket, prev *scrapeState) []histogramBucket {
+	if prev == nil || len(prev.Uppers) != len(buckets) {
+		return buckets
+	}
+	out := make([]histogramBucket, len(buckets))
+	for i, b := range buckets {
+		if prev.Uppers[i] != formatBucketBound(b.upper) || b.count < prev.Counts[i] {
+			return buckets
+		}
+		out[i] = histogramBucket{upper: b.upper, count: b.count - prev.Counts[i]}
+	}
+	return out
+}
+
+func formatBucketBound(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func maxMatching(sel *promSelector, samples []promSample, keep func(promSample) bool) float64 {
+	v := math.NaN()
+	for _, s := range samples {
+		if sel.matches(s, sel.Name) && keep(s) && !math.IsNaN(s.Value) {
+			if math.IsNaN(v) || s.Value > v {
+				v = s.Value
+			}
+		}
+	}
+	return v
+}
+
+func scrapeConfigToProto(c *ScrapeConfig) *catalogpb.ScrapeConfig {
+	if c == nil {
+		return nil
+	}
+	return &catalogpb.ScrapeConfig{
+		Url:             c.URL,
+		IntervalSeconds: c.IntervalSeconds,
+		LatencyMetric:   c.LatencyMetric,
+		LatencyQuantile: c.LatencyQuantile,
+		LatencyUnit:     c.LatencyUnit,
+		ErrorsMetric:    c.ErrorsMetric,
+		TotalMetric:     c.TotalMetric,
+	}
+}
+
+func scrapeConfigFromProto(p *catalogpb.ScrapeConfig) *ScrapeConfig {
+	if p == nil || p.Url == "" {
+		return nil
+	}
+	return &ScrapeConfig{
+		URL:             p.Url,
+		IntervalSeconds: p.IntervalSeconds,
+		LatencyMetric:   p.LatencyMetric,
+		LatencyQuantile: p.LatencyQuantile,
+		LatencyUnit:     p.LatencyUnit,
+		ErrorsMetric:    p.ErrorsMetric,
+		TotalMetric:     p.TotalMetric,
+	}
+}
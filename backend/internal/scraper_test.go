@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// exposition renders a scrape with a latency histogram and request counters.
+func exposition(le100ms, le500ms, count, errors, total float64) string {
+	return fmt.Sprintf(`# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="0.1"} %g
+http_request_duration_seconds_bucket{le="0.5"} %g
+http_request_duration_seconds_bucket{le="+Inf"} %g
+http_request_duration_seconds_sum 1
+http_request_duration_seconds_count %g
+# TYPE http_requests_total counter
+http_requests_total{code="200"} %g
+http_requests_total{code="500"} %g
+`, le100ms, le500ms, count, count, total-errors, errors)
+}
+
+func TestScrapeRates(t *testing.T) {
+	cfg := &ScrapeConfig{
+		LatencyMetric:   "http_request_duration_seconds",
+		LatencyQuantile: 0.5,
+		ErrorsMetric:    `http_requests_total{code=~"5.."}`,
+		TotalMetric:     "http_requests_total",
+	}
+	// Each scrape is derived using the state the previous one left.
+	scrapes := []struct {
+		name      string
+		body      string
+		latencyMs int32
+		errorRate float32
+	}{
+		// first scrape: the counters since start are the increase
+		{"first", exposition(100, 100, 100, 10, 100), 50, 0.1},
+		// 100 more requests, all between 100ms and 500ms, 50 of them errors
+		{"increase", exposition(100, 200, 200, 60, 200), 300, 0.5},
+		// nothing new: latency falls back to the cumulative histogram
+		{"idle", exposition(100, 200, 200, 60, 200), 100, 0},
+		// counters reset by a restart: the new values are the increase
+		{"reset", exposition(10, 10, 10, 1, 10), 50, 0.1},
+	}
+
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+	s := NewScraper(nil, srv.Client())
+
+	var prev *scrapeState
+	for _, sc := range scrapes {
+		body = sc.body
+		samples, types, err := s.fetch(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatalf("%s: %v", sc.name, err)
+		}
+		var metric HealthMetricModel
+		state := deriveScrapeSample(cfg, samples, types, prev, &metric)
+		if metric.LatencyMs != sc.latencyMs || metric.ErrorRate != sc.errorRate {
+			t.Errorf("%s: latency %dms, error rate %v; want %dms, %v", sc.name, metric.LatencyMs, metric.ErrorRate, sc.latencyMs, sc.errorRate)
+		}
+		// The state goes through Redis as JSON between scrapes
+		raw, _ := json.Marshal(state)
+		prev = &scrapeState{}
+		if err := json.Unmarshal(raw, prev); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScrapeLatencyKinds(t *testing.T) {
+	body := `# TYPE rpc_latency summary
+rpc_latency{quantile="0.5"} 0.02
+rpc_latency{quantile="0.99"} 0.4
+# TYPE queue_latency_ms gauge
+queue_latency_ms{queue="a"} 12
+queue_latency_ms{queue="b"} 31
+`
+	samples, types, err := parsePromText(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		cfg  ScrapeConfig
+		want int32
+	}{
+		{ScrapeConfig{LatencyMetric: "rpc_latency"}, 400}, // default quantile 0.99
+		{ScrapeConfig{LatencyMetric: "rpc_latency", LatencyQuantile: 0.5}, 20},
+		{ScrapeConfig{LatencyMetric: "queue_latency_ms", LatencyUnit: "ms"}, 31},
+		{ScrapeConfig{LatencyMetric: `queue_latency_ms{queue="a"}`, LatencyUnit: "ms"}, 12},
+		{ScrapeConfig{LatencyMetric: "missing"}, 0},
+	}
+	for _, tt := range tests {
+		var metric HealthMetricModel
+		deriveScrapeSample(&tt.cfg, samples, types, nil, &metric)
+		if metric.LatencyMs != tt.want {
+			t.Errorf("%+v: latency %dms, want %dms", tt.cfg, metric.LatencyMs, tt.want)
+		}
+	}
+}
+
+func TestScrapeFetchErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"status", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "nope", http.StatusServiceUnavailable) }},
+		{"malformed", func(w http.ResponseWriter, r *http.Request) { fmt.Fprintln(w, "requests_total{code=500} 1") }},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(tt.handler)
+		if _, _, err := NewScraper(nil, srv.Client()).fetch(context.Background(), srv.URL); err == nil {
+			t.Errorf("%s: fetch succeeded, want an error", tt.name)
+		}
+		srv.Close()
+	}
+}
+
+func TestValidateScrapeConfig(t *testing.T) {
+	tests := []struct {
+		cfg ScrapeConfig
+		ok  bool
+	}{
+		{ScrapeConfig{URL: "http://svc:9090/metrics"}, true},
+		{ScrapeConfig{URL: "https://svc/metrics", LatencyMetric: `lat{code=~"2.."}`, LatencyQuantile: 0.9, LatencyUnit: "ms", ErrorsMetric: "e", TotalMetric: "t"}, true},
+		{ScrapeConfig{URL: "ftp://svc/metrics"}, false},
+		{ScrapeConfig{URL: "svc/metrics"}, false},
+		{ScrapeConfig{URL: "http://svc", IntervalSeconds: -1}, false},
+		{ScrapeConfig{URL: "http://svc", LatencyQuantile: 1}, false},
+		{ScrapeConfig{URL: "http://svc", LatencyUnit: "us"}, false},
+		{ScrapeConfig{URL: "http://svc", ErrorsMetric: "e"}, false},
+		{ScrapeConfig{URL: "http://svc", LatencyMetric: "lat{"}, false},
+	}
+	for _, tt := range tests {
+		if err := validateScrapeConfig(&tt.cfg); (err == nil) != tt.ok {
+			t.Errorf("validateScrapeConfig(%+v) = %v, want ok %v", tt.cfg, err, tt.ok)
+		}
+	}
+}
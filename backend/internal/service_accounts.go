@@ -0,0 +1,456 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	// Service account tokens are signed like access tokens, with this
+	// audience and a subject of "sa:" and the client ID, so they can never
+	// be mistaken for a user's.
+	serviceAccountAudience      = "team15-service"
+	serviceAccountSubjectPrefix = "sa:"
+	serviceAccountTokenTTL      = 15 * time.Minute
+
+	clientIDPrefix     = "sa_"
+	clientSecretPrefix = "t15c_"
+)
+
+// scopePackages are the gRPC packages a scope can name, as
+// "<package>:<access>" with an access from scopeAccess.
+var scopePackages = []string{"catalog", "health", "alerting", "notification", "slo", "incident", "maintenance", "audit"}
+
+// scopeAccess are the levels of access a scope grants, each implying the
+// ones before it: admin covers the methods only admin users may call.
+var scopeAccess = []string{"read", "write", "admin"}
+
+// parseScopes validates a list of scopes, as in the space-separated scope
+// parameter of /oauth/token, and returns them sorted without duplicates.
+func parseScopes(scopes []string) ([]string, error) {
+	var parsed []string
+	for _, scope := range scopes {
+		pkg, access, _ := strings.Cut(scope, ":")
+		if !slices.Contains(scopePackages, pkg) || !slices.Contains(scopeAccess, access) {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		parsed = append(parsed, scope)
+	}
+	slices.Sort(parsed)
+	return slices.Compact(parsed), nil
+}
+
+// scopeGranted reports whether scope, or a scope of the same package with
+// more access, is among granted.
+func scopeGranted(granted []string, scope string) bool {
+	pkg, access, _ := strings.Cut(scope, ":")
+	i := slices.Index(scopeAccess, access)
+	if i < 0 {
+		return false
+	}
+	for _, a := range scopeAccess[i:] {
+		if slices.Contains(granted, pkg+":"+a) {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredScope is the scope a gRPC method needs: "<package>:admin" for
+// the methods methodRoles keeps to admins, "<package>:write" for the other
+// mutating ones and "<package>:read" for reads, e.g. "catalog:write" for
+// /catalog.v1.CatalogService/CreateService. Methods outside scopePackages
+// need a scope no account can have, "".
+func requiredScope(fullMethod string) string {
+	pkg, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), ".")
+	switch {
+	case !slices.Contains(scopePackages, pkg):
+		return ""
+	case requiredRole(fullMethod) == roleAdmin:
+		return pkg + ":admin"
+	case mutatingMethod(fullMethod):
+		return pkg + ":write"
+	}
+	return pkg + ":read"
+}
+
+func isServiceAccountToken(claims *AccessClaims) bool {
+	return len(claims.Audience) == 1 && claims.Audience[0] == serviceAccountAudience &&
+		strings.HasPrefix(claims.Subject, serviceAccountSubjectPrefix)
+}
+
+// checkScope lets service account tokens call only the methods their
+// scopes cover.
+func checkScope(claims *AccessClaims, fullMethod string) error {
+	scope := requiredScope(fullMethod)
+	if scope == "" {
+		return status.Errorf(codes.PermissionDenied, "%s is not available to service accounts", fullMethod)
+	}
+	if !scopeGranted(strings.Fields(claims.Scope), scope) {
+		return status.Errorf(codes.PermissionDenied, "token lacks scope %s", scope)
+	}
+	return nil
+}
+
+// ServiceAccounts are the identities of automated clients such as
+// deployment pipelines. They trade their client ID and secret for a
+// short-lived access token at /oauth/token (the OAuth2 client-credentials
+// grant), scoped to what the account was granted. Tokens are sessions
+// like logins, so disabling an account ends its tokens at once.
+type ServiceAccounts struct {
+	db       *gorm.DB
+	key      []byte
+	sessions *Sessions
+}
+
+func NewServiceAccounts(db *gorm.DB, secretKey []byte, sessions *Sessions) *ServiceAccounts {
+	return &ServiceAccounts{db: db, key: secretKey, sessions: sessions}
+}
+
+// errInvalidClient is returned for an unknown client, a wrong secret or a
+// disabled account alike.
+var errInvalidClient = errors.New("invalid client credentials")
+
+// authenticate checks a client ID and secret.
+func (s *ServiceAccounts) authenticate(ctx context.Context, clientID, secret string) (*ServiceAccountModel, error) {
+	if clientID == "" || secret == "" {
+		return nil, errInvalidClient
+	}
+	var sa ServiceAccountModel
+	err := s.db.WithContext(ctx).Where("client_id = ? AND disabled = ?", clientID, false).Take(&sa).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errInvalidClient
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashServiceToken(secret)), []byte(sa.SecretHash)) != 1 {
+		return nil, errInvalidClient
+	}
+	return &sa, nil
+}
+
+// clientCredentials reads the client's credentials from HTTP Basic auth or
+// else the client_id and client_secret form fields (RFC 6749 section 2.3.1).
+func clientCredentials(r *http.Request) (string, string) {
+	if id, secret, ok := r.BasicAuth(); ok {
+		// Basic credentials are form-encoded first.
+		if unescaped, err := url.QueryUnescape(id); err == nil {
+			id = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(secret); err == nil {
+			secret = unescaped
+		}
+		return id, secret
+	}
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+}
+
+// oauthError writes an RFC 6749 error response.
+func oauthError(w http.ResponseWriter, code int, errorCode, description string) {
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="team15"`)
+	}
+	writeJSON(w, code, map[string]string{"error": errorCode, "error_description": description})
+}
+
+// Token is the OAuth2 token endpoint. Only the client-credentials grant is
+// supported; without a scope parameter the token gets every granted scope.
+func (s *ServiceAccounts) Token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, "invalid_request", "malformed form body")
+		return
+	}
+	switch grant := r.PostForm.Get("grant_type"); grant {
+	case "client_credentials":
+	case "":
+		oauthError(w, http.StatusBadRequest, "invalid_request", "grant_type is required")
+		return
+	default:
+		oauthError(w, http.StatusBadRequest, "unsupported_grant_type", "only client_credentials is supported")
+		return
+	}
+
+	clientID, secret := clientCredentials(r)
+	SetAuditResource(r.Context(), "service_account", clientID)
+	sa, err := s.authenticate(r.Context(), clientID, secret)
+	if errors.Is(err, errInvalidClient) {
+		Logger(r.Context()).Warn("client authentication failed", "client_id", clientID)
+		oauthError(w, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+		return
+	}
+	if err != nil {
+		Logger(r.Context()).Error("checking client credentials failed", "error", err)
+		oauthError(w, http.StatusServiceUnavailable, "temporarily_unavailable", "client credentials could not be checked")
+		return
+	}
+
+	scopes := sa.Scopes
+	if requested := strings.Fields(r.PostForm.Get("scope")); len(requested) > 0 {
+		if scopes, err = parseScopes(requested); err != nil {
+			oauthError(w, http.StatusBadRequest, "invalid_scope", err.Error())
+			return
+		}
+		for _, scope := range scopes {
+			if !scopeGranted(sa.Scopes, scope) {
+				oauthError(w, http.StatusBadRequest, "invalid_scope", "scope "+scope+" is not granted to this client")
+				return
+			}
+		}
+	}
+
+	subject := serviceAccountSubjectPrefix + sa.ClientID
+	expires := time.Now().Add(serviceAccountTokenTTL)
+	id, err := s.sessions.Create(r.Context(), subject, r.UserAgent(), HTTPClientIP(r), expires)
+	if err != nil {
+		oauthError(w, http.StatusServiceUnavailable, "temporarily_unavailable", "token could not be issued")
+		return
+	}
+	scope := strings.Join(scopes, " ")
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{serviceAccountAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+		Scope: scope,
+	}).SignedString(s.key)
+	if err != nil {
+		oauthError(w, http.StatusInternalServerError, "server_error", "token could not be signed")
+		return
+	}
+
+	SetPrincipal(r.Context(), subject)
+	s.db.WithContext(r.Context()).Model(sa).Update("last_used_at", time.Now().UnixMilli())
+	Logger(r.Context()).Info("service account token issued", "scope", scope)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": tokenString,
+		"token_type":   "Bearer",
+		"expires_in":   int(serviceAccountTokenTTL.Seconds()),
+		"scope":        scope,
+	})
+}
+
+// Introspect is the RFC 7662 introspection endpoint, for resource servers
+// that want to check a token rather than verify it themselves. The caller
+// authenticates with client credentials or an access token of its own.
+// Invalid, expired and revoked tokens are all just inactive.
+func (s *ServiceAccounts) Introspect(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, "invalid_request", "malformed form body")
+		return
+	}
+	if !s.introspectionAllowed(r) {
+		oauthError(w, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+		return
+	}
+	tokenString := r.PostForm.Get("token")
+	if tokenString == "" {
+		oauthError(w, http.StatusBadRequest, "invalid_request", "token is required")
+		return
+	}
+
+	claims, err := verifyAccessToken(r.Context(), s.key, s.sessions, tokenString)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]bool{"active": false})
+		return
+	}
+	resp := map[string]interface{}{
+		"active":     true,
+		"token_type": "Bearer",
+		"sub":        claims.Subject,
+		"jti":        claims.ID,
+	}
+	if claims.IssuedAt != nil {
+		resp["iat"] = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		resp["exp"] = claims.ExpiresAt.Unix()
+	}
+	if isServiceAccountToken(claims) {
+		resp["aud"] = serviceAccountAudience
+		resp["client_id"] = strings.TrimPrefix(claims.Subject, serviceAccountSubjectPrefix)
+		resp["scope"] = claims.Scope
+	} else {
+		resp["username"] = claims.Subject
+		resp["roles"] = claims.Roles
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// introspectionAllowed checks the caller of Introspect.
+func (s *ServiceAccounts) introspectionAllowed(r *http.Request) bool {
+	if clientID, secret := clientCredentials(r); clientID != "" {
+		_, err := s.authenticate(r.Context(), clientID, secret)
+		return err == nil
+	}
+	tokenString, fromCookie := httpAccessToken(r)
+	if tokenString == "" || (fromCookie && !httpCSRFValid(r)) {
+		return false
+	}
+	_, err := verifyAccessToken(r.Context(), s.key, s.sessions, tokenString)
+	return err == nil
+}
+
+// Routes mounts the OAuth2 endpoints, which authenticate callers themselves.
+func (s *ServiceAccounts) Routes(r chi.Router) {
+	r.Post("/oauth/token", s.Token)
+	r.Post("/oauth/introspect", s.Introspect)
+}
+
+// serviceAccountView is a service account as the admin API shows it.
+type serviceAccountView struct {
+	ClientID   string   `json:"client_id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	CreatedBy  string   `json:"created_by"`
+	CreatedAt  int64    `json:"created_at_ms"`
+	LastUsedAt int64    `json:"last_used_at_ms"`
+	Disabled   bool     `json:"disabled"`
+}
+
+func serviceAccountToView(sa ServiceAccountModel) serviceAccountView {
+	return serviceAccountView{
+		ClientID:   sa.ClientID,
+		Name:       sa.Name,
+		Scopes:     sa.Scopes,
+		CreatedBy:  sa.CreatedBy,
+		CreatedAt:  sa.CreatedAt,
+		LastUsedAt: sa.LastUsedAt,
+		Disabled:   sa.Disabled,
+	}
+}
+
+// Create registers a service account from `{"name": ..., "scopes": [...]}`.
+// The client secret is only returned here.
+func (s *ServiceAccounts) Create(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	scopes, err := parseScopes(body.Scopes)
+	if err == nil && len(scopes) == 0 {
+		err = errors.New("at least one scope is required")
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	secret := clientSecretPrefix + hex.EncodeToString(raw[8:])
+	sa := ServiceAccountModel{
+		ID:         newID(),
+		ClientID:   clientIDPrefix + hex.EncodeToString(raw[:8]),
+		Name:       body.Name,
+		SecretHash: hashServiceToken(secret),
+		Scopes:     scopes,
+		CreatedBy:  actorFromContext(r.Context()),
+	}
+	SetAuditResource(r.Context(), "service_account", sa.ClientID)
+	if err := s.db.WithContext(r.Context()).Create(&sa).Error; err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	AuditChange(r.Context(), "scopes", nil, scopes)
+	Logger(r.Context()).Info("service account created", "client_id", sa.ClientID, "scopes", scopes)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"service_account": serviceAccountToView(sa),
+		"client_secret":   secret,
+	})
+}
+
+// List lists the service accounts, without their secrets.
+func (s *ServiceAccounts) List(w http.ResponseWriter, r *http.Request) {
+	var accounts []ServiceAccountModel
+	if err := s.db.WithContext(r.Context()).Order("created_at").Find(&accounts).Error; err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	views := make([]serviceAccountView, len(accounts))
+	for i, sa := range accounts {
+		views[i] = serviceAccountToView(sa)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"service_accounts": views})
+}
+
+// Disable stops a service account from getting tokens and revokes the
+// tokens it has.
+func (s *ServiceAccounts) Disable(w http.ResponseWriter, r *http.Request) {
+	clientID := chi.URLParam(r, "clientID")
+	SetAuditResource(r.Context(), "service_account", clientID)
+	result := s.db.WithContext(r.Context()).Model(&ServiceAccountModel{}).
+		Where("client_id = ? AND disabled = ?", clientID, false).Update("disabled", true)
+	if result.Error != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		http.Error(w, "Service account not found", http.StatusNotFound)
+		return
+	}
+	AuditChange(r.Context(), "disabled", false, true)
+	if _, err := s.sessions.RevokeAll(r.Context(), serviceAccountSubjectPrefix+clientID); err != nil {
+		Logger(r.Context()).Error("revoking service account tokens failed", "client_id", clientID, "error", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminRoutes mounts /service-accounts for admins.
+func (s *ServiceAccounts) AdminRoutes(r chi.Router) {
+	r.Post("/service-accounts", s.Create)
+	r.Get("/service-accounts", s.List)
+	r.Delete("/service-accounts/{clientID}", s.Disable)
+}
+
+// MeHandler describes the caller; it must run behind RequireJWT.
+func MeHandler(w http.ResponseWriter, r *http.Request) {
+	info := requestInfoFromContext(r.Context())
+	if info == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	me := map[string]interface{}{
+		"principal":  info.Principal,
+		"session_id": info.SessionID,
+	}
+	if clientID, ok := strings.CutPrefix(info.Principal, serviceAccountSubjectPrefix); ok {
+		me["kind"] = "service_account"
+		me["client_id"] = clientID
+		me["scopes"] = info.Scopes
+	} else {
+		me["kind"] = "user"
+		me["roles"] = info.Roles
+	}
+	writeJSON(w, http.StatusOK, me)
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	catalogpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/catalog/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	// ServiceTokenHeader carries a service token on ReportHealth calls.
+	ServiceTokenHeader = "x-service-token"
+
+	serviceTokenPrefix = "t15s_"
+	// serviceTokenTouchEvery limits how often last_used_at is written.
+	serviceTokenTouchEvery = time.Minute
+)
+
+func hashServiceToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateServiceToken issues a new token for a service. The secret is only
+// returned here.
+func (s *CatalogServerImpl) CreateServiceToken(ctx context.Context, req *catalogpb.CreateServiceTokenRequest) (*catalogpb.CreateServiceTokenResponse, error) {
+	if err := s.db.WithContext(ctx).First(&ServiceModel{}, "id = ?", req.ServiceId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "service %q not found", req.ServiceId)
+		}
+		return nil, err
+	}
+
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	secret := serviceTokenPrefix + hex.EncodeToString(raw)
+	m := ServiceTokenModel{
+		ID:        newID(),
+		ServiceID: req.ServiceId,
+		Name:      req.Name,
+		Prefix:    secret[:len(serviceTokenPrefix)+6],
+		TokenHash: hashServiceToken(secret),
+		CreatedBy: actorFromContext(ctx),
+	}
+	if err := s.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, err
+	}
+	return &catalogpb.CreateServiceTokenResponse{Token: serviceTokenToProto(m), Secret: secret}, nil
+}
+
+// ListServiceTokens lists the tokens of a service, without their secrets
+func (s *CatalogServerImpl) ListServiceTokens(ctx context.Context, req *catalogpb.ListServiceTokensRequest) (*catalogpb.ListServiceTokensResponse, error) {
+	var tokens []ServiceTokenModel
+	if err := s.db.WithContext(ctx).Where("service_id = ?", req.ServiceId).Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	resp := &catalogpb.ListServiceTokensResponse{}
+	for _, m := range tokens {
+		resp.Tokens = append(resp.Tokens, serviceTokenToProto(m))
+	}
+	return resp, nil
+}
+
+// RevokeServiceToken stops a token from being accepted
+func (s *CatalogServerImpl) RevokeServiceToken(ctx context.Context, req *catalogpb.RevokeServiceTokenRequest) (*catalogpb.RevokeServiceTokenResponse, error) {
+	result := s.db.WithContext(ctx).Model(&ServiceTokenModel{}).Where("id = ?", req.Id).Update("revoked", true)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "token %q not found", req.Id)
+	}
+	return &catalogpb.RevokeServiceTokenResponse{}, nil
+}
+
+func serviceTokenToProto(m ServiceTokenModel) *catalogpb.ServiceToken {
+	return &catalogpb.ServiceToken{
+		Id:          m.ID,
+		ServiceId:   m.ServiceID,
+		Name:        m.Name,
+		Prefix:      m.Prefix,
+		CreatedBy:   m.CreatedBy,
+		CreatedAtMs: m.CreatedAt,
+		LastUsedMs:  m.LastUsedAt,
+		Revoked:     m.Revoked,
+	}
+}
+
+// hasServiceToken reports whether a call carries a service token.
+func hasServiceToken(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return len(md.Get(ServiceTokenHeader)) > 0
+}
+
+// authenticateServiceToken resolves the service token in the call metadata
+// and records the calling service as the principal.
+func authenticateServiceToken(ctx context.Context, db *gorm.DB) (*ServiceTokenModel, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(ServiceTokenHeader)
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, ServiceTokenHeader+" not provided")
+	}
+
+	var m ServiceTokenModel
+	err := db.WithContext(ctx).Where("token_hash = ? AND revoked = ?", hashServiceToken(values[0]), false).Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Unauthenticated, "invalid service token")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "failed to check service token")
+	}
+
+	now := time.Now()
+	if now.Sub(time.UnixMilli(m.LastUsedAt)) > serviceTokenTouchEvery {
+		db.WithContext(ctx).Model(&m).Update("last_used_at", now.UnixMilli())
+	}
+	SetPrincipal(ctx, "service:"+m.ServiceID)
+	return &m, nil
+}
@@ -0,0 +1,315 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// sessionsRevokedKey is a sorted set of revoked session IDs scored by
+	// when their tokens expire, after which they are pruned.
+	sessionsRevokedKey = "sessions:revoked"
+	// sessionRevokedTopic + ID is the hub topic that ends a session's streams.
+	sessionRevokedTopic = "session-revoked:"
+	// sessionSeenInterval limits how often last-seen is written per session.
+	sessionSeenInterval = time.Minute
+	// sessionResyncInterval is how often revocations are copied from
+	// Postgres back into Redis, in case Redis lost them.
+	sessionResyncInterval = time.Minute
+)
+
+var (
+	errSessionRevoked  = errors.New("session revoked")
+	errSessionNotFound = errors.New("session not found")
+)
+
+// Sessions tracks the access tokens issued at login as sessions, so users
+// can see where they are logged in and revoke them. Every authenticated
+// call checks the revocation set in Redis, falling back to Postgres while
+// Redis is unavailable. If Redis comes back without its data, revoked
+// tokens are accepted until Run copies the revocations back, at most
+// sessionResyncInterval later; admin calls check Postgres instead and are
+// not exposed to that window. Tokens without a jti predate sessions and
+// are accepted until they expire.
+type Sessions struct {
+	db    *gorm.DB
+	redis *redis.Client
+	hub   *Hub
+}
+
+func NewSessions(db *gorm.DB, redisClient *redis.Client, hub *Hub) *Sessions {
+	return &Sessions{db: db, redis: redisClient, hub: hub}
+}
+
+// Create records a new session and returns its ID, used as the token's jti.
+func (s *Sessions) Create(ctx context.Context, username, userAgent, ip string, expires time.Time) (string, error) {
+	now := time.Now().UnixMilli()
+	// Expired sessions of the user are dropped as new ones start.
+	s.db.WithContext(ctx).Where("username = ? AND expires_at < ?", username, now).Delete(&SessionModel{})
+	sess := SessionModel{
+		ID:         newID(),
+		Username:   username,
+		UserAgent:  userAgent,
+		IP:         ip,
+		LastSeenAt: now,
+		ExpiresAt:  expires.UnixMilli(),
+	}
+	if err := s.db.WithContext(ctx).Create(&sess).Error; err != nil {
+		return "", err
+	}
+	return sess.ID, nil
+}
+
+// Check returns errSessionRevoked for a revoked session and notes that a
+// live one was seen.
+func (s *Sessions) Check(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	_, err := s.redis.ZScore(ctx, sessionsRevokedKey, id).Result()
+	switch {
+	case err == nil:
+		return errSessionRevoked
+	case errors.Is(err, redis.Nil):
+		s.touch(ctx, id)
+		return nil
+	}
+	return s.CheckStrict(ctx, id)
+}
+
+// CheckStrict is Check against Postgres alone, for privileged calls: it
+// sees revocations Redis has lost, and fails when Postgres cannot answer.
+func (s *Sessions) CheckStrict(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	var revoked int64
+	if err := s.db.WithContext(ctx).Model(&SessionModel{}).Where("id = ? AND revoked_at > 0", id).Count(&revoked).Error; err != nil {
+		Logger(ctx).Error("checking session failed", "error", err)
+		return errors.New("session could not be checked")
+	}
+	if revoked > 0 {
+		return errSessionRevoked
+	}
+	return nil
+}
+
+// touch updates last-seen, at most once a minute per session.
+func (s *Sessions) touch(ctx context.Context, id string) {
+	if ok, err := s.redis.SetNX(ctx, "session:seen:"+id, 1, sessionSeenInterval).Result(); err != nil || !ok {
+		return
+	}
+	go s.db.WithContext(context.WithoutCancel(ctx)).Model(&SessionModel{}).
+		Where("id = ?", id).Update("last_seen_at", time.Now().UnixMilli())
+}
+
+// List returns the live sessions of username, newest first.
+func (s *Sessions) List(ctx context.Context, username string) ([]SessionModel, error) {
+	var sessions []SessionModel
+	err := s.db.WithContext(ctx).
+		Where("username = ? AND revoked_at = 0 AND expires_at > ?", username, time.Now().UnixMilli()).
+		Order("created_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// revoke revokes the live sessions of username, all of them or only id.
+func (s *Sessions) revoke(ctx context.Context, username, id string) (int, error) {
+	q := s.db.WithContext(ctx).Where("username = ? AND revoked_at = 0 AND expires_at > ?", username, time.Now().UnixMilli())
+	if id != "" {
+		q = q.Where("id = ?", id)
+	}
+	var sessions []SessionModel
+	if err := q.Find(&sessions).Error; err != nil {
+		return 0, err
+	}
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, len(sessions))
+	for i, sess := range sessions {
+		ids[i] = sess.ID
+	}
+	by := PrincipalFromContext(ctx)
+	err := s.db.WithContext(ctx).Model(&SessionModel{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"revoked_at": time.Now().UnixMilli(), "revoked_by": by}).Error
+	if err != nil {
+		return 0, err
+	}
+	s.publish(ctx, sessions)
+	Logger(ctx).Info("sessions revoked", "username", username, "count", len(sessions))
+	return len(sessions), nil
+}
+
+// publish adds revoked sessions to the Redis set and ends their streams.
+func (s *Sessions) publish(ctx context.Context, sessions []SessionModel) {
+	members := make([]redis.Z, len(sessions))
+	for i, sess := range sessions {
+		members[i] = redis.Z{Score: float64(sess.ExpiresAt), Member: sess.ID}
+	}
+	if err := s.redis.ZAdd(ctx, sessionsRevokedKey, members...).Err(); err != nil {
+		Logger(ctx).Warn("adding revoked sessions to Redis failed, resync will retry", "error", err)
+	}
+	s.redis.ZRemRangeByScore(ctx, sessionsRevokedKey, "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	for _, sess := range sessions {
+		s.hub.Publish(ctx, sessionRevokedTopic+sess.ID, nil)
+	}
+}
+
+// Revoke revokes one session of username.
+func (s *Sessions) Revoke(ctx context.Context, username, id string) error {
+	n, err := s.revoke(ctx, username, id)
+	if err == nil && n == 0 {
+		return errSessionNotFound
+	}
+	return err
+}
+
+// RevokeAll revokes every session of username and returns how many.
+func (s *Sessions) RevokeAll(ctx context.Context, username string) (int, error) {
+	return s.revoke(ctx, username, "")
+}
+
+// Watch returns a context that is cancelled with errSessionRevoked when
+// session id is revoked on any instance, and a function to stop watching.
+func (s *Sessions) Watch(ctx context.Context, id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if id == "" {
+		return ctx, func() { cancel(nil) }
+	}
+	ch, unsubscribe := s.hub.Subscribe(sessionRevokedTopic + id)
+	go func() {
+		select {
+		case <-ch:
+			cancel(errSessionRevoked)
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		unsubscribe()
+		cancel(nil)
+	}
+}
+
+// Run copies unexpired revocations from Postgres into Redis every minute,
+// so revocations survive Redis restarts and writes that failed, until ctx
+// is done.
+func (s *Sessions) Run(ctx context.Context) {
+	ticker := time.NewTicker(sessionResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var sessions []SessionModel
+		err := s.db.WithContext(ctx).Select("id", "expires_at").
+			Where("revoked_at > 0 AND expires_at > ?", time.Now().UnixMilli()).Find(&sessions).Error
+		if err != nil || len(sessions) == 0 {
+			continue
+		}
+		members := make([]redis.Z, len(sessions))
+		for i, sess := range sessions {
+			members[i] = redis.Z{Score: float64(sess.ExpiresAt), Member: sess.ID}
+		}
+		s.redis.ZAdd(ctx, sessionsRevokedKey, members...)
+	}
+}
+
+// sessionView is a session as the API shows it.
+type sessionView struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IP         string `json:"ip"`
+	CreatedAt  int64  `json:"created_at_ms"`
+	LastSeenAt int64  `json:"last_seen_at_ms"`
+	ExpiresAt  int64  `json:"expires_at_ms"`
+	Current    bool   `json:"current"`
+}
+
+func (s *Sessions) writeList(w http.ResponseWriter, r *http.Request, username string) {
+	sessions, err := s.List(r.Context(), username)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	current := sessionIDFromContext(r.Context())
+	views := make([]sessionView, len(sessions))
+	for i, sess := range sessions {
+		views[i] = sessionView{
+			ID:         sess.ID,
+			UserAgent:  sess.UserAgent,
+			IP:         sess.IP,
+			CreatedAt:  sess.CreatedAt,
+			LastSeenAt: sess.LastSeenAt,
+			ExpiresAt:  sess.ExpiresAt,
+			Current:    sess.ID == current,
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"sessions": views})
+}
+
+func (s *Sessions) writeRevoked(w http.ResponseWriter, r *http.Request, username string) {
+	SetAuditResource(r.Context(), "user", username)
+	n, err := s.RevokeAll(r.Context(), username)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"revoked": n})
+}
+
+// Routes mounts the caller's own sessions; it must run behind RequireJWT.
+func (s *Sessions) Routes(r chi.Router) {
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		s.writeList(w, r, PrincipalFromContext(r.Context()))
+	})
+	r.Delete("/", func(w http.ResponseWriter, r *http.Request) {
+		s.writeRevoked(w, r, PrincipalFromContext(r.Context()))
+	})
+	r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		SetAuditResource(r.Context(), "session", id)
+		err := s.Revoke(r.Context(), PrincipalFromContext(r.Context()), id)
+		if errors.Is(err, errSessionNotFound) {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// RequireStrict is chi middleware, behind RequireJWT, that checks the
+// caller's session with CheckStrict. It guards the admin routes.
+func (s *Sessions) RequireStrict(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := s.CheckStrict(r.Context(), sessionIDFromContext(r.Context())); err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AdminRoutes mounts /users/{username}/sessions for admins.
+func (s *Sessions) AdminRoutes(r chi.Router) {
+	r.Get("/users/{username}/sessions", func(w http.ResponseWriter, r *http.Request) {
+		s.writeList(w, r, strings.ToLower(chi.URLParam(r, "username")))
+	})
+	r.Delete("/users/{username}/sessions", func(w http.ResponseWriter, r *http.Request) {
+		s.writeRevoked(w, r, strings.ToLower(chi.URLParam(r, "username")))
+	})
+}
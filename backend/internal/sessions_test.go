@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestSessionsCheck revokes a session and checks it under the ways Redis
+// can fail: down, where Postgres answers, and restarted empty, where only
+// CheckStrict sees the revocation until the resync.
+func TestSessionsCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		redis  func(mr *miniredis.Miniredis)
+		check  error
+		strict error
+	}{
+		{name: "redis up", check: errSessionRevoked, strict: errSessionRevoked},
+		{name: "redis down", redis: func(mr *miniredis.Miniredis) { mr.Close() }, check: errSessionRevoked, strict: errSessionRevoked},
+		{name: "redis lost data", redis: func(mr *miniredis.Miniredis) { mr.FlushAll() }, check: nil, strict: errSessionRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client, mr := newTestRedis(t)
+			s := NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client))
+			revoked, err := s.Create(ctx, "alice", "test", "", time.Now().Add(time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			live, _ := s.Create(ctx, "alice", "test", "", time.Now().Add(time.Hour))
+			if err := s.Revoke(ctx, "alice", revoked); err != nil {
+				t.Fatal(err)
+			}
+			if tt.redis != nil {
+				tt.redis(mr)
+			}
+			if err := s.Check(ctx, revoked); !errors.Is(err, tt.check) {
+				t.Errorf("Check of the revoked session = %v, want %v", err, tt.check)
+			}
+			if err := s.CheckStrict(ctx, revoked); !errors.Is(err, tt.strict) {
+				t.Errorf("CheckStrict of the revoked session = %v, want %v", err, tt.strict)
+			}
+			if err := s.Check(ctx, live); err != nil {
+				t.Errorf("Check of a live session = %v", err)
+			}
+			if err := s.CheckStrict(ctx, live); err != nil {
+				t.Errorf("CheckStrict of a live session = %v", err)
+			}
+		})
+	}
+}
+
+func TestSessionsCheckStrictFailsClosed(t *testing.T) {
+	client, _ := newTestRedis(t)
+	db := newTestDB(t, &SessionModel{})
+	s := NewSessions(db, client, NewHub(client))
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+	if err := s.CheckStrict(context.Background(), "s-1"); err == nil {
+		t.Error("CheckStrict succeeded without Postgres")
+	}
+}
+
+// TestAdminCallsCheckPostgres checks that once Redis has lost a revocation
+// the token still reads, but admin methods and routes refuse it.
+func TestAdminCallsCheckPostgres(t *testing.T) {
+	ctx := context.Background()
+	client, mr := newTestRedis(t)
+	s := NewSessions(newTestDB(t, &SessionModel{}), client, NewHub(client))
+	id, err := s.Create(ctx, "alice", "test", "", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := issueToken(testKey, id, "alice", []string{roleAdmin}, "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Revoke(ctx, "alice", id); err != nil {
+		t.Fatal(err)
+	}
+	mr.FlushAll()
+
+	interceptor := JWTInterceptor(testKey, s)
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	for method, want := range map[string]codes.Code{
+		"/catalog.v1.CatalogService/ListServices":  codes.OK,
+		"/catalog.v1.CatalogService/DeleteService": codes.Unauthenticated,
+		"/audit.v1.AuditService/ListAuditEvents":   codes.Unauthenticated,
+	} {
+		_, err := interceptor(metadata.NewIncomingContext(ctx, md), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		if status.Code(err) != want {
+			t.Errorf("%s: %v, want %v", method, err, want)
+		}
+	}
+
+	handler := RequireJWT(testKey, s)(s.RequireStrict(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+	req := httptest.NewRequest(http.MethodGet, "/admin/loglevel", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("admin route = %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	slopb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/slo/v1"
+)
+
+// burnWindows are the windows burn rates are reported for. The pairs used
+// for alerting follow the multi-window, multi-burn-rate scheme from the
+// Google SRE workbook: 1h/5m at 14.4x (2% of a 30-day budget in an hour)
+// and 6h/30m at 6x (5% in six hours).
+var burnWindows = []struct {
+	name string
+	d    time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+	{"1d", 24 * time.Hour},
+	{"3d", 72 * time.Hour},
+}
+
+const (
+	fastBurnThreshold = 14.4
+	slowBurnThreshold = 6
+)
+
+// checkCounts is the number of total and good checks of a service in a window.
+type checkCounts struct {
+	Total int64
+	Good  int64
+}
+
+// countChecks classifies every sample of the SLO's service since `since` as
+// good or bad. Availability SLOs read through HealthHistory, so windows
+// longer than the raw retention are answered from rollups. Latency SLOs
+// need each sample's latency and are only counted over raw samples.
+func countChecks(ctx context.Context, history *HealthHistory, slo SLOModel, since time.Time) (checkCounts, error) {
+	if slo.Kind != int32(slopb.SLOKind_SLO_KIND_LATENCY) {
+		buckets, _, err := history.Query(ctx, []string{slo.ServiceID}, since, time.Now(), 0, slo.ExcludeMaintenance)
+		var c checkCounts
+		for _, b := range buckets {
+			c.Total += b.Samples
+			c.Good += b.UpSamples
+		}
+		return c, err
+	}
+
+	q := history.db.WithContext(ctx).Model(&HealthMetricModel{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN latency_ms < ? THEN 1 ELSE 0 END), 0) AS good", slo.LatencyThresholdMs).
+		Where("service_id = ? AND timestamp >= ?", slo.ServiceID, since.UnixMilli())
+	if slo.ExcludeMaintenance {
+		q = q.Where("in_maintenance = ?", false)
+	}
+	var c checkCounts
+	err := q.Scan(&c).Error
+	return c, err
+}
+
+// ComputeSLOStatus evaluates attainment, remaining error budget and burn
+// rates of slo as of now.
+func ComputeSLOStatus(ctx context.Context, history *HealthHistory, slo SLOModel, now time.Time) (*slopb.SLOStatus, error) {
+	window := time.Duration(slo.WindowDays) * 24 * time.Hour
+	total, err := countChecks(ctx, history, slo, now.Add(-window))
+	if err != nil {
+		return nil, err
+	}
+
+	budget := 1 - slo.Target
+	st := &slopb.SLOStatus{
+		SloId:                slo.ID,
+		Attainment:           1,
+		ErrorBudgetRemaining: 1,
+		TotalChecks:          total.Total,
+		GoodChecks:           total.Good,
+		ComputedAtMs:         now.UnixMilli(),
+	}
+	if total.Total > 0 {
+		badRatio := float64(total.Total-total.Good) / float64(total.Total)
+		st.Attainment = 1 - badRatio
+		if budget > 0 {
+			st.ErrorBudgetRemaining = 1 - badRatio/budget
+		}
+	}
+
+	rates := map[string]float64{}
+	for _, w := range burnWindows {
+		c, err := countChecks(ctx, history, slo, now.Add(-w.d))
+		if err != nil {
+			return nil, err
+		}
+		rate := 0.0
+		if c.Total > 0 && budget > 0 {
+			rate = float64(c.Total-c.Good) / float64(c.Total) / budget
+		}
+		rates[w.name] = rate
+		st.BurnRates = append(st.BurnRates, &slopb.BurnRate{Window: w.name, Rate: rate})
+	}
+	st.FastBurn = rates["1h"] > fastBurnThreshold && rates["5m"] > fastBurnThreshold
+	st.SlowBurn = rates["6h"] > slowBurnThreshold && rates["30m"] > slowBurnThreshold
+	return st, nil
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	slopb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/slo/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type SLOServerImpl struct {
+	slopb.UnimplementedSLOServiceServer
+	db      *gorm.DB
+	history *HealthHistory
+}
+
+func NewSLOServer(db *gorm.DB, history *HealthHistory) *SLOServerImpl {
+	return &SLOServerImpl{db: db, history: history}
+}
+
+// CreateSLO validates and stores a new objective
+func (s *SLOServerImpl) CreateSLO(ctx context.Context, req *slopb.CreateSLORequest) (*slopb.CreateSLOResponse, error) {
+	p := req.Slo
+	if p == nil || p.ServiceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "slo.service_id is required")
+	}
+	if p.Target <= 0 || p.Target >= 1 {
+		return nil, status.Error(codes.InvalidArgument, "slo.target must be between 0 and 1 exclusive")
+	}
+	switch p.Kind {
+	case slopb.SLOKind_SLO_KIND_AVAILABILITY:
+	case slopb.SLOKind_SLO_KIND_LATENCY:
+		if p.LatencyThresholdMs <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "latency SLOs need latency_threshold_ms")
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "slo.kind is required")
+	}
+	if err := s.db.WithContext(ctx).First(&ServiceModel{}, "id = ?", p.ServiceId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "service %q not found", p.ServiceId)
+		}
+		return nil, err
+	}
+
+	m := SLOModel{
+		ID:                 newID(),
+		ServiceID:          p.ServiceId,
+		Name:               p.Name,
+		Kind:               int32(p.Kind),
+		Target:             p.Target,
+		LatencyThresholdMs: p.LatencyThresholdMs,
+		WindowDays:         p.WindowDays,
+		ExcludeMaintenance: p.ExcludeMaintenance,
+	}
+	if m.WindowDays <= 0 {
+		m.WindowDays = 30
+	}
+	if err := s.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, err
+	}
+	return &slopb.CreateSLOResponse{Slo: sloToProto(m)}, nil
+}
+
+// ListSLOs returns objectives, optionally for one service
+func (s *SLOServerImpl) ListSLOs(ctx context.Context, req *slopb.ListSLOsRequest) (*slopb.ListSLOsResponse, error) {
+	q := s.db.WithContext(ctx).Order("service_id, name")
+	if req.ServiceId != "" {
+		q = q.Where("service_id = ?", req.ServiceId)
+	}
+	var slos []SLOModel
+	if err := q.Find(&slos).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &slopb.ListSLOsResponse{}
+	for _, m := range slos {
+		resp.Slos = append(resp.Slos, sloToProto(m))
+	}
+	return resp, nil
+}
+
+// GetSLOStatus computes the current attainment and burn rates of one objective
+func (s *SLOServerImpl) GetSLOStatus(ctx context.Context, req *slopb.GetSLOStatusRequest) (*slopb.GetSLOStatusResponse, error) {
+	slo, err := s.find(ctx, req.SloId)
+	if err != nil {
+		return nil, err
+	}
+	st, err := ComputeSLOStatus(ctx, s.history, slo, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &slopb.GetSLOStatusResponse{Status: st}, nil
+}
+
+// WatchSLOStatus streams the status of one objective every interval
+func (s *SLOServerImpl) WatchSLOStatus(req *slopb.WatchSLOStatusRequest, stream slopb.SLOService_WatchSLOStatusServer) error {
+	ctx := stream.Context()
+	slo, err := s.find(ctx, req.SloId)
+	if err != nil {
+		return err
+	}
+	interval := time.Duration(req.IntervalSeconds) * time.Second
+	if interval < time.Second {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		st, err := ComputeSLOStatus(ctx, s.history, slo, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&slopb.WatchSLOStatusResponse{Status: st}); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SLOServerImpl) find(ctx context.Context, id string) (SLOModel, error) {
+	var slo SLOModel
+	if err := s.db.WithContext(ctx).First(&slo, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return slo, status.Errorf(codes.NotFound, "SLO %q not found", id)
+		}
+		return slo, err
+	}
+	return slo, nil
+}
+
+func sloToProto(m SLOModel) *slopb.SLO {
+	return &slopb.SLO{
+		Id:                 m.ID,
+		ServiceId:          m.ServiceID,
+		Name:               m.Name,
+		Kind:               slopb.SLOKind(m.Kind),
+		Target:             m.Target,
+		LatencyThresholdMs: m.LatencyThresholdMs,
+		WindowDays:         m.WindowDays,
+		ExcludeMaintenance: m.ExcludeMaintenance,
+	}
+}
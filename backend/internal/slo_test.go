@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	slopb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/slo/v1"
+)
+
+// sloSamples are n samples taken age ago, bad of which are down (or slow,
+// for latency SLOs).
+type sloSamples struct {
+	age         time.Duration
+	n, bad      int
+	maintenance bool
+}
+
+func TestComputeSLOStatus(t *testing.T) {
+	availability := SLOModel{ServiceID: "svc", Kind: int32(slopb.SLOKind_SLO_KIND_AVAILABILITY), Target: 0.99, WindowDays: 30}
+	latency := SLOModel{ServiceID: "svc", Kind: int32(slopb.SLOKind_SLO_KIND_LATENCY), Target: 0.95, WindowDays: 7, LatencyThresholdMs: 300}
+	excluding := availability
+	excluding.ExcludeMaintenance = true
+
+	tests := []struct {
+		name       string
+		slo        SLOModel
+		samples    []sloSamples
+		attainment float64
+		remaining  float64
+		rates      map[string]float64 // windows not listed are 0
+		fast, slow bool
+	}{
+		{name: "no data", slo: availability, attainment: 1, remaining: 1},
+		{name: "half the budget spent last week", slo: availability,
+			samples:    []sloSamples{{age: 7 * 24 * time.Hour, n: 1000, bad: 5}},
+			attainment: 0.995, remaining: 0.5},
+		{name: "budget overspent", slo: availability,
+			samples:    []sloSamples{{age: 10 * 24 * time.Hour, n: 100, bad: 2}},
+			attainment: 0.98, remaining: -1},
+		{name: "outside the window", slo: availability,
+			samples:    []sloSamples{{age: 31 * 24 * time.Hour, n: 100, bad: 100}},
+			attainment: 1, remaining: 1},
+		{name: "fast burn", slo: availability,
+			samples:    []sloSamples{{age: 2 * time.Minute, n: 100, bad: 20}},
+			attainment: 0.8, remaining: -19,
+			rates: map[string]float64{"5m": 20, "30m": 20, "1h": 20, "6h": 20, "1d": 20, "3d": 20},
+			fast:  true, slow: true},
+		{name: "short blip does not page", slo: availability,
+			samples:    []sloSamples{{age: 2 * time.Minute, n: 10, bad: 2}, {age: 50 * time.Minute, n: 990}},
+			attainment: 0.998, remaining: 0.8,
+			rates: map[string]float64{"5m": 20, "30m": 20, "1h": 0.2, "6h": 0.2, "1d": 0.2, "3d": 0.2}},
+		{name: "slow burn", slo: availability,
+			samples:    []sloSamples{{age: 20 * time.Minute, n: 100, bad: 7}, {age: 3 * time.Hour, n: 100, bad: 7}},
+			attainment: 0.93, remaining: -6,
+			rates: map[string]float64{"30m": 7, "1h": 7, "6h": 7, "1d": 7, "3d": 7},
+			slow:  true},
+		{name: "latency", slo: latency,
+			samples:    []sloSamples{{age: 20 * time.Hour, n: 100, bad: 10}},
+			attainment: 0.9, remaining: -1,
+			rates: map[string]float64{"1d": 2, "3d": 2}},
+		{name: "maintenance counted", slo: availability,
+			samples:    []sloSamples{{age: 2 * 24 * time.Hour, n: 10, bad: 10, maintenance: true}, {age: 2 * 24 * time.Hour, n: 90}},
+			attainment: 0.9, remaining: -9, rates: map[string]float64{"3d": 10}},
+		{name: "maintenance excluded", slo: excluding,
+			samples:    []sloSamples{{age: 2 * 24 * time.Hour, n: 10, bad: 10, maintenance: true}, {age: 2 * 24 * time.Hour, n: 90}},
+			attainment: 1, remaining: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &HealthMetricModel{})
+			now := time.Now()
+			var rows []HealthMetricModel
+			for _, s := range tt.samples {
+				for i := 0; i < s.n; i++ {
+					m := HealthMetricModel{ServiceID: "svc", Status: int32(healthpb.Status_STATUS_UP), LatencyMs: 100,
+						Timestamp: now.Add(-s.age).UnixMilli(), InMaintenance: s.maintenance}
+					if i < s.bad {
+						m.Status, m.LatencyMs = int32(healthpb.Status_STATUS_DOWN), 500
+					}
+					rows = append(rows, m)
+				}
+			}
+			if len(rows) > 0 {
+				if err := db.CreateInBatches(rows, 500).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			st, err := ComputeSLOStatus(context.Background(), NewHealthHistory(db, RetentionPolicy{}), tt.slo, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !approx(st.Attainment, tt.attainment) || !approx(st.ErrorBudgetRemaining, tt.remaining) {
+				t.Errorf("attainment %v, budget remaining %v; want %v, %v", st.Attainment, st.ErrorBudgetRemaining, tt.attainment, tt.remaining)
+			}
+			if len(st.BurnRates) != len(burnWindows) {
+				t.Fatalf("%d burn rates, want %d", len(st.BurnRates), len(burnWindows))
+			}
+			for _, r := range st.BurnRates {
+				if !approx(r.Rate, tt.rates[r.Window]) {
+					t.Errorf("burn rate over %s = %v, want %v", r.Window, r.Rate, tt.rates[r.Window])
+				}
+			}
+			if st.FastBurn != tt.fast || st.SlowBurn != tt.slow {
+				t.Errorf("fast burn %v, slow burn %v; want %v, %v", st.FastBurn, st.SlowBurn, tt.fast, tt.slow)
+			}
+		})
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
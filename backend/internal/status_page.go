@@ -0,0 +1,580 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	healthpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/health/v1"
+	incidentpb "github.com/Prof-Rosario-UCLA/team15/gen/go/proto/incident/v1"
+)
+
+const (
+	statusPageCacheKey = "status:page:v1"
+	statusPageCacheFor = 60 * time.Second
+	statusPageDays     = 90
+	// statusPageStaleAfter is how old the latest sample may be before a
+	// service is shown as unknown rather than with its last status.
+	statusPageStaleAfter = 5 * time.Minute
+	statusPageUpcoming   = 7 * 24 * time.Hour
+	statusFeedEntries    = 50
+	dayMs                = int64(24 * time.Hour / time.Millisecond)
+)
+
+// StatusPage serves the unauthenticated status page for services flagged as
+// public. Nothing about private services, incident notes or owners is shown.
+type StatusPage struct {
+	db          *gorm.DB
+	redis       *redis.Client
+	catalog     *CatalogCache
+	maintenance *MaintenanceSchedule
+	history     *HealthHistory
+}
+
+func NewStatusPage(db *gorm.DB, redisClient *redis.Client, catalog *CatalogCache, maintenance *MaintenanceSchedule, history *HealthHistory) *StatusPage {
+	return &StatusPage{db: db, redis: redisClient, catalog: catalog, maintenance: maintenance, history: history}
+}
+
+// Routes mounts the page, its JSON form and the status change feeds.
+func (s *StatusPage) Routes(r chi.Router) {
+	r.Get("/status", s.HTML)
+	r.Get("/status.json", s.JSON)
+	r.Get("/status/feed.atom", s.Atom)
+	r.Get("/status/feed.rss", s.RSS)
+}
+
+type statusSnapshot struct {
+	GeneratedAt time.Time               `json:"generated_at"`
+	Overall     string                  `json:"overall"`
+	Services    []statusPageService     `json:"services"`
+	Incidents   []statusPageIncident    `json:"incidents"`
+	Maintenance []statusPageMaintenance `json:"maintenance"`
+}
+
+type statusPageService struct {
+	ID     string          `json:"id"`
+	Name   string          `json:"name"`
+	Status string          `json:"status"`
+	Uptime float64         `json:"uptime_percent"` // over the days with data, -1 if none
+	Days   []statusPageDay `json:"days"`
+}
+
+type statusPageDay struct {
+	Date    string  `json:"date"`
+	Uptime  float64 `json:"uptime_percent"` // -1 when there were no samples
+	Samples int64   `json:"samples"`
+}
+
+type statusPageIncident struct {
+	Title     string    `json:"title"`
+	Severity  string    `json:"severity"`
+	State     string    `json:"state"`
+	Services  []string  `json:"services"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type statusPageMaintenance struct {
+	Title    string    `json:"title"`
+	Services []string  `json:"services"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Active   bool      `json:"active"`
+}
+
+// snapshot returns the page contents, cached in Redis so that a burst of
+// anonymous visitors costs one set of queries per statusPageCacheFor.
+func (s *StatusPage) snapshot(ctx context.Context) (*statusSnapshot, error) {
+	if raw, err := s.redis.Get(ctx, statusPageCacheKey).Bytes(); err == nil {
+		var snap statusSnapshot
+		if json.Unmarshal(raw, &snap) == nil {
+			return &snap, nil
+		}
+	}
+
+	snap, err := s.build(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := json.Marshal(snap)
+	s.redis.Set(ctx, statusPageCacheKey, raw, statusPageCacheFor)
+	return snap, nil
+}
+
+func (s *StatusPage) publicServices(ctx context.Context) ([]ServiceModel, error) {
+	all, err := s.catalog.Services(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var public []ServiceModel
+	for _, svc := range all {
+		if svc.Public {
+			public = append(public, svc)
+		}
+	}
+	sort.Slice(public, func(i, j int) bool { return public[i].Name < public[j].Name })
+	return public, nil
+}
+
+func (s *StatusPage) build(ctx context.Context, now time.Time) (*statusSnapshot, error) {
+	services, err := s.publicServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap := &statusSnapshot{GeneratedAt: now.UTC(), Overall: "operational"}
+	if len(services) == 0 {
+		return snap, nil
+	}
+	ids := make([]string, len(services))
+	names := make(map[string]string, len(services))
+	for i, svc := range services {
+		ids[i] = svc.ID
+		names[svc.ID] = svc.Name
+	}
+
+	uptime, err := s.dailyUptime(ctx, ids, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		ps := statusPageService{ID: svc.ID, Name: svc.Name, Status: "unknown"}
+		if m, ok := latestHealthMetric(ctx, s.db, s.redis, svc.ID); ok && now.Sub(time.UnixMilli(m.Timestamp)) < statusPageStaleAfter {
+			ps.Status = statusName(m.Status)
+		}
+		if s.maintenance.Active(ctx, svc, now) != nil {
+			ps.Status = "maintenance"
+		}
+
+		var up, total int64
+		firstDay := now.UnixMilli()/dayMs - statusPageDays + 1
+		for d := int64(0); d < statusPageDays; d++ {
+			day := statusPageDay{Date: time.UnixMilli((firstDay + d) * dayMs).UTC().Format("2006-01-02"), Uptime: -1}
+			if c, ok := uptime[svc.ID][firstDay+d]; ok && c.Total > 0 {
+				day.Samples = c.Total
+				day.Uptime = 100 * float64(c.Up) / float64(c.Total)
+				up += c.Up
+				total += c.Total
+			}
+			ps.Days = append(ps.Days, day)
+		}
+		ps.Uptime = -1
+		if total > 0 {
+			ps.Uptime = 100 * float64(up) / float64(total)
+		}
+		switch {
+		case ps.Status == "down":
+			snap.Overall = "outage"
+		case ps.Status == "degraded" && snap.Overall == "operational":
+			snap.Overall = "degraded"
+		}
+		snap.Services = append(snap.Services, ps)
+	}
+
+	if snap.Incidents, err = s.activeIncidents(ctx, ids, names); err != nil {
+		return nil, err
+	}
+	if len(snap.Incidents) > 0 && snap.Overall != "outage" {
+		snap.Overall = "incident"
+	}
+	snap.Maintenance = s.scheduledMaintenance(ctx, services, now)
+	return snap, nil
+}
+
+type uptimeCount struct {
+	Up    int64
+	Total int64
+}
+
+// dailyUptime counts samples per service and UTC day, normally from the
+// daily rollups. Samples taken during maintenance are left out rather than
+// counted as downtime.
+func (s *StatusPage) dailyUptime(ctx context.Context, ids []string, now time.Time) (map[string]map[int64]uptimeCount, error) {
+	since := time.UnixMilli((now.UnixMilli()/dayMs - statusPageDays + 1) * dayMs)
+	buckets, _, err := s.history.Query(ctx, ids, since, now, 24*time.Hour, true)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]map[int64]uptimeCount)
+	for _, b := range buckets {
+		if out[b.ServiceID] == nil {
+			out[b.ServiceID] = make(map[int64]uptimeCount)
+		}
+		out[b.ServiceID][b.Start/dayMs] = uptimeCount{Up: b.UpSamples, Total: b.Samples}
+	}
+	return out, nil
+}
+
+// activeIncidents lists unresolved incidents touching at least one public
+// service. Only public service names are listed.
+func (s *StatusPage) activeIncidents(ctx context.Context, ids []string, names map[string]string) ([]statusPageIncident, error) {
+	var links []IncidentServiceModel
+	if err := s.db.WithContext(ctx).Where("service_id IN ?", ids).Find(&links).Error; err != nil {
+		return nil, err
+	}
+	affected := make(map[string][]string)
+	for _, l := range links {
+		affected[l.IncidentID] = append(affected[l.IncidentID], names[l.ServiceID])
+	}
+	if len(affected) == 0 {
+		return nil, nil
+	}
+	incidentIDs := make([]string, 0, len(affected))
+	for id := range affected {
+		incidentIDs = append(incidentIDs, id)
+	}
+
+	var incidents []IncidentModel
+	err := s.db.WithContext(ctx).
+		Where("id IN ? AND state <> ?", incidentIDs, int32(incidentpb.IncidentState_INCIDENT_STATE_RESOLVED)).
+		Order("created_at desc").
+		Find(&incidents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]statusPageIncident, 0, len(incidents))
+	for _, inc := range incidents {
+		svcs := affected[inc.ID]
+		sort.Strings(svcs)
+		out = append(out, statusPageIncident{
+			Title:     inc.Title,
+			Severity:  enumName(incidentpb.Severity(inc.Severity).String(), "SEVERITY_"),
+			State:     enumName(incidentpb.IncidentState(inc.State).String(), "INCIDENT_STATE_"),
+			Services:  svcs,
+			StartedAt: time.UnixMilli(inc.CreatedAt).UTC(),
+			UpdatedAt: time.UnixMilli(inc.UpdatedAt).UTC(),
+		})
+	}
+	return out, nil
+}
+
+// scheduledMaintenance lists windows covering public services that are in
+// progress or start within statusPageUpcoming.
+func (s *StatusPage) scheduledMaintenance(ctx context.Context, services []ServiceModel, now time.Time) []statusPageMaintenance {
+	var out []statusPageMaintenance
+	for _, w := range s.maintenance.current(ctx) {
+		if w.Cancelled {
+			continue
+		}
+		start, end, ok := w.Occurrence(now)
+		if !ok || start.After(now.Add(statusPageUpcoming)) {
+			continue
+		}
+		var covered []string
+		for _, svc := range services {
+			if w.Covers(svc) {
+				covered = append(covered, svc.Name)
+			}
+		}
+		if len(covered) == 0 {
+			continue
+		}
+		out = append(out, statusPageMaintenance{
+			Title:    w.Title,
+			Services: covered,
+			StartsAt: start,
+			EndsAt:   end,
+			Active:   !start.After(now),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartsAt.Before(out[j].StartsAt) })
+	return out
+}
+
+func statusName(status int32) string {
+	switch healthpb.Status(status) {
+	case healthpb.Status_STATUS_UP:
+		return "up"
+	case healthpb.Status_STATUS_DOWN:
+		return "down"
+	case healthpb.Status_STATUS_DEGRADED:
+		return "degraded"
+	case healthpb.Status_STATUS_MAINTENANCE:
+		return "maintenance"
+	default:
+		return "unknown"
+	}
+}
+
+// enumName turns e.g. INCIDENT_STATE_OPEN into "open".
+func enumName(s, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(s, prefix))
+}
+
+// JSON serves the status page as JSON.
+func (s *StatusPage) JSON(w http.ResponseWriter, r *http.Request) {
+	snap, err := s.snapshot(r.Context())
+	if err != nil {
+		Logger(r.Context()).Error("failed to build status page", "error", err)
+		http.Error(w, "status unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusPageCacheFor.Seconds())))
+	json.NewEncoder(w).Encode(snap)
+}
+
+var statusPageFuncs = template.FuncMap{
+	"uptimeClass": func(pct float64) string {
+		switch {
+		case pct < 0:
+			return "none"
+		case pct >= 99.9:
+			return "good"
+		case pct >= 95:
+			return "partial"
+		default:
+			return "bad"
+		}
+	},
+	"pct": func(pct float64) string {
+		if pct < 0 {
+			return "no data"
+		}
+		return fmt.Sprintf("%.2f%%", pct)
+	},
+	"when": func(t time.Time) string { return t.Format("Jan 2 15:04 MST") },
+}
+
+var statusPageTemplate = template.Must(template.New("status").Funcs(statusPageFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Service status</title>
+<link rel="alternate" type="application/atom+xml" title="Status changes" href="/status/feed.atom">
+<link rel="alternate" type="application/rss+xml" title="Status changes" href="/status/feed.rss">
+<style>
+body { font-family: system-ui, sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; color: #222; }
+.banner { padding: 1rem; border-radius: 6px; color: #fff; font-weight: 600; }
+.operational { background: #2e7d32; } .incident, .banner.degraded { background: #ef6c00; } .outage { background: #c62828; }
+.service { margin: 1.5rem 0; }
+.service h3 { display: flex; justify-content: space-between; margin: 0 0 .4rem; font-size: 1rem; }
+.up { color: #2e7d32; } .down { color: #c62828; } .degraded { color: #ef6c00; } .unknown { color: #757575; } .maintenance { color: #1565c0; }
+.bars { display: flex; gap: 2px; height: 32px; }
+.bars span { flex: 1; border-radius: 2px; }
+.good { background: #43a047; } .partial { background: #fbc02d; } .bad { background: #e53935; } .none { background: #e0e0e0; }
+.meta { font-size: .8rem; color: #757575; }
+</style>
+</head>
+<body>
+<h1>Service status</h1>
+<div class="banner {{.Overall}}">
+{{- if eq .Overall "operational"}}All systems operational{{else if eq .Overall "incident"}}Some services are affected by an incident{{else if eq .Overall "degraded"}}Some services are degraded{{else}}Some services are down{{end -}}
+</div>
+
+{{if .Incidents}}<h2>Active incidents</h2>
+{{range .Incidents}}<div class="service"><h3>{{.Title}} <span>{{.State}}</span></h3>
+<div class="meta">{{.Severity}} &middot; affects {{range $i, $s := .Services}}{{if $i}}, {{end}}{{$s}}{{end}} &middot; started {{when .StartedAt}} &middot; updated {{when .UpdatedAt}}</div></div>
+{{end}}{{end}}
+
+{{if .Maintenance}}<h2>Scheduled maintenance</h2>
+{{range .Maintenance}}<div class="service"><h3>{{.Title}} <span>{{if .Active}}in progress{{else}}scheduled{{end}}</span></h3>
+<div class="meta">{{range $i, $s := .Services}}{{if $i}}, {{end}}{{$s}}{{end}} &middot; {{when .StartsAt}} &ndash; {{when .EndsAt}}</div></div>
+{{end}}{{end}}
+
+<h2>Services</h2>
+{{range .Services}}<div class="service">
+<h3>{{.Name}} <span class="{{.Status}}">{{.Status}}</span></h3>
+<div class="bars">{{range .Days}}<span class="{{uptimeClass .Uptime}}" title="{{.Date}}: {{pct .Uptime}}"></span>{{end}}</div>
+<div class="meta">90 days &middot; {{pct .Uptime}} uptime</div>
+</div>
+{{else}}<p>No public services.</p>
+{{end}}
+<p class="meta">Updated {{when .GeneratedAt}} &middot; <a href="/status.json">JSON</a> &middot; <a href="/status/feed.atom">Atom</a> &middot; <a href="/status/feed.rss">RSS</a></p>
+</body>
+</html>
+`))
+
+// HTML renders the status page.
+func (s *StatusPage) HTML(w http.ResponseWriter, r *http.Request) {
+	snap, err := s.snapshot(r.Context())
+	if err != nil {
+		Logger(r.Context()).Error("failed to build status page", "error", err)
+		http.Error(w, "status unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusPageCacheFor.Seconds())))
+	if err := statusPageTemplate.Execute(w, snap); err != nil {
+		Logger(r.Context()).Warn("failed to render status page", "error", err)
+	}
+}
+
+type statusFeedEntry struct {
+	ID      string
+	Title   string
+	At      time.Time
+	Summary string
+}
+
+// feedEntries returns the most recent status changes of public services.
+func (s *StatusPage) feedEntries(ctx context.Context) ([]statusFeedEntry, error) {
+	services, err := s.publicServices(ctx)
+	if err != nil || len(services) == 0 {
+		return nil, err
+	}
+	ids := make([]string, len(services))
+	names := make(map[string]string, len(services))
+	for i, svc := range services {
+		ids[i] = svc.ID
+		names[svc.ID] = svc.Name
+	}
+
+	var changes []StatusChangeModel
+	err = s.db.WithContext(ctx).
+		Where("service_id IN ?", ids).
+		Order("at desc").
+		Limit(statusFeedEntries).
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]statusFeedEntry, len(changes))
+	for i, c := range changes {
+		name := names[c.ServiceID]
+		entries[i] = statusFeedEntry{
+			ID:      fmt.Sprintf("status-change-%d", c.ID),
+			Title:   fmt.Sprintf("%s is %s", name, statusName(c.ToStatus)),
+			At:      time.UnixMilli(c.At).UTC(),
+			Summary: fmt.Sprintf("%s changed from %s to %s.", name, statusName(c.FromStatus), statusName(c.ToStatus)),
+		}
+	}
+	return entries, nil
+}
+
+// baseURL is the externally visible origin of the request, as seen through Envoy.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if p := r.Header.Get("X-Forwarded-Proto"); p != "" {
+		scheme = p
+	}
+	return scheme + "://" + r.Host
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary"`
+}
+
+// Atom serves the status change feed as Atom 1.0.
+func (s *StatusPage) Atom(w http.ResponseWriter, r *http.Request) {
+	entries, err := s.feedEntries(r.Context())
+	if err != nil {
+		Logger(r.Context()).Error("failed to build status feed", "error", err)
+		http.Error(w, "status unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	base := baseURL(r)
+	feed := atomFeed{
+		Title:   "Service status changes",
+		ID:      base + "/status",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: base + "/status/feed.atom", Rel: "self"},
+			{Href: base + "/status"},
+		},
+	}
+	if len(entries) > 0 {
+		feed.Updated = entries[0].At.Format(time.RFC3339)
+	}
+	for _, e := range entries {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   e.Title,
+			ID:      base + "/status#" + e.ID,
+			Updated: e.At.Format(time.RFC3339),
+			Link:    atomLink{Href: base + "/status"},
+			Summary: e.Summary,
+		})
+	}
+	writeXML(w, r, "application/atom+xml; charset=utf-8", feed)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+// RSS serves the status change feed as RSS 2.0.
+func (s *StatusPage) RSS(w http.ResponseWriter, r *http.Request) {
+	entries, err := s.feedEntries(r.Context())
+	if err != nil {
+		Logger(r.Context()).Error("failed to build status feed", "error", err)
+		http.Error(w, "status unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	base := baseURL(r)
+	feed := rssFeed{Version: "2.0", Channel: rssChannel{
+		Title:       "Service status changes",
+		Link:        base + "/status",
+		Description: "Status changes of public services",
+	}}
+	for _, e := range entries {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        base + "/status",
+			GUID:        base + "/status#" + e.ID,
+			PubDate:     e.At.Format(time.RFC1123Z),
+			Description: e.Summary,
+		})
+	}
+	writeXML(w, r, "application/rss+xml; charset=utf-8", feed)
+}
+
+func writeXML(w http.ResponseWriter, r *http.Request, contentType string, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusPageCacheFor.Seconds())))
+	w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		Logger(r.Context()).Warn("failed to write feed", "error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP as authenticator apps implement it (RFC 6238): HMAC-SHA1, six
+// digits, 30 second steps.
+const (
+	totpIssuer = "team15"
+	totpDigits = 6
+	totpPeriod = 30
+	// totpSkew is how many steps either side of now are accepted, for
+	// clocks that drift.
+	totpSkew = 1
+
+	recoveryCodeCount = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newTOTPSecret returns a random 160-bit secret in base32, as entered into
+// authenticator apps.
+func newTOTPSecret() string {
+	b := make([]byte, 20)
+	rand.Read(b)
+	return totpEncoding.EncodeToString(b)
+}
+
+// totpURI is the otpauth:// provisioning URI shown as a QR code.
+func totpURI(account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", totpIssuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + url.PathEscape(totpIssuer+":"+account) + "?" + q.Encode()
+}
+
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	off := sum[len(sum)-1] & 0x0f
+	n := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, n%1_000_000)
+}
+
+// verifyTOTP checks code against secret at now and returns the step it
+// matched. Only steps after last are accepted, so a code cannot be replayed.
+func verifyTOTP(secret, code string, now time.Time, last int64) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	code = strings.TrimSpace(code)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	step := now.Unix() / totpPeriod
+	for s := step - totpSkew; s <= step+totpSkew; s++ {
+		if s > last && hmac.Equal([]byte(totpCode(key, s)), []byte(code)) {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes returns single-use codes to show the user once, and the
+// hashes to store.
+func newRecoveryCodes() (codes, hashes []string) {
+	for range recoveryCodeCount {
+		b := make([]byte, 5)
+		rand.Read(b)
+		code := strings.ToLower(totpEncoding.EncodeToString(b))
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes
+}
+
+// hashRecoveryCode ignores case, spaces and dashes, which users mistype.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Key is the SHA1 seed from the test vectors in RFC 6238 appendix B.
+var rfc6238Key = []byte("12345678901234567890")
+
+func TestTOTPCode(t *testing.T) {
+	// The RFC lists eight-digit codes; six-digit ones are their last six
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	for _, tt := range tests {
+		if got := totpCode(rfc6238Key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("%d: code %s, want %s", tt.unix, got, tt.want)
+		}
+		now := time.Unix(tt.unix, 0)
+		if step, ok := verifyTOTP(strings.ToLower(secret), " "+tt.want+" ", now, 0); !ok || step != tt.unix/totpPeriod {
+			t.Errorf("%d: verifyTOTP = %d, %v", tt.unix, step, ok)
+		}
+	}
+}
+
+func TestVerifyTOTPWindow(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	now := time.Unix(1234567890, 0)
+	step := now.Unix() / totpPeriod
+	tests := []struct {
+		name   string
+		offset int64 // steps from now that the code was made for
+		last   int64
+		want   bool
+	}{
+		{name: "current", want: true},
+		{name: "one step behind", offset: -1, want: true},
+		{name: "one step ahead", offset: 1, want: true},
+		{name: "two steps behind", offset: -2},
+		{name: "two steps ahead", offset: 2},
+		{name: "already used", last: step},
+		{name: "older than the last used", offset: -1, last: step - 1},
+		{name: "newer than the last used", offset: 1, last: step, want: true},
+	}
+	for _, tt := range tests {
+		code := totpCode(rfc6238Key, step+tt.offset)
+		got, ok := verifyTOTP(secret, code, now, tt.last)
+		if ok != tt.want {
+			t.Errorf("%s: accepted %v, want %v", tt.name, ok, tt.want)
+		}
+		if ok && got != step+tt.offset {
+			t.Errorf("%s: matched step %d, want %d", tt.name, got, step+tt.offset)
+		}
+	}
+
+	if _, ok := verifyTOTP("not base32!", totpCode(rfc6238Key, step), now, 0); ok {
+		t.Error("accepted a code for a malformed secret")
+	}
+	if _, ok := verifyTOTP(secret, "12345", now, 0); ok {
+		t.Error("accepted a five-digit code")
+	}
+}
+
+func TestUseSecondFactorOnce(t *testing.T) {
+	a, _ := newTestAccounts(t, nil)
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	codes, hashes := newRecoveryCodes()
+	u := newTestUser(t, a, UserModel{Username: "alice", MFAEnabled: true, TOTPSecret: secret, RecoveryCodes: hashes})
+	totp := totpCode(rfc6238Key, time.Now().Unix()/totpPeriod)
+
+	steps := []struct {
+		name string
+		req  mfaRequest
+		ok   bool
+	}{
+		{"recovery code", mfaRequest{RecoveryCode: codes[0]}, true},
+		{"same recovery code again", mfaRequest{RecoveryCode: codes[0]}, false},
+		{"recovery code as typed", mfaRequest{RecoveryCode: strings.ToUpper(strings.ReplaceAll(codes[1], "-", " "))}, true},
+		{"unknown recovery code", mfaRequest{RecoveryCode: "aaaa-aaaa"}, false},
+		{"TOTP code", mfaRequest{Code: totp}, true},
+		{"same TOTP code again", mfaRequest{Code: totp}, false},
+	}
+	for _, s := range steps {
+		err := a.useSecondFactor(context.Background(), u.ID, s.req)
+		if s.ok && err != nil {
+			t.Errorf("%s: %v", s.name, err)
+		}
+		if !s.ok && !errors.Is(err, errSecondFactor) {
+			t.Errorf("%s: error %v, want errSecondFactor", s.name, err)
+		}
+	}
+
+	var stored UserModel
+	if err := a.db.Take(&stored, "id = ?", u.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n := len(stored.RecoveryCodes); n != recoveryCodeCount-2 {
+		t.Errorf("%d recovery codes left, want %d", n, recoveryCodeCount-2)
+	}
+}